@@ -1,17 +1,36 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"gastro-galaxy-back/internal/server"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
 
-	server := server.NewServer()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		if err := seed(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	fmt.Println("Sever running at port", server.Addr)
-	err := server.ListenAndServe()
-	if err != nil {
+	if err := server.Run(ctx); err != nil {
 		panic(fmt.Sprintf("cannot start server: %s", err))
 	}
 }
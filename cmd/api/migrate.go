@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate [up | down [steps] | version]"
+
+// migrate runs the migrate subcommand against the database configured by
+// the DB_* variables.
+func migrate(args []string) error {
+	ctx := context.Background()
+
+	db := database.New()
+	defer db.Close()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		return db.Migrate(ctx)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("steps must be a positive integer\n%s", migrateUsage)
+			}
+		}
+		return db.MigrateDown(ctx, steps)
+
+	case "version":
+		version, err := db.SchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Println(version)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+}
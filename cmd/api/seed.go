@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/fixtures"
+)
+
+const seedUsage = "usage: seed [fixture.yaml | fixture.json]"
+
+// seed loads a fixture file, or the demo catalog when none is given, into
+// the database configured by the DB_* variables.
+func seed(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments\n%s", seedUsage)
+	}
+
+	fixture, err := fixtures.Demo()
+	if len(args) == 1 {
+		fixture, err = fixtures.ReadFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	db := database.New()
+	defer db.Close()
+
+	if err := db.Migrate(ctx); err != nil {
+		return err
+	}
+
+	ids, err := fixtures.Load(ctx, db, fixture)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("loaded %d categories, %d ingredients and %d recipes\n", len(ids.Categories), len(ids.Ingredients), len(ids.Recipes))
+	return nil
+}
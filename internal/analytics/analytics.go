@@ -0,0 +1,176 @@
+// Package analytics collects anonymous usage events: which API routes and
+// frontend pages are used, and what people search for. Nothing that
+// identifies a person is recorded, clients that send DNT or Sec-GPC are
+// left out entirely and events are deleted after the retention period.
+package analytics
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	bufferSize      = 1024
+	batchSize       = 200
+	flushInterval   = 10 * time.Second
+	cleanupInterval = 24 * time.Hour
+	maxNameLength   = 100
+)
+
+// Sink buffers events in memory and writes them to the database in batches,
+// so recording one never slows a request down. Events are dropped when the
+// buffer is full. A nil *Sink records nothing.
+type Sink struct {
+	db        database.Service
+	events    chan models.AnalyticsEvent
+	retention time.Duration
+}
+
+// New returns a Sink writing to db that keeps events for retention.
+func New(db database.Service, retention time.Duration) *Sink {
+	return &Sink{
+		db:        db,
+		events:    make(chan models.AnalyticsEvent, bufferSize),
+		retention: retention,
+	}
+}
+
+// Record adds an event for r, unless the client opted out.
+func (s *Sink) Record(r *http.Request, kind string, name string) {
+	if s == nil || OptedOut(r) || name == "" {
+		return
+	}
+
+	select {
+	case s.events <- models.AnalyticsEvent{OccurredAt: time.Now().UTC(), Kind: kind, Name: truncate(name, maxNameLength)}:
+	default:
+	}
+}
+
+// Middleware records every request to a known route, by its pattern.
+func (s *Sink) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if ctx := chi.RouteContext(r.Context()); ctx != nil {
+			s.Record(r, models.AnalyticsKindAPI, r.Method+" "+ctx.RoutePattern())
+		}
+	})
+}
+
+// Run writes the buffered events and applies the retention policy until
+// ctx is cancelled.
+func (s *Sink) Run(ctx context.Context) {
+	flush := time.NewTicker(flushInterval)
+	defer flush.Stop()
+
+	cleanup := time.NewTicker(cleanupInterval)
+	defer cleanup.Stop()
+
+	s.deleteExpired(ctx)
+
+	batch := make([]models.AnalyticsEvent, 0, batchSize)
+
+	write := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.db.InsertAnalyticsEvents(ctx, batch); err != nil {
+			log.Printf("analytics: cannot store %d events: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Keep what is buffered, with a deadline of its own since ctx
+			// is gone.
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			defer cancel()
+			for len(s.events) > 0 && len(batch) < cap(batch) {
+				batch = append(batch, <-s.events)
+			}
+			write(ctx)
+			return
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) == batchSize {
+				write(ctx)
+			}
+		case <-flush.C:
+			write(ctx)
+		case <-cleanup.C:
+			s.deleteExpired(ctx)
+		}
+	}
+}
+
+func (s *Sink) deleteExpired(ctx context.Context) {
+	deleted, err := s.db.DeleteAnalyticsEventsBefore(ctx, time.Now().Add(-s.retention))
+
+	if err != nil {
+		log.Printf("analytics: cannot delete expired events: %v", err)
+		return
+	}
+
+	if deleted > 0 {
+		log.Printf("analytics: deleted %d expired events", deleted)
+	}
+}
+
+// OptedOut reports whether the client asked not to be tracked, with the
+// Do Not Track or Global Privacy Control header.
+func OptedOut(r *http.Request) bool {
+	return r.Header.Get("DNT") == "1" || r.Header.Get("Sec-GPC") == "1"
+}
+
+// ScrubSearchTerm normalizes a search for storage and drops the words that
+// may identify someone: anything with an @ and anything with four or more
+// digits, such as phone or document numbers. ok is false when nothing is
+// left.
+func ScrubSearchTerm(term string) (scrubbed string, ok bool) {
+	var words []string
+
+	for _, word := range strings.Fields(strings.ToLower(term)) {
+		digits := 0
+		for _, r := range word {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+
+		if strings.Contains(word, "@") || digits >= 4 {
+			continue
+		}
+
+		words = append(words, word)
+	}
+
+	scrubbed = truncate(strings.Join(words, " "), maxNameLength)
+	return scrubbed, scrubbed != ""
+}
+
+// ScrubPath keeps only the path of a frontend URL, dropping the query and
+// fragment, which may carry personal data.
+func ScrubPath(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	path, _, _ = strings.Cut(path, "#")
+	return truncate(path, maxNameLength)
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
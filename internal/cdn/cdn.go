@@ -0,0 +1,136 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"gastro-galaxy-back/internal/requestid"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+	_ "github.com/joho/godotenv/autoload"
+)
+
+// Purger removes cached copies of URLs from a CDN.
+type Purger interface {
+	// Purge removes every cached URL that starts with one of prefixes, so
+	// the variants of a page with a query string go along with it.
+	Purge(ctx context.Context, prefixes []string) error
+}
+
+var (
+	provider           = os.Getenv("CDN_PROVIDER")
+	cloudflareZoneId   = os.Getenv("CLOUDFLARE_ZONE_ID")
+	cloudflareApiToken = os.Getenv("CLOUDFLARE_API_TOKEN")
+	cloudfrontDistId   = os.Getenv("CLOUDFRONT_DISTRIBUTION_ID")
+)
+
+// New returns the Purger selected by CDN_PROVIDER ("cloudflare" or
+// "cloudfront"), or nil when no CDN is configured.
+func New(ctx context.Context) (Purger, error) {
+	switch provider {
+	case "":
+		return nil, nil
+	case "cloudflare":
+		return &cloudflare{
+			zoneId: cloudflareZoneId,
+			token:  cloudflareApiToken,
+			client: &http.Client{Timeout: 10 * time.Second, Transport: &requestid.Transport{}},
+		}, nil
+	case "cloudfront":
+		cfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &cloudFront{
+			distributionId: cloudfrontDistId,
+			client: cloudfront.NewFromConfig(cfg, func(o *cloudfront.Options) {
+				o.HTTPClient = &http.Client{Transport: &requestid.Transport{}}
+			}),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown CDN provider %q", provider)
+	}
+}
+
+type cloudflare struct {
+	zoneId string
+	token  string
+	client *http.Client
+}
+
+// Purge purges by prefix, which Cloudflare expects without the scheme.
+func (c *cloudflare) Purge(ctx context.Context, prefixes []string) error {
+	hostPaths := make([]string, 0, len(prefixes))
+	for _, raw := range prefixes {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		hostPaths = append(hostPaths, u.Host+u.EscapedPath())
+	}
+
+	body, err := json.Marshal(map[string][]string{"prefixes": hostPaths})
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/purge_cache", c.zoneId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("cloudflare purge failed: %s", resp.Status)
+	}
+	return nil
+}
+
+type cloudFront struct {
+	distributionId string
+	client         *cloudfront.Client
+}
+
+// Purge creates an invalidation for the paths of prefixes followed by a
+// wildcard. CloudFront invalidates by path, so the scheme and host are
+// dropped.
+func (c *cloudFront) Purge(ctx context.Context, prefixes []string) error {
+	paths := make([]string, 0, len(prefixes))
+	for _, raw := range prefixes {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, u.EscapedPath()+"*")
+	}
+
+	_, err := c.client.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(c.distributionId),
+		InvalidationBatch: &types.InvalidationBatch{
+			CallerReference: aws.String(strconv.FormatInt(time.Now().UnixNano(), 10)),
+			Paths: &types.Paths{
+				Quantity: aws.Int32(int32(len(paths))),
+				Items:    paths,
+			},
+		},
+	})
+	return err
+}
@@ -0,0 +1,222 @@
+// Package classifier proposes tags for recipes, such as spicy, vegetarian or
+// dessert, from their ingredients and texts. Proposals go to a review queue
+// for an admin to accept or reject; nothing is tagged automatically.
+package classifier
+
+import (
+	"context"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const batchSize = 100
+
+// Words are matched on folded text: lower case, without accents, and with
+// anything but letters turned into spaces, so "Dedo-de-moça" reads
+// "dedo de moca". A word also matches its plural.
+var (
+	spicyWords = []string{
+		"malagueta", "dedo de moca", "pimenta calabresa", "pimenta caiena", "caiena", "cayenne",
+		"jalapeno", "habanero", "chili", "chilli", "sriracha", "wasabi", "harissa", "gochujang",
+		"picante", "apimentado", "apimentada",
+	}
+
+	meatWords = []string{
+		"carne", "frango", "galinha", "peru", "bacon", "presunto", "linguica", "salsicha", "salame",
+		"mortadela", "chourico", "toucinho", "torresmo", "costela", "picanha", "alcatra", "patinho",
+		"file mignon", "lombo", "pernil", "porco", "cordeiro", "peixe", "atum", "salmao", "bacalhau",
+		"sardinha", "camarao", "lula", "polvo", "marisco", "anchova", "gelatina",
+		"beef", "chicken", "pork", "ham", "fish", "shrimp", "tuna", "salmon", "sausage", "gelatin",
+	}
+
+	dessertCategories = []string{"sobremesa", "doce", "dessert", "confeitaria"}
+
+	dessertWords = []string{
+		"bolo", "pudim", "brigadeiro", "beijinho", "mousse", "sorvete", "brownie", "cookie", "pave",
+		"quindim", "cocada", "cheesecake", "tiramisu", "sobremesa", "cake", "dessert",
+	}
+
+	sweetWords = []string{
+		"acucar", "chocolate", "leite condensado", "doce de leite", "cacau", "mel", "baunilha",
+		"goiabada", "chantilly", "granulado", "sugar", "honey", "vanilla",
+	}
+)
+
+// minSweetIngredients is how many sweet ingredients make a recipe a
+// dessert when nothing else says so.
+const minSweetIngredients = 2
+
+// Classify returns the tags recipe seems to deserve.
+func Classify(recipe models.ClassifiableRecipe) []models.TagProposal {
+	var proposals []models.TagProposal
+
+	text := fold(recipe.Name + " " + recipe.Description + " " + recipe.LongDescription)
+	ingredients := make([]string, len(recipe.Ingredients))
+	for i, name := range recipe.Ingredients {
+		ingredients[i] = fold(name)
+	}
+
+	if i, word := firstMatch(ingredients, spicyWords); word != "" {
+		proposals = append(proposals, models.TagProposal{Tag: models.TagSpicy, Reason: fmt.Sprintf("ingredient %q is hot", recipe.Ingredients[i])})
+	} else if word := match(text, spicyWords); word != "" {
+		proposals = append(proposals, models.TagProposal{Tag: models.TagSpicy, Reason: fmt.Sprintf("its description says %q", word)})
+	}
+
+	_, meat := firstMatch(ingredients, meatWords)
+
+	if meat == "" && len(ingredients) > 0 {
+		proposals = append(proposals, models.TagProposal{Tag: models.TagVegetarian, Reason: fmt.Sprintf("none of its %d ingredients is meat or fish", len(ingredients))})
+	}
+
+	if word := match(fold(recipe.Category), dessertCategories); word != "" {
+		proposals = append(proposals, models.TagProposal{Tag: models.TagDessert, Reason: fmt.Sprintf("it is in the %q category", recipe.Category)})
+	} else if word := match(text, dessertWords); word != "" {
+		proposals = append(proposals, models.TagProposal{Tag: models.TagDessert, Reason: fmt.Sprintf("its name or description says %q", word)})
+	} else if sweet := countMatches(ingredients, sweetWords); sweet >= minSweetIngredients && meat == "" {
+		proposals = append(proposals, models.TagProposal{Tag: models.TagDessert, Reason: fmt.Sprintf("%d of its ingredients are sweet", sweet)})
+	}
+
+	return proposals
+}
+
+// fold lowers text, strips its accents and keeps only letters, separated
+// by single spaces and padded with one on each side.
+func fold(text string) string {
+	var b strings.Builder
+	b.WriteByte(' ')
+
+	space := true
+	for _, r := range strings.ToLower(text) {
+		if folded, ok := accents[r]; ok {
+			r = folded
+		}
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+			space = false
+		} else if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+
+	if !space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+var accents = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// match returns the first of words found in the folded text, or "".
+func match(text string, words []string) string {
+	for _, word := range words {
+		for _, form := range []string{word, word + "s", word + "es"} {
+			if strings.Contains(text, " "+form+" ") {
+				return word
+			}
+		}
+	}
+	return ""
+}
+
+// firstMatch returns the index of the first of texts containing one of
+// words, and the word.
+func firstMatch(texts []string, words []string) (int, string) {
+	for i, text := range texts {
+		if word := match(text, words); word != "" {
+			return i, word
+		}
+	}
+	return -1, ""
+}
+
+func countMatches(texts []string, words []string) int {
+	count := 0
+	for _, text := range texts {
+		if match(text, words) != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// Job classifies the whole catalog every interval.
+type Job struct {
+	db       database.Service
+	interval time.Duration
+}
+
+// New creates a Job that classifies the recipes stored in db every
+// interval.
+func New(db database.Service, interval time.Duration) *Job {
+	return &Job{db: db, interval: interval}
+}
+
+// Run classifies the recipes right away and then every interval, until ctx
+// is cancelled.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.ClassifyAll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("classifier: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ClassifyAll classifies every recipe once and queues the tags not proposed
+// before.
+func (j *Job) ClassifyAll(ctx context.Context) error {
+	afterId, queued := 0, 0
+
+	for {
+		recipes, err := j.db.GetRecipesToClassify(ctx, afterId, batchSize)
+
+		if err != nil {
+			return err
+		}
+
+		if len(recipes) == 0 {
+			break
+		}
+
+		for _, recipe := range recipes {
+			proposals := Classify(recipe)
+
+			if len(proposals) > 0 {
+				added, err := j.db.InsertTagSuggestions(ctx, recipe.Id, proposals)
+				if err != nil {
+					return err
+				}
+				queued += added
+			}
+
+			afterId = recipe.Id
+		}
+	}
+
+	if queued > 0 {
+		log.Printf("classifier: queued %d tag suggestions for review", queued)
+	}
+
+	return nil
+}
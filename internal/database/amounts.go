@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/quantity"
+	"log"
+)
+
+// convertLegacyAmounts fills the quantity and unit of the ingredients that
+// only have a free text amount, when the amount can be read. Amounts it
+// can't read, such as "a gosto", are left as they are.
+func (s *service) convertLegacyAmounts(ctx context.Context) error {
+
+	rows, err := s.db.Query(ctx, `SELECT id, amount FROM ingredient WHERE quantity IS NULL AND amount IS NOT NULL`)
+
+	if err != nil {
+		return err
+	}
+
+	type conversion struct {
+		id     int
+		amount quantity.Amount
+	}
+
+	var conversions []conversion
+
+	for rows.Next() {
+		var id int
+		var amount string
+		if err := rows.Scan(&id, &amount); err != nil {
+			rows.Close()
+			return err
+		}
+		if parsed, ok := quantity.Parse(amount); ok {
+			conversions = append(conversions, conversion{id, parsed})
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range conversions {
+		stmt := `UPDATE ingredient SET quantity = $2, unit = $3 WHERE id = $1 AND quantity IS NULL`
+		if _, err := s.db.Exec(ctx, stmt, c.id, c.amount.Quantity, string(c.amount.Unit)); err != nil {
+			return err
+		}
+	}
+
+	if len(conversions) > 0 {
+		log.Printf("converted %d ingredient amounts to a quantity and unit", len(conversions))
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AnalyticsRepository keeps the usage events and their counts.
+type AnalyticsRepository interface {
+	InsertAnalyticsEvents(ctx context.Context, events []models.AnalyticsEvent) error
+	DeleteAnalyticsEventsBefore(ctx context.Context, before time.Time) (int64, error)
+	GetAnalyticsCounts(ctx context.Context, kind string, since time.Time, limit int) ([]models.AnalyticsCount, error)
+}
+
+// InsertAnalyticsEvents stores a batch of usage events.
+func (s *service) InsertAnalyticsEvents(ctx context.Context, events []models.AnalyticsEvent) error {
+
+	_, err := s.db.CopyFrom(ctx, pgx.Identifier{"analytics_event"}, []string{"occurred_at", "kind", "name"},
+		pgx.CopyFromSlice(len(events), func(i int) ([]any, error) {
+			return []any{events[i].OccurredAt, events[i].Kind, events[i].Name}, nil
+		}))
+
+	return err
+}
+
+// DeleteAnalyticsEventsBefore removes the events older than before and
+// returns how many were removed.
+func (s *service) DeleteAnalyticsEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM analytics_event WHERE occurred_at < $1`, before)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetAnalyticsCounts returns the limit most frequent events of kind since
+// since, most frequent first.
+func (s *service) GetAnalyticsCounts(ctx context.Context, kind string, since time.Time, limit int) ([]models.AnalyticsCount, error) {
+
+	query := `
+		SELECT name, COUNT(*)
+		FROM analytics_event
+		WHERE kind = $1 AND occurred_at >= $2
+		GROUP BY name
+		ORDER BY COUNT(*) DESC, name
+		LIMIT $3
+	`
+
+	rows, err := s.db.Query(ctx, query, kind, since, limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.AnalyticsCount{}
+
+	for rows.Next() {
+		var count models.AnalyticsCount
+		if err := rows.Scan(&count.Name, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+)
+
+// SetIngredientsAvailability marks the ingredients in ids as available or
+// not, or flips each one's current availability when available is nil. It
+// returns the updated ingredients ordered by id. When any id does not exist
+// nothing is changed and ErrNotFound is returned.
+func (s *service) SetIngredientsAvailability(ctx context.Context, ids []int, available *bool) ([]models.Ingedient, error) {
+
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	stmt := `
+		UPDATE ingredient i
+		SET isavailable = COALESCE($2, NOT COALESCE(isavailable, false))
+		WHERE id = ANY($1)
+		RETURNING ` + ingredientColumns
+
+	rows, err := tx.Query(ctx, stmt, ids, available)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []models.Ingedient
+
+	for rows.Next() {
+		var ingredient models.Ingedient
+		if err := scanIngredient(rows, &ingredient); err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ingredients) != len(ids) {
+		return nil, ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(ingredients, func(a, b models.Ingedient) int { return a.Id - b.Id })
+
+	return ingredients, nil
+}
@@ -0,0 +1,267 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CatalogRepository exports and imports the whole catalogue: categories,
+// ingredients and recipes, matched by uuid.
+type CatalogRepository interface {
+	ExportCatalog(ctx context.Context, visit models.CatalogVisitor) error
+	ImportCatalog(ctx context.Context, catalog models.Catalog, overwrite bool) (models.ImportSummary, error)
+}
+
+// ExportCatalog walks every category, ingredient and recipe for an export.
+// The rows come from a single snapshot, so the recipes never refer to rows
+// created after the ingredients were read. Unlike the list methods it is
+// not capped by MAX_LIST_ROWS.
+func (s *service) ExportCatalog(ctx context.Context, visit models.CatalogVisitor) error {
+
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT c.id, c.uuid, c.name FROM category c ORDER BY c.id`)
+
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.Id, &category.Uuid, &category.Name); err != nil {
+			rows.Close()
+			return err
+		}
+		if err := visit.Category(category); err != nil {
+			rows.Close()
+			return err
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = tx.Query(ctx, ingredientsQuery+` ORDER BY i.id`)
+
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var ingredient models.Ingedient
+		if err := scanIngredient(rows, &ingredient); err != nil {
+			rows.Close()
+			return err
+		}
+		if err := visit.Ingredient(ingredient); err != nil {
+			rows.Close()
+			return err
+		}
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT r.id, r.uuid, r.category_id, r.name, r.imageUrl, r.description, r.long_description, c.uuid::text,
+			COALESCE(array_agg(i.uuid::text ORDER BY ir.id) FILTER (WHERE i.id IS NOT NULL), '{}')
+		FROM recipe r
+		LEFT JOIN category c ON c.id = r.category_id
+		LEFT JOIN ingredient_recipe ir ON ir.recipe_id = r.id
+		LEFT JOIN ingredient i ON i.id = ir.ingredient_id
+		GROUP BY r.id, c.uuid
+		ORDER BY r.id
+	`
+
+	rows, err = tx.Query(ctx, query)
+
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipe models.CatalogRecipe
+		if err := rows.Scan(&recipe.Recipe.Id, &recipe.Recipe.Uuid, &recipe.Recipe.CategoryId, &recipe.Recipe.Name, &recipe.Recipe.Url, &recipe.Recipe.Description, &recipe.Recipe.LongDescription, &recipe.CategoryUuid, &recipe.IngredientUuids); err != nil {
+			return err
+		}
+		if err := visit.Recipe(recipe); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ImportCatalog loads the rows of an export in one transaction. Rows are
+// matched by uuid: new ones are created, existing ones are left alone, or
+// replaced with their ingredient list when overwrite is set. References to
+// uuids neither in the catalog nor in the database fail the whole import
+// with ErrUnknownReference.
+func (s *service) ImportCatalog(ctx context.Context, catalog models.Catalog, overwrite bool) (models.ImportSummary, error) {
+
+	var summary models.ImportSummary
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return summary, err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, category := range catalog.Categories {
+		_, outcome, err := importRow(ctx, tx, "category", category.Uuid, overwrite,
+			`INSERT INTO category (uuid, name) VALUES($1,$2)`,
+			`UPDATE category SET name = $2 WHERE uuid = $1`,
+			category.Uuid, category.Name)
+
+		if err != nil {
+			return summary, fmt.Errorf("category %s: %w", category.Uuid, err)
+		}
+		outcome.count(&summary.Categories)
+	}
+
+	for _, ingredient := range catalog.Ingredients {
+		args := append([]any{ingredient.Uuid, ingredient.Name, ingredient.Amount, ingredient.Url, ingredient.IsAvailable, ingredient.Quantity, ingredient.Unit}, nutritionArgs(ingredient.Nutrition)...)
+
+		_, outcome, err := importRow(ctx, tx, "ingredient", ingredient.Uuid, overwrite,
+			`INSERT INTO ingredient (uuid, name, amount, imageurl, isavailable, quantity, unit, calories, protein, fat, carbs) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+			`UPDATE ingredient SET name = $2, amount = $3, imageurl = $4, isavailable = $5, quantity = $6, unit = $7, calories = $8, protein = $9, fat = $10, carbs = $11 WHERE uuid = $1`,
+			args...)
+
+		if err != nil {
+			return summary, fmt.Errorf("ingredient %s: %w", ingredient.Uuid, err)
+		}
+		outcome.count(&summary.Ingredients)
+	}
+
+	for _, recipe := range catalog.Recipes {
+		var categoryId *int
+
+		if recipe.CategoryUuid != nil {
+			id, err := idByUUIDTx(ctx, tx, "category", *recipe.CategoryUuid)
+			if err != nil {
+				return summary, fmt.Errorf("recipe %s: category %s: %w", recipe.Recipe.Uuid, *recipe.CategoryUuid, err)
+			}
+			categoryId = &id
+		}
+
+		ingredientIds := make([]int, len(recipe.IngredientUuids))
+
+		for i, ingredientUuid := range recipe.IngredientUuids {
+			id, err := idByUUIDTx(ctx, tx, "ingredient", ingredientUuid)
+			if err != nil {
+				return summary, fmt.Errorf("recipe %s: ingredient %s: %w", recipe.Recipe.Uuid, ingredientUuid, err)
+			}
+			ingredientIds[i] = id
+		}
+
+		id, outcome, err := importRow(ctx, tx, "recipe", recipe.Recipe.Uuid, overwrite,
+			`INSERT INTO recipe (uuid, name, description, long_description, imageurl, category_id) VALUES($1,$2,$3,$4,$5,$6)`,
+			`UPDATE recipe SET name = $2, description = $3, long_description = $4, imageurl = $5, category_id = $6 WHERE uuid = $1`,
+			recipe.Recipe.Uuid, recipe.Recipe.Name, recipe.Recipe.Description, recipe.Recipe.LongDescription, recipe.Recipe.Url, categoryId)
+
+		if err != nil {
+			return summary, fmt.Errorf("recipe %s: %w", recipe.Recipe.Uuid, err)
+		}
+		outcome.count(&summary.Recipes)
+
+		if outcome == importSkipped {
+			continue
+		}
+
+		if outcome == importUpdated {
+			if _, err := tx.Exec(ctx, `DELETE FROM ingredient_recipe WHERE recipe_id = $1`, id); err != nil {
+				return summary, err
+			}
+		}
+
+		if err := insertRecipeIngredients(ctx, tx, id, ingredientIds); err != nil {
+			return summary, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return summary, err
+	}
+
+	return summary, nil
+}
+
+type importOutcome int
+
+const (
+	importCreated importOutcome = iota
+	importUpdated
+	importSkipped
+)
+
+func (o importOutcome) count(counts *models.ImportCounts) {
+	switch o {
+	case importCreated:
+		counts.Created++
+	case importUpdated:
+		counts.Updated++
+	case importSkipped:
+		counts.Skipped++
+	}
+}
+
+// importRow inserts a row unless one with its uuid exists, which is then
+// updated when overwrite is set. insert and update take the same
+// arguments, the first being the uuid.
+func importRow(ctx context.Context, tx pgx.Tx, table string, uuid string, overwrite bool, insert string, update string, args ...any) (int, importOutcome, error) {
+
+	id, err := idByUUIDTx(ctx, tx, table, uuid)
+
+	if errors.Is(err, ErrUnknownReference) {
+		err = tx.QueryRow(ctx, insert+` RETURNING id`, args...).Scan(&id)
+		return id, importCreated, err
+	}
+
+	if err != nil {
+		return -1, importSkipped, err
+	}
+
+	if !overwrite {
+		return id, importSkipped, nil
+	}
+
+	if _, err := tx.Exec(ctx, update, args...); err != nil {
+		return -1, importUpdated, err
+	}
+
+	return id, importUpdated, nil
+}
+
+// idByUUIDTx returns the id of the row of table with uuid, or
+// ErrUnknownReference. table is one of the catalog tables, never input.
+func idByUUIDTx(ctx context.Context, tx pgx.Tx, table string, uuid string) (int, error) {
+
+	var id int
+
+	err := tx.QueryRow(ctx, `SELECT id FROM `+table+` WHERE uuid = $1`, uuid).Scan(&id)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return -1, ErrUnknownReference
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
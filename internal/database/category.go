@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+)
+
+// CategoryRepository reads and writes the recipe categories.
+type CategoryRepository interface {
+	InsertCategory(ctx context.Context, name string) (int, error)
+	GetCategories(ctx context.Context) ([]models.Category, error)
+	UpdateCategory(ctx context.Context, id int, name string) error
+	DeleteCategory(ctx context.Context, id int) error
+	CategoryIdByUUID(ctx context.Context, id string) (int, error)
+}
+
+func (s *service) InsertCategory(ctx context.Context, name string) (int, error) {
+
+	stmt := `INSERT INTO category (uuid, name) VALUES($1,$2) RETURNING id`
+
+	var id int
+
+	err := s.db.QueryRow(ctx, stmt, newUUID(), name).Scan(&id)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (s *service) GetCategories(ctx context.Context) ([]models.Category, error) {
+
+	rows, err := s.db.Query(ctx, `SELECT c.id, c.uuid, c.name FROM category c ORDER BY c.name`)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.Category{}
+
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.Id, &category.Uuid, &category.Name); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *service) UpdateCategory(ctx context.Context, id int, name string) error {
+
+	tag, err := s.db.Exec(ctx, `UPDATE category SET name = $2 WHERE id = $1`, id, name)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteCategory removes a category. It returns ErrInUse while recipes
+// still belong to it.
+func (s *service) DeleteCategory(ctx context.Context, id int) error {
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM category WHERE id = $1`, id)
+
+	if isForeignKeyViolation(err) {
+		return ErrInUse
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
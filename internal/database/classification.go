@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ClassificationRepository feeds recipes to the classifier and keeps the
+// tags it proposes until they are reviewed.
+type ClassificationRepository interface {
+	GetRecipesToClassify(ctx context.Context, afterId int, limit int) ([]models.ClassifiableRecipe, error)
+	InsertTagSuggestions(ctx context.Context, recipeId int, proposals []models.TagProposal) (int, error)
+	GetTagSuggestions(ctx context.Context, status string) ([]models.TagSuggestion, error)
+	ReviewTagSuggestion(ctx context.Context, id int, status string) error
+}
+
+// GetRecipesToClassify returns up to limit recipes with an id above afterId,
+// in id order, with their category and ingredient names.
+func (s *service) GetRecipesToClassify(ctx context.Context, afterId int, limit int) ([]models.ClassifiableRecipe, error) {
+
+	query := `
+		SELECT r.id, r.name, COALESCE(r.description, ''), COALESCE(r.long_description, ''), COALESCE(c.name, ''),
+			COALESCE(array_agg(i.name ORDER BY ir.id) FILTER (WHERE i.id IS NOT NULL), '{}')
+		FROM recipe r
+		LEFT JOIN category c ON c.id = r.category_id
+		LEFT JOIN ingredient_recipe ir ON ir.recipe_id = r.id
+		LEFT JOIN ingredient i ON i.id = ir.ingredient_id
+		WHERE r.id > $1
+		GROUP BY r.id, c.name
+		ORDER BY r.id
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(ctx, query, afterId, limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []models.ClassifiableRecipe{}
+
+	for rows.Next() {
+		var recipe models.ClassifiableRecipe
+		if err := rows.Scan(&recipe.Id, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Category, &recipe.Ingredients); err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, rows.Err()
+}
+
+// InsertTagSuggestions queues the proposed tags of a recipe for review,
+// skipping the ones proposed before whatever their status. It returns how
+// many were queued.
+func (s *service) InsertTagSuggestions(ctx context.Context, recipeId int, proposals []models.TagProposal) (int, error) {
+
+	stmt := `INSERT INTO tag_suggestion (recipe_id, tag, reason) VALUES ($1, $2, $3) ON CONFLICT (recipe_id, tag) DO NOTHING`
+
+	queued := 0
+
+	for _, proposal := range proposals {
+		tag, err := s.db.Exec(ctx, stmt, recipeId, proposal.Tag, proposal.Reason)
+
+		if isForeignKeyViolation(err) {
+			// The recipe was deleted since it was read.
+			return queued, nil
+		}
+
+		if err != nil {
+			return queued, err
+		}
+
+		queued += int(tag.RowsAffected())
+	}
+
+	return queued, nil
+}
+
+// GetTagSuggestions returns the tag suggestions with status, oldest first.
+func (s *service) GetTagSuggestions(ctx context.Context, status string) ([]models.TagSuggestion, error) {
+
+	query := `
+		SELECT t.id, t.recipe_id, r.name, t.tag, t.reason, t.status, t.created_at, t.reviewed_at
+		FROM tag_suggestion t
+		JOIN recipe r ON r.id = t.recipe_id
+		WHERE t.status = $1
+		ORDER BY t.created_at, t.id
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(ctx, query, status, MaxListRows)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []models.TagSuggestion{}
+
+	for rows.Next() {
+		var suggestion models.TagSuggestion
+		if err := rows.Scan(&suggestion.Id, &suggestion.RecipeId, &suggestion.RecipeName, &suggestion.Tag, &suggestion.Reason, &suggestion.Status, &suggestion.CreatedAt, &suggestion.ReviewedAt); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// ReviewTagSuggestion sets the moderation status of a tag suggestion.
+// Accepting it adds the tag to its recipe.
+func (s *service) ReviewTagSuggestion(ctx context.Context, id int, status string) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var recipeId int
+	var tag string
+
+	stmt := `UPDATE tag_suggestion SET status = $2, reviewed_at = now() WHERE id = $1 RETURNING recipe_id, tag`
+
+	err = tx.QueryRow(ctx, stmt, id, status).Scan(&recipeId, &tag)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if status == models.SuggestionAccepted {
+		if err := attachTags(ctx, tx, recipeId, models.NormalizeTags([]string{tag})); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+)
+
+// GetCookableRecipes returns the recipes lacking at most maxMissing of their
+// ingredients, fewest missing first. The ingredients on hand are
+// ingredientIds, or the ones marked as available when ingredientIds is nil.
+// Any ingredient with the same base counts, so canned tomatoes on hand
+// cover a recipe asking for cherry tomatoes. Recipes without ingredients
+// are left out.
+func (s *service) GetCookableRecipes(ctx context.Context, ingredientIds []int, maxMissing int) ([]models.CookableRecipe, error) {
+
+	query := `
+		SELECT r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id,
+			COALESCE(array_agg(ri.ingredient_id ORDER BY ri.ingredient_id) FILTER (WHERE NOT ri.on_hand), '{}')
+		FROM recipe r
+		JOIN (
+			SELECT DISTINCT ir.recipe_id, ir.ingredient_id,
+				EXISTS (
+					SELECT 1 FROM ingredient h
+					WHERE COALESCE(h.base_id, h.id) = COALESCE(i.base_id, i.id)
+						AND CASE WHEN $1::int[] IS NULL THEN COALESCE(h.isavailable, false) ELSE h.id = ANY($1) END
+				) AS on_hand
+			FROM ingredient_recipe ir
+			JOIN ingredient i ON i.id = ir.ingredient_id
+		) ri ON ri.recipe_id = r.id
+		GROUP BY r.id
+		HAVING COUNT(*) FILTER (WHERE NOT ri.on_hand) <= $2
+		ORDER BY COUNT(*) FILTER (WHERE NOT ri.on_hand), r.id
+		LIMIT $3
+	`
+
+	rows, err := s.db.Query(ctx, query, ingredientIds, maxMissing, MaxListRows)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []models.CookableRecipe{}
+
+	for rows.Next() {
+		var recipe models.CookableRecipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId, &recipe.MissingIngredientIds); err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, rows.Err()
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"gastro-galaxy-back/internal/models"
+)
+
+// CustomFieldRepository keeps the custom fields of recipes and their JSON
+// Schemas.
+type CustomFieldRepository interface {
+	GetCustomFields(ctx context.Context) ([]models.CustomField, error)
+	SetCustomField(ctx context.Context, name string, schema json.RawMessage) (models.CustomField, error)
+	DeleteCustomField(ctx context.Context, name string) error
+}
+
+func (s *service) GetCustomFields(ctx context.Context) ([]models.CustomField, error) {
+
+	rows, err := s.db.Query(ctx, `SELECT name, schema, updated_at FROM custom_field ORDER BY name`)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []models.CustomField{}
+
+	for rows.Next() {
+		var field models.CustomField
+		if err := rows.Scan(&field.Name, &field.Schema, &field.UpdatedAt); err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, rows.Err()
+}
+
+// SetCustomField defines a custom field, or replaces the schema of an
+// existing one. Values already stored are not checked against the new
+// schema.
+func (s *service) SetCustomField(ctx context.Context, name string, schema json.RawMessage) (models.CustomField, error) {
+
+	stmt := `
+		INSERT INTO custom_field (name, schema) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET schema = EXCLUDED.schema, updated_at = now()
+		RETURNING name, schema, updated_at
+	`
+
+	var field models.CustomField
+
+	err := s.db.QueryRow(ctx, stmt, name, schema).Scan(&field.Name, &field.Schema, &field.UpdatedAt)
+
+	return field, err
+}
+
+// DeleteCustomField deletes a custom field and its values on every recipe.
+func (s *service) DeleteCustomField(ctx context.Context, name string) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM custom_field WHERE name = $1`, name)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE recipe SET custom_fields = custom_fields - $1::text WHERE custom_fields ? $1`, name); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// SetRecipeCustomFields replaces the custom field values of a recipe. It
+// returns ErrNotFound when the recipe doesn't exist.
+func (s *service) SetRecipeCustomFields(ctx context.Context, recipeId int, values map[string]json.RawMessage) error {
+
+	tag, err := s.db.Exec(ctx, `UPDATE recipe SET custom_fields = $2 WHERE id = $1`, recipeId, values)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
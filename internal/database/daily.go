@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DailyRecipeRepository picks and pins the recipe featured each day.
+type DailyRecipeRepository interface {
+	GetDailyRecipe(ctx context.Context, day time.Time) (*models.Recipe, error)
+	PinDailyRecipe(ctx context.Context, day time.Time, recipeId int) error
+}
+
+// dailyRecipeCooldown is how many days a featured recipe sits out before it
+// can be picked again, catalogue size permitting.
+const dailyRecipeCooldown = 30
+
+// GetDailyRecipe returns the recipe featured on day, choosing and recording
+// one the first time the day is asked for. The choice is seeded by the date,
+// so concurrent first requests agree. It returns nil when there are no
+// recipes.
+func (s *service) GetDailyRecipe(ctx context.Context, day time.Time) (*models.Recipe, error) {
+
+	date := day.Format(time.DateOnly)
+
+	recipeId, err := s.dailyRecipeId(ctx, date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if recipeId == 0 {
+		recipeId, err = s.pickDailyRecipe(ctx, date)
+
+		if err != nil || recipeId == 0 {
+			return nil, err
+		}
+
+		if _, err := s.db.Exec(ctx, `INSERT INTO daily_recipe (day, recipe_id) VALUES ($1, $2) ON CONFLICT (day) DO NOTHING`, date, recipeId); err != nil {
+			return nil, err
+		}
+
+		// Another request or an admin pin may have recorded the day first.
+		if recipeId, err = s.dailyRecipeId(ctx, date); err != nil {
+			return nil, err
+		}
+	}
+
+	var recipe models.Recipe
+
+	err = s.db.QueryRow(ctx, recipeByIdQuery, recipeId).Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId, &recipe.CustomFields)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &recipe, nil
+}
+
+// PinDailyRecipe features recipeId on day, replacing any earlier choice.
+func (s *service) PinDailyRecipe(ctx context.Context, day time.Time, recipeId int) error {
+
+	stmt := `
+		INSERT INTO daily_recipe (day, recipe_id, pinned) VALUES ($1, $2, true)
+		ON CONFLICT (day) DO UPDATE SET recipe_id = excluded.recipe_id, pinned = true
+	`
+
+	_, err := s.db.Exec(ctx, stmt, day.Format(time.DateOnly), recipeId)
+
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func (s *service) dailyRecipeId(ctx context.Context, date string) (int, error) {
+
+	var recipeId int
+
+	err := s.db.QueryRow(ctx, `SELECT recipe_id FROM daily_recipe WHERE day = $1`, date).Scan(&recipeId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+
+	return recipeId, err
+}
+
+// pickDailyRecipe orders the recipes by a hash of the date and their id and
+// takes the first one not featured during the cooldown. When every recipe
+// was featured recently it ignores the cooldown.
+func (s *service) pickDailyRecipe(ctx context.Context, date string) (int, error) {
+
+	query := `
+		SELECT r.id FROM recipe r
+		WHERE r.id NOT IN (
+			SELECT recipe_id FROM daily_recipe
+			WHERE day >= $1::date - $2::int AND day < $1::date
+		)
+		ORDER BY md5($1::text || ':' || r.id)
+		LIMIT 1
+	`
+
+	for _, cooldown := range []int{dailyRecipeCooldown, 0} {
+		var recipeId int
+
+		err := s.db.QueryRow(ctx, query, date, cooldown).Scan(&recipeId)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+
+		return recipeId, err
+	}
+
+	return 0, nil
+}
@@ -2,39 +2,80 @@ package database
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
+	"gastro-galaxy-back/internal/migrations"
 	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/requestid"
 	"log"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/tracelog"
 	_ "github.com/joho/godotenv/autoload"
 )
 
-// Service represents a service that interacts with a database.
+// Service represents a service that interacts with a database. Each kind of
+// data has a repository of its own, so code that only needs one of them can
+// depend on, and fake, just that.
 type Service interface {
 	// Health returns a map of health status information.
-	// The keys and values in the map are service-specific.
-	Health() map[string]string
+	// The keys and values in the map are service-specific. "status" is
+	// "up", "degraded" when the pool shows signs of trouble, or "down" when
+	// the database can't be reached.
+	Health(ctx context.Context) map[string]string
+
+	// Migrate applies the pending schema migrations, then converts the
+	// free text ingredient amounts it can read to a quantity and unit.
+	Migrate(ctx context.Context) error
+
+	// MigrateDown reverts the newest steps schema migrations.
+	MigrateDown(ctx context.Context, steps int) error
+
+	// SchemaVersion returns the newest migration applied to the database.
+	SchemaVersion(ctx context.Context) (int, error)
+
+	// CheckSchema returns migrations.ErrIncompatibleSchema when this build
+	// can't run against the database's schema.
+	CheckSchema(ctx context.Context) error
+
+	// MissingIndexes returns the expected indexes that don't exist in the database.
+	MissingIndexes(ctx context.Context) ([]string, error)
 
 	// Close terminates the database connection.
 	// It returns an error if the connection cannot be closed.
 	Close() error
 
-	InsertRecipe(name string, description string, longDescription string, url string, categoryId int, ingredientIds []int) (int, error)
-	UpdateRecipe(id int, name string, description string, url string) error
-	InsertRecipeIngredient(recipeId int, ingredientIds []int) error
-	GetRecipes(category string) ([]models.Recipe, error)
-	GetRecipeWithIngredients(recipeId int) (*models.RecipeWithIngredientsDto, error)
-	InsertIngredient(name string, amount string, url string, isAvailable bool) (int, error)
-	GetIngredients() (*[]models.Ingedient, error)
+	RecipeRepository
+	IngredientRepository
+	CategoryRepository
+	StepRepository
+	TagRepository
+	CatalogRepository
+	ShoppingListRepository
+	MealPlanRepository
+	DailyRecipeRepository
+	HomeRepository
+	StatsRepository
+	BannedWordRepository
+	ImageLinkRepository
+	AnalyticsRepository
+	PrepLogRepository
+	SuggestionRepository
+	ClassificationRepository
+	CustomFieldRepository
+	ImageImportRepository
 }
 
 type service struct {
-	db *sql.DB
+	db *pgxpool.Pool
+
+	// lastPool is the pool sample of the previous health check, which the
+	// next one compares its counters with.
+	poolMu   sync.Mutex
+	lastPool PoolSample
 }
 
 var (
@@ -43,252 +84,274 @@ var (
 	username   = os.Getenv("DB_USERNAME")
 	port       = os.Getenv("DB_PORT")
 	host       = os.Getenv("DB_HOST")
+	logLevel   = os.Getenv("DB_LOG_LEVEL")
 	dbInstance *service
 )
 
+// expectedIndexes are the indexes created by the migrations for the hot queries.
+var expectedIndexes = []string{
+	"idx_recipe_category_id",
+	"idx_recipe_name_trgm",
+	"idx_recipe_search",
+	"idx_ingredient_recipe_recipe_ingredient",
+	"idx_ingredient_recipe_ingredient_recipe",
+	"idx_recipe_tag_tag_recipe",
+}
+
 func New() Service {
 	// Reuse Connection
 	if dbInstance != nil {
 		return dbInstance
 	}
 	connStr := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", username, password, host, port, database)
-	db, err := sql.Open("pgx", connStr)
+	db, err := Open(connStr)
 	if err != nil {
 		log.Fatal(err)
 	}
-	dbInstance = &service{
-		db: db,
-	}
+	dbInstance = db.(*service)
 	return dbInstance
 }
 
+// Open returns a Service for the database at connStr, without the DB_*
+// environment configuration or connection reuse of New.
+func Open(connStr string) (Service, error) {
+	config, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if logLevel != "" {
+		level, err := tracelog.LogLevelFromString(logLevel)
+		if err != nil {
+			return nil, err
+		}
+		config.ConnConfig.Tracer = &tracelog.TraceLog{Logger: tracelog.LoggerFunc(logQuery), LogLevel: level}
+	}
+
+	// The pool connects lazily and caches prepared statements per connection.
+	db, err := pgxpool.NewWithConfig(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+	return &service{db: db}, nil
+}
+
+// logQuery writes pgx trace events to the standard logger, tagged with the
+// id of the request that issued the query.
+func logQuery(ctx context.Context, level tracelog.LogLevel, msg string, data map[string]any) {
+	if id := requestid.FromContext(ctx); id != "" {
+		data["request_id"] = id
+	}
+	log.Printf("db %s: %s %v", level, msg, data)
+}
+
+// nullIfEmpty stores empty optional text as NULL.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
 // Health checks the health of the database connection by pinging the database.
 // It returns a map with keys indicating various health statistics.
-func (s *service) Health() map[string]string {
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+func (s *service) Health(ctx context.Context) map[string]string {
+	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
 	stats := make(map[string]string)
 
 	// Ping the database
-	err := s.db.PingContext(ctx)
+	err := s.db.Ping(ctx)
 	if err != nil {
 		stats["status"] = "down"
 		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf(fmt.Sprintf("db down: %v", err)) // Log the error and terminate the program
+		log.Printf("health check failed: db down: %v", err)
 		return stats
 	}
 
-	// Database is up, add more statistics
-	stats["status"] = "up"
-	stats["message"] = "It's healthy"
-
-	// Get database stats (like open connections, in use, idle, etc.)
-	dbStats := s.db.Stats()
-	stats["open_connections"] = strconv.Itoa(dbStats.OpenConnections)
-	stats["in_use"] = strconv.Itoa(dbStats.InUse)
-	stats["idle"] = strconv.Itoa(dbStats.Idle)
-	stats["wait_count"] = strconv.FormatInt(dbStats.WaitCount, 10)
-	stats["wait_duration"] = dbStats.WaitDuration.String()
-	stats["max_idle_closed"] = strconv.FormatInt(dbStats.MaxIdleClosed, 10)
-	stats["max_lifetime_closed"] = strconv.FormatInt(dbStats.MaxLifetimeClosed, 10)
-
-	// Evaluate stats to provide a health message
-	if dbStats.OpenConnections > 40 { // Assuming 50 is the max for this example
-		stats["message"] = "The database is experiencing heavy load."
+	// Get pool stats (like open connections, in use, idle, etc.)
+	dbStats := s.db.Stat()
+	stats["open_connections"] = strconv.Itoa(int(dbStats.TotalConns()))
+	stats["in_use"] = strconv.Itoa(int(dbStats.AcquiredConns()))
+	stats["idle"] = strconv.Itoa(int(dbStats.IdleConns()))
+	stats["wait_count"] = strconv.FormatInt(dbStats.EmptyAcquireCount(), 10)
+	stats["wait_duration"] = dbStats.AcquireDuration().String()
+	stats["max_idle_closed"] = strconv.FormatInt(dbStats.MaxIdleDestroyCount(), 10)
+	stats["max_lifetime_closed"] = strconv.FormatInt(dbStats.MaxLifetimeDestroyCount(), 10)
+
+	sample := PoolSample{
+		TotalConns:     dbStats.TotalConns(),
+		MaxConns:       dbStats.MaxConns(),
+		EmptyAcquires:  dbStats.EmptyAcquireCount(),
+		IdleClosed:     dbStats.MaxIdleDestroyCount(),
+		LifetimeClosed: dbStats.MaxLifetimeDestroyCount(),
 	}
 
-	if dbStats.WaitCount > 1000 {
-		stats["message"] = "The database has a high number of wait events, indicating potential bottlenecks."
-	}
+	s.poolMu.Lock()
+	previous := s.lastPool
+	s.lastPool = sample
+	s.poolMu.Unlock()
 
-	if dbStats.MaxIdleClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many idle connections are being closed, consider revising the connection pool settings."
-	}
-
-	if dbStats.MaxLifetimeClosed > int64(dbStats.OpenConnections)/2 {
-		stats["message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
-	}
+	stats["status"], stats["message"] = EvaluatePool(previous, sample)
 
 	return stats
 }
 
-func (s *service) InsertRecipe(name string, description string, longDescription string, url string, categoryId int, ingredientIds []int) (int, error) {
-
-	log.Printf("Inserting new recipe")
-	stmt := `INSERT INTO recipe (name, description, long_description, imageurl, category_id) VALUES($1,$2,$3,$4,$5) RETURNING id`
+// PoolSample is what a health check reads from the connection pool. The
+// counters only grow over the life of the pool.
+type PoolSample struct {
+	TotalConns     int32
+	MaxConns       int32
+	EmptyAcquires  int64
+	IdleClosed     int64
+	LifetimeClosed int64
+}
 
-	var id int
+// EvaluatePool returns "degraded" with the reason when the pool looks
+// troubled, or "up". The counters are judged by how much they grew since
+// previous, the sample of the last check, so a pool that has been up for
+// long doesn't stay degraded for trouble long past.
+func EvaluatePool(previous PoolSample, current PoolSample) (string, string) {
+	status, message := "up", "It's healthy"
 
-	err := s.db.QueryRow(stmt, name, description, longDescription, url, categoryId).Scan(&id)
+	if current.TotalConns > current.MaxConns*4/5 {
+		status, message = "degraded", "The database is experiencing heavy load."
+	}
 
-	if err != nil {
-		return -1, err
+	if current.EmptyAcquires-previous.EmptyAcquires > 1000 {
+		status, message = "degraded", "The database has a high number of wait events, indicating potential bottlenecks."
 	}
 
-	for _, ingredientId := range ingredientIds {
-		stmt := `INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES($1,$2)`
-		_, err = s.db.Exec(stmt, ingredientId, id)
+	if current.IdleClosed-previous.IdleClosed > int64(current.TotalConns)/2 {
+		status, message = "degraded", "Many idle connections are being closed, consider revising the connection pool settings."
+	}
 
-		if err != nil {
-			return int(id), err
-		}
+	if current.LifetimeClosed-previous.LifetimeClosed > int64(current.TotalConns)/2 {
+		status, message = "degraded", "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
 	}
 
-	err = s.InsertRecipeIngredient(id, ingredientIds)
+	return status, message
+}
 
-	if err != nil {
-		return int(id), err
+func (s *service) Migrate(ctx context.Context) error {
+	if err := migrations.Up(ctx, s.db); err != nil {
+		return err
 	}
+	return s.convertLegacyAmounts(ctx)
+}
 
-	return int(id), nil
+func (s *service) MigrateDown(ctx context.Context, steps int) error {
+	return migrations.Down(ctx, s.db, steps)
 }
 
-func (s *service) GetRecipes(category string) ([]models.Recipe, error) {
-
-	baseQuery := `
-        SELECT r.id, r.name, r.description, r.long_description, r.imageurl, r.category_id
-        FROM recipe r
-    `
-	var rows *sql.Rows
-	var err error
-
-	if category != "" {
-		query := baseQuery + `
-        JOIN category c ON r.category_id = c.id
-        WHERE c.name = $1
-        `
-		rows, err = s.db.Query(query, category)
-	} else {
-		rows, err = s.db.Query(baseQuery)
-	}
+func (s *service) SchemaVersion(ctx context.Context) (int, error) {
+	return migrations.Version(ctx, s.db)
+}
+
+func (s *service) CheckSchema(ctx context.Context) error {
+	return migrations.Check(ctx, s.db)
+}
+
+func (s *service) MissingIndexes(ctx context.Context) ([]string, error) {
+
+	query := `SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND indexname = ANY($1)`
+
+	rows, err := s.db.Query(ctx, query, expectedIndexes)
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var recipes []models.Recipe
+	existing := make(map[string]bool)
 
 	for rows.Next() {
-		var recipe models.Recipe
-		if err := rows.Scan(&recipe.Id, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
+		var name string
+		if err := rows.Scan(&name); err != nil {
 			return nil, err
 		}
-		recipes = append(recipes, recipe)
+		existing[name] = true
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return recipes, nil
+	var missing []string
+
+	for _, name := range expectedIndexes {
+		if !existing[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
 }
 
-func (s *service) GetRecipeWithIngredients(recipeId int) (*models.RecipeWithIngredientsDto, error) {
+// StatsRepository counts the catalogue for the public statistics.
+type StatsRepository interface {
+	GetPublicStats(ctx context.Context, newest int) (*models.PublicStats, error)
+}
 
-	log.Printf("Getting recipe with ingredients")
+func (s *service) GetPublicStats(ctx context.Context, newest int) (*models.PublicStats, error) {
 
-	recipeQuery := `
-		SELECT r.id, r.name, r.description, r.long_description, r.imageurl, r.category_id
-		FROM recipe r
-		WHERE r.id = $1
+	countsQuery := `
+		SELECT
+			(SELECT COUNT(*) FROM recipe),
+			(SELECT COUNT(*) FROM category),
+			(SELECT COUNT(*) FROM ingredient)
 	`
 
-	var recipe models.Recipe
+	var stats models.PublicStats
 
-	err := s.db.QueryRow(recipeQuery, recipeId).Scan(&recipe.Id, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId)
+	err := s.db.QueryRow(ctx, countsQuery).Scan(&stats.TotalRecipes, &stats.TotalCategories, &stats.TotalIngredients)
 
 	if err != nil {
 		return nil, err
 	}
 
-	ingredientQuery := `
-		SELECT i.id, i.name, i.amount, i.imageUrl, i.isAvailable
-		FROM ingredient i
-		INNER JOIN ingredient_recipe ir ON i.id = ir.ingredient_id WHERE ir.recipe_id = $1
+	newestQuery := `
+		SELECT r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id
+		FROM recipe r
+		ORDER BY r.id DESC
+		LIMIT $1
 	`
-	rows, err := s.db.Query(ingredientQuery, recipeId)
+
+	rows, err := s.db.Query(ctx, newestQuery, newest)
+
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var ingredients []models.Ingedient
+	stats.NewestRecipes = []models.Recipe{}
 
 	for rows.Next() {
-		var ingredient models.Ingedient
-
-		if err := rows.Scan(&ingredient.Id, &ingredient.Name, &ingredient.Amount, &ingredient.Url, &ingredient.IsAvailable); err != nil {
+		var recipe models.Recipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
 			return nil, err
 		}
-
-		ingredients = append(ingredients, ingredient)
+		stats.NewestRecipes = append(stats.NewestRecipes, recipe)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return &models.RecipeWithIngredientsDto{
-		Recipe:      recipe,
-		Ingredients: ingredients,
-	}, nil
-
-}
-
-func (s *service) InsertIngredient(name string, amount string, url string, isAvailable bool) (int, error) {
-
-	log.Printf("Inserting new ingredient")
-	stmt := `INSERT INTO ingredient (name, amount, imageurl, isavailable) VALUES($1,$2,$3,$4) RETURNING id`
-
-	var id int
-
-	err := s.db.QueryRow(stmt, name, amount, url, isAvailable).Scan(&id)
-
-	if err != nil {
-		return -1, err
-	}
-
-	return int(id), nil
-}
-
-func (s *service) UpdateRecipe(id int, name string, description string, url string) error {
-
-	updateRecipeQuery := `
-		UPDATE recipe 
-		SET name = $2, description = $3, imageurl = $4
-		WHERE id = $1
-	`
-
-	_, err := s.db.Exec(updateRecipeQuery, id, name, description, url)
-
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return &stats, nil
 }
 
-func (s *service) InsertRecipeIngredient(recipeId int, ingredientIds []int) error {
-
-	for _, ingredientId := range ingredientIds {
-		stmt := `INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES($1,$2)`
-		_, err := s.db.Exec(stmt, ingredientId, recipeId)
-
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+// BannedWordRepository keeps the words user text may not contain.
+type BannedWordRepository interface {
+	GetBannedWords(ctx context.Context) ([]string, error)
+	InsertBannedWord(ctx context.Context, word string) error
+	DeleteBannedWord(ctx context.Context, word string) error
 }
 
-func (s *service) GetIngredients() (*[]models.Ingedient, error) {
-
-	getIngredientsQuery := `SELECT i.id, i.name, i.amount, i.imageUrl, i.isavailable FROM ingredient i`
+func (s *service) GetBannedWords(ctx context.Context) ([]string, error) {
 
-	rows, err := s.db.Query(getIngredientsQuery)
+	rows, err := s.db.Query(ctx, `SELECT word FROM banned_word ORDER BY word`)
 
 	if err != nil {
 		return nil, err
@@ -296,24 +359,42 @@ func (s *service) GetIngredients() (*[]models.Ingedient, error) {
 
 	defer rows.Close()
 
-	var ingredients []models.Ingedient
+	var words []string
 
 	for rows.Next() {
 
-		var ingredient models.Ingedient
+		var word string
 
-		if err := rows.Scan(&ingredient.Id, &ingredient.Name, &ingredient.Amount, &ingredient.Url, &ingredient.IsAvailable); err != nil {
+		if err := rows.Scan(&word); err != nil {
 			return nil, err
 		}
 
-		ingredients = append(ingredients, ingredient)
+		words = append(words, word)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return &ingredients, err
+	return words, nil
+}
+
+func (s *service) InsertBannedWord(ctx context.Context, word string) error {
+
+	stmt := `INSERT INTO banned_word (word) VALUES($1) ON CONFLICT (word) DO NOTHING`
+
+	_, err := s.db.Exec(ctx, stmt, word)
+
+	return err
+}
+
+func (s *service) DeleteBannedWord(ctx context.Context, word string) error {
+
+	stmt := `DELETE FROM banned_word WHERE word = $1`
+
+	_, err := s.db.Exec(ctx, stmt, word)
+
+	return err
 }
 
 // Close closes the database connection.
@@ -322,5 +403,6 @@ func (s *service) GetIngredients() (*[]models.Ingedient, error) {
 // If an error occurs while closing the connection, it returns the error.
 func (s *service) Close() error {
 	log.Printf("Disconnected from database: %s", database)
-	return s.db.Close()
+	s.db.Close()
+	return nil
 }
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	// ErrNotFound is returned when the row to change does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrInUse is returned when a row can't be deleted because other rows
+	// still reference it.
+	ErrInUse = errors.New("still referenced by other records")
+
+	// ErrUnknownReference is returned when a write refers to a row that
+	// does not exist.
+	ErrUnknownReference = errors.New("refers to a record that does not exist")
+
+	// ErrNestedVariant is returned when an ingredient would become a
+	// variant of a variant, or a base with variants would become a variant
+	// itself. The ingredient taxonomy is one level deep.
+	ErrNestedVariant = errors.New("variants can't have variants")
+
+	// ErrStepOrder is returned when a new order of the steps of a recipe
+	// doesn't list each of them exactly once.
+	ErrStepOrder = errors.New("must list every step of the recipe once")
+)
+
+const foreignKeyViolation = "23503"
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+)
+
+// HomeRepository keeps the recipes shown in the landing page slots.
+type HomeRepository interface {
+	GetHomeSlots(ctx context.Context) (map[string][]models.Recipe, error)
+	SetHomeSlot(ctx context.Context, slot string, recipeIds []int) error
+}
+
+// GetHomeSlots returns the recipes of every landing page slot that has any,
+// in display order.
+func (s *service) GetHomeSlots(ctx context.Context) (map[string][]models.Recipe, error) {
+
+	query := `
+		SELECT h.slot, r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id
+		FROM home_slot h
+		JOIN recipe r ON r.id = h.recipe_id
+		ORDER BY h.slot, h.position
+	`
+
+	rows, err := s.db.Query(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := map[string][]models.Recipe{}
+
+	for rows.Next() {
+		var slot string
+		var recipe models.Recipe
+		if err := rows.Scan(&slot, &recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
+			return nil, err
+		}
+		slots[slot] = append(slots[slot], recipe)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+// SetHomeSlot replaces the recipes of a landing page slot, keeping the order
+// of recipeIds.
+func (s *service) SetHomeSlot(ctx context.Context, slot string, recipeIds []int) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM home_slot WHERE slot = $1`, slot); err != nil {
+		return err
+	}
+
+	stmt := `
+		INSERT INTO home_slot (slot, position, recipe_id)
+		SELECT $1, ordinality, recipe_id FROM unnest($2::int[]) WITH ORDINALITY AS ids(recipe_id, ordinality)
+	`
+
+	if recipeIds == nil {
+		recipeIds = []int{}
+	}
+
+	_, err = tx.Exec(ctx, stmt, slot, recipeIds)
+
+	if isForeignKeyViolation(err) {
+		return ErrUnknownReference
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
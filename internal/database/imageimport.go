@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ImageImportRepository tracks the progress of bulk image imports.
+type ImageImportRepository interface {
+	InsertImageImport(ctx context.Context, files int) (string, error)
+	UpdateImageImport(ctx context.Context, id string, status string, results []models.ImageImportResult) error
+	GetImageImport(ctx context.Context, id string) (*models.ImageImport, error)
+}
+
+// InsertImageImport records a queued image import of files images and
+// returns its id.
+func (s *service) InsertImageImport(ctx context.Context, files int) (string, error) {
+
+	var id string
+
+	err := s.db.QueryRow(ctx, `INSERT INTO image_import (files) VALUES ($1) RETURNING id::text`, files).Scan(&id)
+
+	return id, err
+}
+
+// UpdateImageImport saves the status and the results so far of an image
+// import. Imports that are done or failed are marked finished.
+func (s *service) UpdateImageImport(ctx context.Context, id string, status string, results []models.ImageImportResult) error {
+
+	if results == nil {
+		results = []models.ImageImportResult{}
+	}
+
+	stmt := `
+		UPDATE image_import SET status = $2, results = $3,
+			finished_at = CASE WHEN $2 IN ('done', 'failed') THEN now() END
+		WHERE id = $1
+	`
+
+	tag, err := s.db.Exec(ctx, stmt, id, status, results)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *service) GetImageImport(ctx context.Context, id string) (*models.ImageImport, error) {
+
+	query := `SELECT id::text, status, files, results, created_at, finished_at FROM image_import WHERE id = $1`
+
+	var imageImport models.ImageImport
+
+	err := s.db.QueryRow(ctx, query, id).Scan(&imageImport.Id, &imageImport.Status, &imageImport.Files, &imageImport.Results, &imageImport.CreatedAt, &imageImport.FinishedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &imageImport, nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// InsertImportedRecipe inserts a recipe read from another site in one
+// transaction. Its ingredients are matched to the catalog by name, ignoring
+// case, and the missing ones are created as unavailable.
+func (s *service) InsertImportedRecipe(ctx context.Context, recipe models.ImportedRecipe) (int, error) {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback(ctx)
+
+	categoryId := recipe.CategoryId
+
+	if categoryId == 0 {
+		if categoryId, err = importedCategory(ctx, tx, recipe.Categories); err != nil {
+			return -1, err
+		}
+	}
+
+	ingredientIds := make([]int, 0, len(recipe.Ingredients))
+
+	for _, ingredient := range recipe.Ingredients {
+		var id int
+
+		err := tx.QueryRow(ctx, `SELECT id FROM ingredient WHERE lower(name) = lower($1) ORDER BY id LIMIT 1`, ingredient.Name).Scan(&id)
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			stmt := `INSERT INTO ingredient (uuid, name, amount, quantity, unit, isavailable) VALUES($1,$2,$3,$4,$5,false) RETURNING id`
+			err = tx.QueryRow(ctx, stmt, newUUID(), ingredient.Name, nullIfEmpty(ingredient.Amount), ingredient.Quantity, nullIfEmpty(ingredient.Unit)).Scan(&id)
+		}
+
+		if err != nil {
+			return -1, err
+		}
+
+		ingredientIds = append(ingredientIds, id)
+	}
+
+	stmt := `INSERT INTO recipe (uuid, name, description, long_description, imageurl, category_id) VALUES($1,$2,$3,$4,$5,$6) RETURNING id`
+
+	var id int
+
+	err = tx.QueryRow(ctx, stmt, newUUID(), recipe.Name, recipe.Description, nullIfEmpty(recipe.LongDescription), nullIfEmpty(recipe.Url), categoryId).Scan(&id)
+
+	if isForeignKeyViolation(err) {
+		return -1, ErrUnknownReference
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	if err := insertRecipeIngredients(ctx, tx, id, ingredientIds); err != nil {
+		return -1, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// importedCategory returns the first of names the catalog has, ignoring
+// case, and creates the first of them when it has none.
+func importedCategory(ctx context.Context, tx pgx.Tx, names []string) (int, error) {
+
+	if len(names) == 0 {
+		return -1, ErrUnknownReference
+	}
+
+	query := `
+		SELECT c.id FROM unnest($1::text[]) WITH ORDINALITY AS n(name, position)
+		JOIN category c ON lower(c.name) = lower(n.name)
+		ORDER BY n.position, c.id
+		LIMIT 1
+	`
+
+	var id int
+
+	err := tx.QueryRow(ctx, query, names).Scan(&id)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, `INSERT INTO category (uuid, name) VALUES($1,$2) RETURNING id`, newUUID(), names[0]).Scan(&id)
+	}
+
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
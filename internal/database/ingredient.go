@@ -0,0 +1,238 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"gastro-galaxy-back/internal/models"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IngredientRepository reads and writes ingredients, their availability and
+// their place in the ingredient taxonomy.
+type IngredientRepository interface {
+	InsertIngredient(ctx context.Context, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) (int, error)
+	UpdateIngredient(ctx context.Context, id int, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) error
+	DeleteIngredient(ctx context.Context, id int, cascade bool) error
+	SetIngredientsAvailability(ctx context.Context, ids []int, available *bool) ([]models.Ingedient, error)
+	SetIngredientBase(ctx context.Context, id int, baseId *int) error
+	GetIngredientSubstitutes(ctx context.Context, id int) ([]models.Ingedient, error)
+	GetRecipesUsingIngredient(ctx context.Context, id int) ([]models.Recipe, error)
+	GetIngredients(ctx context.Context, available *bool) (*[]models.Ingedient, error)
+	StreamIngredients(ctx context.Context, available *bool, fn func(models.Ingedient) error) error
+	GetIngredientsPage(ctx context.Context, available *bool, limit int, offset int) ([]models.Ingedient, int, error)
+	IngredientIdByUUID(ctx context.Context, id string) (int, error)
+}
+
+func (s *service) InsertIngredient(ctx context.Context, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) (int, error) {
+
+	log.Printf("Inserting new ingredient")
+	stmt := `
+		INSERT INTO ingredient (uuid, name, amount, imageurl, isavailable, quantity, unit, calories, protein, fat, carbs)
+		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11) RETURNING id
+	`
+
+	var id int
+
+	args := append([]any{newUUID(), name, nullIfEmpty(amount), nullIfEmpty(url), isAvailable, quantity, nullIfEmpty(unit)}, nutritionArgs(nutrition)...)
+
+	err := s.db.QueryRow(ctx, stmt, args...).Scan(&id)
+
+	if err != nil {
+		return -1, err
+	}
+
+	return int(id), nil
+}
+
+func (s *service) UpdateIngredient(ctx context.Context, id int, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) error {
+
+	stmt := `
+		UPDATE ingredient
+		SET name = $2, amount = $3, imageurl = $4, isavailable = $5, quantity = $6, unit = $7,
+			calories = $8, protein = $9, fat = $10, carbs = $11
+		WHERE id = $1
+	`
+
+	args := append([]any{id, name, nullIfEmpty(amount), nullIfEmpty(url), isAvailable, quantity, nullIfEmpty(unit)}, nutritionArgs(nutrition)...)
+
+	tag, err := s.db.Exec(ctx, stmt, args...)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// nutritionArgs returns the calories, protein, fat and carbs columns of
+// nutrition, all null when it is nil.
+func nutritionArgs(nutrition *models.Nutrition) []any {
+	if nutrition == nil {
+		return []any{nil, nil, nil, nil}
+	}
+	return []any{nutrition.Calories, nutrition.Protein, nutrition.Fat, nutrition.Carbs}
+}
+
+// DeleteIngredient removes an ingredient. When recipes still use it, it
+// returns ErrInUse unless cascade is set, in which case the ingredient is
+// also removed from those recipes.
+func (s *service) DeleteIngredient(ctx context.Context, id int, cascade bool) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if cascade {
+		if _, err := tx.Exec(ctx, `DELETE FROM ingredient_recipe WHERE ingredient_id = $1`, id); err != nil {
+			return err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM ingredient WHERE id = $1`, id)
+
+	if isForeignKeyViolation(err) {
+		return ErrInUse
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *service) GetIngredients(ctx context.Context, available *bool) (*[]models.Ingedient, error) {
+
+	var ingredients []models.Ingedient
+
+	err := s.StreamIngredients(ctx, available, func(ingredient models.Ingedient) error {
+		ingredients = append(ingredients, ingredient)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ingredients, nil
+}
+
+// ingredientsListQuery picks the ingredient list query for an optional
+// availability filter.
+func ingredientsListQuery(available *bool) (string, []any) {
+	if available == nil {
+		return ingredientsQuery, []any{}
+	}
+	return ingredientsByAvailabilityQuery, []any{*available}
+}
+
+// scanIngredient reads a row of ingredientColumns.
+func scanIngredient(row pgx.Row, ingredient *models.Ingedient) error {
+
+	var calories, protein, fat, carbs *float64
+
+	err := row.Scan(&ingredient.Id, &ingredient.Uuid, &ingredient.Name, &ingredient.Amount, &ingredient.Url, &ingredient.IsAvailable,
+		&ingredient.Quantity, &ingredient.Unit, &calories, &protein, &fat, &carbs, &ingredient.BaseId)
+
+	if err != nil {
+		return err
+	}
+
+	// The schema only allows all four values or none.
+	if calories != nil && protein != nil && fat != nil && carbs != nil {
+		ingredient.Nutrition = &models.Nutrition{Calories: *calories, Protein: *protein, Fat: *fat, Carbs: *carbs}
+	}
+
+	return nil
+}
+
+// StreamIngredients calls fn for every ingredient, optionally only those
+// whose availability matches available, without holding the whole result
+// set in memory. It stops at the first error returned by fn.
+func (s *service) StreamIngredients(ctx context.Context, available *bool, fn func(models.Ingedient) error) error {
+
+	query, args := ingredientsListQuery(available)
+
+	if err := s.checkRowLimit(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args = limitQuery(query, args...)
+
+	rows, err := s.db.Query(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var ingredient models.Ingedient
+
+		if err := scanIngredient(rows, &ingredient); err != nil {
+			return err
+		}
+
+		if err := fn(ingredient); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetIngredientsPage returns one page of ingredients ordered by id,
+// optionally only those whose availability matches available, along with
+// the total number of matching ingredients.
+func (s *service) GetIngredientsPage(ctx context.Context, available *bool, limit int, offset int) ([]models.Ingedient, int, error) {
+
+	query, args := ingredientsListQuery(available)
+
+	var total int
+
+	err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM (`+query+`) matching`, args...).Scan(&total)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pageQuery := fmt.Sprintf("%s ORDER BY i.id LIMIT $%d OFFSET $%d", query, len(args)+1, len(args)+2)
+
+	rows, err := s.db.Query(ctx, pageQuery, append(args, limit, offset)...)
+
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var ingredients []models.Ingedient
+
+	for rows.Next() {
+		var ingredient models.Ingedient
+		if err := scanIngredient(rows, &ingredient); err != nil {
+			return nil, 0, err
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return ingredients, total, nil
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const defaultMaxListRows = 1000
+
+// MaxListRows is the hard cap on rows any list query may return,
+// configurable with MAX_LIST_ROWS.
+var MaxListRows = envInt("MAX_LIST_ROWS", defaultMaxListRows)
+
+// TooManyRowsError is returned by list queries whose result would exceed
+// MaxListRows. Nothing has been streamed when it is returned.
+type TooManyRowsError struct {
+	Limit int
+}
+
+func (e *TooManyRowsError) Error() string {
+	return fmt.Sprintf("result has more than %d rows", e.Limit)
+}
+
+// checkRowLimit counts at most MaxListRows+1 rows of query, so the check
+// stays cheap no matter how large the table is.
+func (s *service) checkRowLimit(ctx context.Context, query string, args ...any) error {
+	capped := fmt.Sprintf("SELECT COUNT(*) FROM (%s LIMIT $%d) capped", query, len(args)+1)
+
+	var count int
+
+	if err := s.db.QueryRow(ctx, capped, append(args, MaxListRows+1)...).Scan(&count); err != nil {
+		return err
+	}
+
+	if count > MaxListRows {
+		return &TooManyRowsError{Limit: MaxListRows}
+	}
+
+	return nil
+}
+
+// limitQuery appends the MaxListRows cap to query as its next parameter.
+func limitQuery(query string, args ...any) (string, []any) {
+	return fmt.Sprintf("%s LIMIT $%d", query, len(args)+1), append(args, MaxListRows)
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
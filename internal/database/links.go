@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"gastro-galaxy-back/internal/models"
+	"time"
+)
+
+// ImageLinkRepository tracks the checks of the image links of recipes and
+// ingredients.
+type ImageLinkRepository interface {
+	GetImageLinksToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.ImageLink, error)
+	SetImageLinkStatus(ctx context.Context, link models.ImageLink, checkErr string, checkedAt time.Time) error
+	GetBrokenLinks(ctx context.Context) ([]models.BrokenLink, error)
+}
+
+// linkTables maps the kind of an image link to the table storing it.
+var linkTables = map[string]string{
+	models.LinkKindRecipe:     "recipe",
+	models.LinkKindIngredient: "ingredient",
+}
+
+// GetImageLinksToCheck returns up to limit external image URLs that were not
+// checked since checkedBefore, or whose URL changed after the last check.
+// Never checked URLs come first.
+func (s *service) GetImageLinksToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.ImageLink, error) {
+
+	query := `
+		SELECT kind, id, url FROM (
+			SELECT 'recipe' AS kind, id, imageurl AS url, image_checked_url, image_checked_at FROM recipe
+			UNION ALL
+			SELECT 'ingredient', id, imageurl, image_checked_url, image_checked_at FROM ingredient
+		) links
+		WHERE (url LIKE 'http://%' OR url LIKE 'https://%')
+		AND (image_checked_url IS DISTINCT FROM url OR image_checked_at < $1)
+		ORDER BY image_checked_url IS NOT DISTINCT FROM url, image_checked_at NULLS FIRST
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(ctx, query, checkedBefore, limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.ImageLink
+
+	for rows.Next() {
+		var link models.ImageLink
+		if err := rows.Scan(&link.Kind, &link.Id, &link.Url); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// SetImageLinkStatus records the result of checking link at checkedAt. An
+// empty checkErr means the link works. Nothing is recorded when the row no
+// longer stores link.Url.
+func (s *service) SetImageLinkStatus(ctx context.Context, link models.ImageLink, checkErr string, checkedAt time.Time) error {
+
+	table, ok := linkTables[link.Kind]
+
+	if !ok {
+		return fmt.Errorf("unknown image link kind %q", link.Kind)
+	}
+
+	// The right hand sides see the row as it was before the update, so a
+	// link that stays broken keeps its original image_broken_since.
+	stmt := fmt.Sprintf(`
+		UPDATE %s SET
+			image_broken_since = CASE
+				WHEN $3 = '' THEN NULL
+				WHEN image_broken_since IS NULL OR image_checked_url IS DISTINCT FROM $2 THEN $4
+				ELSE image_broken_since
+			END,
+			image_error = NULLIF($3, ''),
+			image_checked_url = $2,
+			image_checked_at = $4
+		WHERE id = $1 AND imageurl = $2
+	`, table)
+
+	_, err := s.db.Exec(ctx, stmt, link.Id, link.Url, checkErr, checkedAt)
+	return err
+}
+
+// GetBrokenLinks returns the image links that failed their latest check,
+// longest broken first.
+func (s *service) GetBrokenLinks(ctx context.Context) ([]models.BrokenLink, error) {
+
+	query := `
+		SELECT 'recipe', id, COALESCE(name, ''), imageurl, image_error, image_broken_since, image_checked_at
+		FROM recipe
+		WHERE image_broken_since IS NOT NULL AND image_checked_url = imageurl
+		UNION ALL
+		SELECT 'ingredient', id, COALESCE(name, ''), imageurl, image_error, image_broken_since, image_checked_at
+		FROM ingredient
+		WHERE image_broken_since IS NOT NULL AND image_checked_url = imageurl
+		ORDER BY 6, 1, 2
+	`
+
+	rows, err := s.db.Query(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.BrokenLink{}
+
+	for rows.Next() {
+		var link models.BrokenLink
+		if err := rows.Scan(&link.Kind, &link.Id, &link.Name, &link.Url, &link.Error, &link.BrokenSince, &link.CheckedAt); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
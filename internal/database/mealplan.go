@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+	"time"
+)
+
+// MealPlanRepository keeps the recipes planned for each day and slot.
+type MealPlanRepository interface {
+	GetMealPlan(ctx context.Context, from time.Time, to time.Time) ([]models.Meal, error)
+	SetMeal(ctx context.Context, day time.Time, slot string, recipeId int) error
+	DeleteMeal(ctx context.Context, day time.Time, slot string) error
+}
+
+// GetMealPlan returns the meals planned from the day from to the day to,
+// both included, by date and slot.
+func (s *service) GetMealPlan(ctx context.Context, from time.Time, to time.Time) ([]models.Meal, error) {
+
+	query := `
+		SELECT m.date, m.meal_slot, r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id
+		FROM meal_plan m
+		JOIN recipe r ON r.id = m.recipe_id
+		WHERE m.date BETWEEN $1 AND $2
+		ORDER BY m.date, array_position($3::text[], m.meal_slot)
+	`
+
+	rows, err := s.db.Query(ctx, query, from.Format(time.DateOnly), to.Format(time.DateOnly), models.MealSlots)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	meals := []models.Meal{}
+
+	for rows.Next() {
+		var meal models.Meal
+		if err := rows.Scan(&meal.Date, &meal.Slot, &meal.Recipe.Id, &meal.Recipe.Uuid, &meal.Recipe.Name, &meal.Recipe.Description, &meal.Recipe.LongDescription, &meal.Recipe.Url, &meal.Recipe.CategoryId); err != nil {
+			return nil, err
+		}
+		meals = append(meals, meal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return meals, nil
+}
+
+// SetMeal plans recipeId for a slot of day, replacing the recipe planned
+// there before. It returns ErrUnknownReference when the recipe doesn't
+// exist.
+func (s *service) SetMeal(ctx context.Context, day time.Time, slot string, recipeId int) error {
+
+	stmt := `
+		INSERT INTO meal_plan (date, meal_slot, recipe_id) VALUES ($1, $2, $3)
+		ON CONFLICT (date, meal_slot) DO UPDATE SET recipe_id = excluded.recipe_id
+	`
+
+	_, err := s.db.Exec(ctx, stmt, day.Format(time.DateOnly), slot, recipeId)
+
+	if isForeignKeyViolation(err) {
+		return ErrUnknownReference
+	}
+
+	return err
+}
+
+func (s *service) DeleteMeal(ctx context.Context, day time.Time, slot string) error {
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM meal_plan WHERE date = $1 AND meal_slot = $2`, day.Format(time.DateOnly), slot)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
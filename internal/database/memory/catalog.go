@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+)
+
+// ExportCatalog walks every category, ingredient and recipe for an export.
+// The rows are copied first, so visit runs without holding the store.
+func (s *Store) ExportCatalog(ctx context.Context, visit models.CatalogVisitor) error {
+	categories, ingredients, recipes := s.catalog()
+
+	for _, category := range categories {
+		if err := visit.Category(category); err != nil {
+			return err
+		}
+	}
+
+	for _, ingredient := range ingredients {
+		if err := visit.Ingredient(ingredient); err != nil {
+			return err
+		}
+	}
+
+	for _, recipe := range recipes {
+		if err := visit.Recipe(recipe); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// catalog returns a copy of every category, ingredient and recipe, by id.
+func (s *Store) catalog() ([]models.Category, []models.Ingedient, []models.CatalogRecipe) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var categories []models.Category
+	for _, id := range sortedKeys(s.categories) {
+		categories = append(categories, *s.categories[id])
+	}
+
+	var ingredients []models.Ingedient
+	for _, id := range sortedKeys(s.ingredients) {
+		ingredients = append(ingredients, s.ingredients[id].row())
+	}
+
+	var recipes []models.CatalogRecipe
+	for _, id := range sortedKeys(s.recipes) {
+		stored := s.recipes[id]
+		recipe := models.CatalogRecipe{Recipe: stored.row(), IngredientUuids: []string{}}
+
+		if stored.CategoryId != nil {
+			recipe.CategoryUuid = &s.categories[*stored.CategoryId].Uuid
+		}
+
+		for _, l := range s.links {
+			if l.recipeId == id {
+				recipe.IngredientUuids = append(recipe.IngredientUuids, s.ingredients[l.ingredientId].Uuid)
+			}
+		}
+
+		recipes = append(recipes, recipe)
+	}
+
+	return categories, ingredients, recipes
+}
+
+// ImportCatalog loads the rows of an export. Rows are matched by uuid: new
+// ones are created, existing ones are left alone, or replaced with their
+// ingredient list when overwrite is set. References to uuids neither in
+// the catalog nor in the store fail the whole import with
+// database.ErrUnknownReference, before anything is changed.
+func (s *Store) ImportCatalog(ctx context.Context, catalog models.Catalog, overwrite bool) (models.ImportSummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summary models.ImportSummary
+
+	if err := s.checkCatalog(catalog); err != nil {
+		return summary, err
+	}
+
+	for _, category := range catalog.Categories {
+		id, found := s.categoryByUUID(category.Uuid)
+
+		switch {
+		case !found:
+			id = s.next("category")
+			s.categories[id] = &models.Category{Id: id, Uuid: category.Uuid, Name: category.Name}
+			summary.Categories.Created++
+		case overwrite:
+			s.categories[id].Name = category.Name
+			summary.Categories.Updated++
+		default:
+			summary.Categories.Skipped++
+		}
+	}
+
+	for _, imported := range catalog.Ingredients {
+		id, found := s.ingredientByUUID(imported.Uuid)
+
+		switch {
+		case !found:
+			id = s.next("ingredient")
+			s.ingredients[id] = &ingredient{}
+			summary.Ingredients.Created++
+		case overwrite:
+			summary.Ingredients.Updated++
+		default:
+			summary.Ingredients.Skipped++
+			continue
+		}
+
+		stored := s.ingredients[id]
+		baseId := stored.BaseId
+		stored.Ingedient = imported
+		stored.Id = id
+		stored.BaseId = baseId
+		stored.Amount = copyPtr(imported.Amount)
+		stored.Url = copyPtr(imported.Url)
+		stored.Quantity = copyPtr(imported.Quantity)
+		stored.Unit = copyPtr(imported.Unit)
+		stored.Nutrition = copyPtr(imported.Nutrition)
+	}
+
+	for _, imported := range catalog.Recipes {
+		var categoryId *int
+		if imported.CategoryUuid != nil {
+			id, _ := s.categoryByUUID(*imported.CategoryUuid)
+			categoryId = &id
+		}
+
+		ingredientIds := make([]int, len(imported.IngredientUuids))
+		for i, ingredientUuid := range imported.IngredientUuids {
+			ingredientIds[i], _ = s.ingredientByUUID(ingredientUuid)
+		}
+
+		id, found := s.recipeByUUID(imported.Recipe.Uuid)
+
+		switch {
+		case !found:
+			id = s.next("recipe")
+			s.recipes[id] = &recipe{Recipe: models.Recipe{Id: id, Uuid: imported.Recipe.Uuid, CustomFields: map[string]json.RawMessage{}}}
+			summary.Recipes.Created++
+		case overwrite:
+			s.links = slices.DeleteFunc(s.links, func(l link) bool { return l.recipeId == id })
+			summary.Recipes.Updated++
+		default:
+			summary.Recipes.Skipped++
+			continue
+		}
+
+		stored := s.recipes[id]
+		stored.Name = imported.Recipe.Name
+		stored.Description = imported.Recipe.Description
+		stored.LongDescription = copyPtr(imported.Recipe.LongDescription)
+		stored.Url = copyPtr(imported.Recipe.Url)
+		stored.CategoryId = categoryId
+
+		s.link(id, ingredientIds)
+	}
+
+	return summary, nil
+}
+
+// checkCatalog returns database.ErrUnknownReference, with the row at fault,
+// when a recipe of catalog refers to a category or an ingredient neither in
+// catalog nor in the store.
+func (s *Store) checkCatalog(catalog models.Catalog) error {
+	categories := map[string]bool{}
+	for _, category := range catalog.Categories {
+		categories[category.Uuid] = true
+	}
+
+	ingredients := map[string]bool{}
+	for _, ingredient := range catalog.Ingredients {
+		ingredients[ingredient.Uuid] = true
+	}
+
+	for _, recipe := range catalog.Recipes {
+		if uuid := recipe.CategoryUuid; uuid != nil {
+			if _, found := s.categoryByUUID(*uuid); !found && !categories[*uuid] {
+				return fmt.Errorf("recipe %s: category %s: %w", recipe.Recipe.Uuid, *uuid, database.ErrUnknownReference)
+			}
+		}
+
+		for _, uuid := range recipe.IngredientUuids {
+			if _, found := s.ingredientByUUID(uuid); !found && !ingredients[uuid] {
+				return fmt.Errorf("recipe %s: ingredient %s: %w", recipe.Recipe.Uuid, uuid, database.ErrUnknownReference)
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetPublicStats returns how many recipes, categories and ingredients there
+// are, with the newest recipes.
+func (s *Store) GetPublicStats(ctx context.Context, newest int) (*models.PublicStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := models.PublicStats{
+		TotalRecipes:     len(s.recipes),
+		TotalCategories:  len(s.categories),
+		TotalIngredients: len(s.ingredients),
+		NewestRecipes:    []models.Recipe{},
+	}
+
+	recipeIds := sortedKeys(s.recipes)
+	slices.Reverse(recipeIds)
+
+	for _, id := range recipeIds[:min(len(recipeIds), newest)] {
+		stats.NewestRecipes = append(stats.NewestRecipes, s.recipes[id].row())
+	}
+
+	return &stats, nil
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"strings"
+)
+
+func (s *Store) InsertCategory(ctx context.Context, name string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertCategory(name), nil
+}
+
+func (s *Store) insertCategory(name string) int {
+	id := s.next("category")
+	s.categories[id] = &models.Category{Id: id, Uuid: newUUID(), Name: name}
+	return id
+}
+
+func (s *Store) GetCategories(ctx context.Context) ([]models.Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categories := []models.Category{}
+	for _, id := range sortedKeys(s.categories) {
+		categories = append(categories, *s.categories[id])
+	}
+
+	slices.SortStableFunc(categories, func(a, b models.Category) int { return strings.Compare(a.Name, b.Name) })
+	return categories, nil
+}
+
+func (s *Store) UpdateCategory(ctx context.Context, id int, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category, ok := s.categories[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	category.Name = name
+	return nil
+}
+
+// DeleteCategory removes a category. It returns database.ErrInUse while
+// recipes still belong to it.
+func (s *Store) DeleteCategory(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categories[id]; !ok {
+		return database.ErrNotFound
+	}
+
+	for _, recipe := range s.recipes {
+		if recipe.CategoryId != nil && *recipe.CategoryId == id {
+			return database.ErrInUse
+		}
+	}
+
+	delete(s.categories, id)
+	return nil
+}
+
+func (s *Store) CategoryIdByUUID(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.categoryByUUID(id); ok {
+		return id, nil
+	}
+	return 0, database.ErrNotFound
+}
+
+func (s *Store) categoryByUUID(uuid string) (int, bool) {
+	for id, category := range s.categories {
+		if category.Uuid == uuid {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// categoryByName returns the id of the category called name, ignoring case.
+// With several, the oldest wins.
+func (s *Store) categoryByName(name string) (int, bool) {
+	for _, id := range sortedKeys(s.categories) {
+		if strings.EqualFold(s.categories[id].Name, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
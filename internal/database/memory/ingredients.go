@@ -0,0 +1,327 @@
+package memory
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"strings"
+)
+
+// row returns a copy of the stored ingredient.
+func (i *ingredient) row() models.Ingedient {
+	return i.Ingedient
+}
+
+// family is the base of the ingredient, or itself for bases.
+func (i *ingredient) family() int {
+	if i.BaseId != nil {
+		return *i.BaseId
+	}
+	return i.Id
+}
+
+func (s *Store) InsertIngredient(ctx context.Context, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.next("ingredient")
+	s.ingredients[id] = &ingredient{Ingedient: models.Ingedient{
+		Id:          id,
+		Uuid:        newUUID(),
+		Name:        name,
+		Amount:      nullIfEmpty(amount),
+		Url:         nullIfEmpty(url),
+		IsAvailable: isAvailable,
+		Quantity:    copyPtr(quantity),
+		Unit:        nullIfEmpty(unit),
+		Nutrition:   copyPtr(nutrition),
+	}}
+
+	return id, nil
+}
+
+func (s *Store) UpdateIngredient(ctx context.Context, id int, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.ingredients[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	stored.Name = name
+	stored.Amount = nullIfEmpty(amount)
+	stored.Url = nullIfEmpty(url)
+	stored.IsAvailable = isAvailable
+	stored.Quantity = copyPtr(quantity)
+	stored.Unit = nullIfEmpty(unit)
+	stored.Nutrition = copyPtr(nutrition)
+	return nil
+}
+
+// DeleteIngredient removes an ingredient. When recipes still use it, it
+// returns database.ErrInUse unless cascade is set, in which case the
+// ingredient is also removed from those recipes. Its variants become bases
+// and shopping list items keep their copy of it.
+func (s *Store) DeleteIngredient(ctx context.Context, id int, cascade bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ingredients[id]; !ok {
+		return database.ErrNotFound
+	}
+
+	used := slices.ContainsFunc(s.links, func(l link) bool { return l.ingredientId == id })
+
+	if used && !cascade {
+		return database.ErrInUse
+	}
+
+	s.links = slices.DeleteFunc(s.links, func(l link) bool { return l.ingredientId == id })
+
+	for _, other := range s.ingredients {
+		if other.BaseId != nil && *other.BaseId == id {
+			other.BaseId = nil
+		}
+	}
+
+	for _, list := range s.shoppingLists {
+		for i := range list.Items {
+			if item := &list.Items[i]; item.IngredientId != nil && *item.IngredientId == id {
+				item.IngredientId = nil
+			}
+		}
+	}
+
+	delete(s.ingredients, id)
+	return nil
+}
+
+// SetIngredientsAvailability marks the ingredients in ids as available or
+// not, or flips each one's current availability when available is nil. It
+// returns the updated ingredients ordered by id. When any id does not exist
+// nothing is changed and database.ErrNotFound is returned.
+func (s *Store) SetIngredientsAvailability(ctx context.Context, ids []int, available *bool) ([]models.Ingedient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	for _, id := range ids {
+		if _, ok := s.ingredients[id]; !ok {
+			return nil, database.ErrNotFound
+		}
+	}
+
+	var ingredients []models.Ingedient
+
+	for _, id := range ids {
+		stored := s.ingredients[id]
+		if available != nil {
+			stored.IsAvailable = *available
+		} else {
+			stored.IsAvailable = !stored.IsAvailable
+		}
+		ingredients = append(ingredients, stored.row())
+	}
+
+	return ingredients, nil
+}
+
+// SetIngredientBase makes an ingredient a variant of baseId, or a base
+// again when baseId is nil.
+func (s *Store) SetIngredientBase(ctx context.Context, id int, baseId *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.ingredients[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	if baseId != nil {
+		base, ok := s.ingredients[*baseId]
+
+		if !ok {
+			return database.ErrUnknownReference
+		}
+
+		if base.BaseId != nil || *baseId == id {
+			return database.ErrNestedVariant
+		}
+
+		for _, other := range s.ingredients {
+			if other.BaseId != nil && *other.BaseId == id {
+				return database.ErrNestedVariant
+			}
+		}
+	}
+
+	stored.BaseId = copyPtr(baseId)
+	return nil
+}
+
+// GetIngredientSubstitutes returns the other ingredients of the family of
+// id: its base and the variants of that base. Available ones come first.
+func (s *Store) GetIngredientSubstitutes(ctx context.Context, id int) ([]models.Ingedient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.ingredients[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+
+	ingredients := []models.Ingedient{}
+
+	for _, otherId := range sortedKeys(s.ingredients) {
+		if other := s.ingredients[otherId]; otherId != id && other.family() == stored.family() {
+			ingredients = append(ingredients, other.row())
+		}
+	}
+
+	slices.SortStableFunc(ingredients, func(a, b models.Ingedient) int {
+		if a.IsAvailable != b.IsAvailable {
+			if a.IsAvailable {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return ingredients, nil
+}
+
+// GetRecipesUsingIngredient returns the recipes using id or any ingredient
+// of its family, by name.
+func (s *Store) GetRecipesUsingIngredient(ctx context.Context, id int) ([]models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.ingredients[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+
+	using := map[int]bool{}
+	for _, l := range s.links {
+		if s.ingredients[l.ingredientId].family() == stored.family() {
+			using[l.recipeId] = true
+		}
+	}
+
+	recipes := []models.Recipe{}
+	for _, recipeId := range sortedKeys(using) {
+		recipes = append(recipes, s.recipes[recipeId].row())
+	}
+
+	slices.SortStableFunc(recipes, func(a, b models.Recipe) int { return strings.Compare(a.Name, b.Name) })
+
+	return recipes[:min(len(recipes), database.MaxListRows)], nil
+}
+
+func (s *Store) GetIngredients(ctx context.Context, available *bool) (*[]models.Ingedient, error) {
+	var ingredients []models.Ingedient
+
+	err := s.StreamIngredients(ctx, available, func(ingredient models.Ingedient) error {
+		ingredients = append(ingredients, ingredient)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ingredients, nil
+}
+
+// StreamIngredients calls fn for every ingredient, optionally only those
+// whose availability matches available. It returns a
+// *database.TooManyRowsError, without calling fn, when there are more than
+// database.MaxListRows.
+func (s *Store) StreamIngredients(ctx context.Context, available *bool, fn func(models.Ingedient) error) error {
+	ingredients := s.matchingIngredients(available)
+
+	if len(ingredients) > database.MaxListRows {
+		return &database.TooManyRowsError{Limit: database.MaxListRows}
+	}
+
+	for _, ingredient := range ingredients {
+		if err := fn(ingredient); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) GetIngredientsPage(ctx context.Context, available *bool, limit int, offset int) ([]models.Ingedient, int, error) {
+	ingredients := s.matchingIngredients(available)
+	return page(ingredients, limit, offset), len(ingredients), nil
+}
+
+// matchingIngredients returns the ingredients whose availability matches
+// available, or all of them when it is nil, by id.
+func (s *Store) matchingIngredients(available *bool) []models.Ingedient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ingredients []models.Ingedient
+
+	for _, id := range sortedKeys(s.ingredients) {
+		if stored := s.ingredients[id]; available == nil || stored.IsAvailable == *available {
+			ingredients = append(ingredients, stored.row())
+		}
+	}
+
+	return ingredients
+}
+
+func (s *Store) IngredientIdByUUID(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.ingredientByUUID(id); ok {
+		return id, nil
+	}
+	return 0, database.ErrNotFound
+}
+
+func (s *Store) ingredientByUUID(uuid string) (int, bool) {
+	for id, stored := range s.ingredients {
+		if stored.Uuid == uuid {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// ingredientByName returns the id of the ingredient called name, ignoring
+// case. With several, the oldest wins.
+func (s *Store) ingredientByName(name string) (int, bool) {
+	for _, id := range sortedKeys(s.ingredients) {
+		if strings.EqualFold(s.ingredients[id].Name, name) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// page returns the rows of one page of rows, nil when offset is past the end.
+func page[T any](rows []T, limit int, offset int) []T {
+	if offset >= len(rows) {
+		return nil
+	}
+	return rows[offset:min(len(rows), offset+limit)]
+}
+
+func copyPtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	return clone(v)
+}
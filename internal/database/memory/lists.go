@@ -0,0 +1,298 @@
+package memory
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dailyRecipeCooldown is how many days a featured recipe sits out before it
+// can be picked again, catalogue size permitting.
+const dailyRecipeCooldown = 30
+
+// CreateShoppingList makes a list of the ingredients the recipes need that
+// aren't available, one item per ingredient, by name. It returns
+// database.ErrUnknownReference when a recipe doesn't exist.
+func (s *Store) CreateShoppingList(ctx context.Context, recipeIds []int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipeIds = slices.Clone(recipeIds)
+	slices.Sort(recipeIds)
+	recipeIds = slices.Compact(recipeIds)
+
+	for _, recipeId := range recipeIds {
+		if _, ok := s.recipes[recipeId]; !ok {
+			return -1, database.ErrUnknownReference
+		}
+	}
+
+	needs := map[int][]int{}
+	for _, l := range s.links {
+		if slices.Contains(recipeIds, l.recipeId) && !s.ingredients[l.ingredientId].IsAvailable && !slices.Contains(needs[l.ingredientId], l.recipeId) {
+			needs[l.ingredientId] = append(needs[l.ingredientId], l.recipeId)
+		}
+	}
+
+	ingredientIds := sortedKeys(needs)
+	slices.SortStableFunc(ingredientIds, func(a, b int) int {
+		return strings.Compare(s.ingredients[a].Name, s.ingredients[b].Name)
+	})
+
+	id := s.next("shopping_list")
+	list := &models.ShoppingList{Id: id, Uuid: newUUID(), RecipeIds: recipeIds, CreatedAt: time.Now(), Items: []models.ShoppingListItem{}}
+
+	for _, ingredientId := range ingredientIds {
+		stored := s.ingredients[ingredientId]
+		slices.Sort(needs[ingredientId])
+
+		list.Items = append(list.Items, models.ShoppingListItem{
+			Id:           s.next("shopping_list_item"),
+			IngredientId: ptr(ingredientId),
+			Name:         stored.Name,
+			Amount:       stored.Amount,
+			Quantity:     stored.Quantity,
+			Unit:         stored.Unit,
+			RecipeIds:    needs[ingredientId],
+		})
+	}
+
+	s.shoppingLists[id] = list
+	return id, nil
+}
+
+func (s *Store) GetShoppingList(ctx context.Context, id int) (*models.ShoppingList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.shoppingLists[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+
+	list := clone(stored)
+	list.RecipeIds = slices.Clone(stored.RecipeIds)
+	list.Items = make([]models.ShoppingListItem, len(stored.Items))
+
+	for i, item := range stored.Items {
+		item.RecipeIds = slices.Clone(item.RecipeIds)
+		list.Items[i] = item
+	}
+
+	return list, nil
+}
+
+// CheckShoppingListItem sets whether an item of a list is checked off, or
+// flips it when checked is nil.
+func (s *Store) CheckShoppingListItem(ctx context.Context, listId int, itemId int, checked *bool) (models.ShoppingListItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if list, ok := s.shoppingLists[listId]; ok {
+		for i := range list.Items {
+			if item := &list.Items[i]; item.Id == itemId {
+				if checked != nil {
+					item.Checked = *checked
+				} else {
+					item.Checked = !item.Checked
+				}
+
+				updated := *item
+				updated.RecipeIds = slices.Clone(item.RecipeIds)
+				return updated, nil
+			}
+		}
+	}
+
+	return models.ShoppingListItem{}, database.ErrNotFound
+}
+
+func (s *Store) DeleteShoppingList(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.shoppingLists[id]; !ok {
+		return database.ErrNotFound
+	}
+
+	delete(s.shoppingLists, id)
+	return nil
+}
+
+func (s *Store) ShoppingListIdByUUID(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, list := range s.shoppingLists {
+		if list.Uuid == id {
+			return list.Id, nil
+		}
+	}
+	return 0, database.ErrNotFound
+}
+
+// GetMealPlan returns the meals planned from the day from to the day to,
+// both included, by date and slot.
+func (s *Store) GetMealPlan(ctx context.Context, from time.Time, to time.Time) ([]models.Meal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first, last := from.Format(time.DateOnly), to.Format(time.DateOnly)
+	meals := []models.Meal{}
+
+	for key, recipeId := range s.meals {
+		if key.date < first || key.date > last {
+			continue
+		}
+
+		date, _ := time.Parse(time.DateOnly, key.date)
+		meals = append(meals, models.Meal{Date: date, Slot: key.slot, Recipe: s.recipes[recipeId].row()})
+	}
+
+	slices.SortFunc(meals, func(a, b models.Meal) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return slices.Index(models.MealSlots, a.Slot) - slices.Index(models.MealSlots, b.Slot)
+	})
+
+	return meals, nil
+}
+
+// SetMeal plans recipeId for a slot of day, replacing the recipe planned
+// there before.
+func (s *Store) SetMeal(ctx context.Context, day time.Time, slot string, recipeId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipeId]; !ok {
+		return database.ErrUnknownReference
+	}
+
+	s.meals[mealKey{day.Format(time.DateOnly), slot}] = recipeId
+	return nil
+}
+
+func (s *Store) DeleteMeal(ctx context.Context, day time.Time, slot string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := mealKey{day.Format(time.DateOnly), slot}
+
+	if _, ok := s.meals[key]; !ok {
+		return database.ErrNotFound
+	}
+
+	delete(s.meals, key)
+	return nil
+}
+
+// GetHomeSlots returns the recipes of every landing page slot that has any,
+// in display order.
+func (s *Store) GetHomeSlots(ctx context.Context) (map[string][]models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slots := map[string][]models.Recipe{}
+
+	for slot, recipeIds := range s.homeSlots {
+		for _, recipeId := range recipeIds {
+			slots[slot] = append(slots[slot], s.recipes[recipeId].row())
+		}
+	}
+
+	return slots, nil
+}
+
+// SetHomeSlot replaces the recipes of a landing page slot, keeping the order
+// of recipeIds.
+func (s *Store) SetHomeSlot(ctx context.Context, slot string, recipeIds []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, recipeId := range recipeIds {
+		if _, ok := s.recipes[recipeId]; !ok {
+			return database.ErrUnknownReference
+		}
+	}
+
+	s.homeSlots[slot] = slices.Clone(recipeIds)
+	return nil
+}
+
+// GetDailyRecipe returns the recipe featured on day, choosing one the first
+// time the day is asked for, the same way the Postgres service does. It
+// returns nil when there are no recipes.
+func (s *Store) GetDailyRecipe(ctx context.Context, day time.Time) (*models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	date := day.Format(time.DateOnly)
+
+	daily, ok := s.daily[date]
+
+	if !ok {
+		recipeId := s.pickDailyRecipe(day)
+
+		if recipeId == 0 {
+			return nil, nil
+		}
+
+		daily = dailyRecipe{recipeId: recipeId}
+		s.daily[date] = daily
+	}
+
+	recipe := s.recipes[daily.recipeId].full()
+	return &recipe, nil
+}
+
+// PinDailyRecipe features recipeId on day, replacing any earlier choice.
+func (s *Store) PinDailyRecipe(ctx context.Context, day time.Time, recipeId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipeId]; !ok {
+		return database.ErrNotFound
+	}
+
+	s.daily[day.Format(time.DateOnly)] = dailyRecipe{recipeId: recipeId, pinned: true}
+	return nil
+}
+
+// pickDailyRecipe orders the recipes by a hash of the date and their id and
+// takes the first one not featured during the cooldown. When every recipe
+// was featured recently it ignores the cooldown.
+func (s *Store) pickDailyRecipe(day time.Time) int {
+	date := day.Format(time.DateOnly)
+
+	hash := func(recipeId int) string {
+		sum := md5.Sum([]byte(date + ":" + strconv.Itoa(recipeId)))
+		return hex.EncodeToString(sum[:])
+	}
+
+	recipeIds := sortedKeys(s.recipes)
+	slices.SortFunc(recipeIds, func(a, b int) int { return strings.Compare(hash(a), hash(b)) })
+
+	for _, cooldown := range []int{dailyRecipeCooldown, 0} {
+		featured := map[int]bool{}
+		for d := 1; d <= cooldown; d++ {
+			if daily, ok := s.daily[day.AddDate(0, 0, -d).Format(time.DateOnly)]; ok {
+				featured[daily.recipeId] = true
+			}
+		}
+
+		for _, recipeId := range recipeIds {
+			if !featured[recipeId] {
+				return recipeId
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,199 @@
+// Package memory implements database.Service in memory, for local
+// development and handler tests without Postgres. It keeps the behaviour
+// the callers rely on: the errors of the Postgres service, the orderings,
+// the MAX_LIST_ROWS cap and what deleting a row does to the rows referring
+// to it. Full-text search is a plain word match, and nothing survives a
+// restart.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/migrations"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store is an in-memory database.Service. The zero value is not usable,
+// create one with New.
+type Store struct {
+	mu sync.Mutex
+
+	// serials hold the last id given out per table.
+	serials map[string]int
+
+	categories  map[int]*models.Category
+	recipes     map[int]*recipe
+	ingredients map[int]*ingredient
+	// links are the rows of ingredient_recipe, in insertion order.
+	links []link
+	steps map[int]*models.RecipeStep
+	// recipeTags are the rows of recipe_tag, in insertion order.
+	recipeTags []recipeTag
+
+	daily         map[string]dailyRecipe
+	homeSlots     map[string][]int
+	shoppingLists map[int]*models.ShoppingList
+	meals         map[mealKey]int
+
+	bannedWords    map[string]bool
+	suggestions    map[int]*models.Suggestion
+	tagSuggestions map[int]*models.TagSuggestion
+	prepLogs       map[int]*models.PrepLog
+	events         []models.AnalyticsEvent
+	customFields   map[string]*models.CustomField
+	imageImports   map[string]*models.ImageImport
+}
+
+type recipe struct {
+	models.Recipe
+	check linkCheck
+}
+
+type ingredient struct {
+	models.Ingedient
+	check linkCheck
+}
+
+// linkCheck is the latest result of the link checker for an image URL.
+type linkCheck struct {
+	url         *string
+	checkedAt   *time.Time
+	brokenSince *time.Time
+	err         string
+}
+
+type link struct {
+	id           int
+	recipeId     int
+	ingredientId int
+}
+
+type recipeTag struct {
+	recipeId int
+	tag      string
+}
+
+type dailyRecipe struct {
+	recipeId int
+	pinned   bool
+}
+
+type mealKey struct {
+	date string
+	slot string
+}
+
+var _ database.Service = (*Store)(nil)
+
+// seedCategories are the categories the first migration creates.
+var seedCategories = []string{"Pizzas", "Hamburgers", "Massas", "Bolos", "Brasileira"}
+
+// New returns an empty Store holding the categories a freshly migrated
+// database starts with.
+func New() *Store {
+	s := &Store{
+		serials:        map[string]int{},
+		categories:     map[int]*models.Category{},
+		recipes:        map[int]*recipe{},
+		ingredients:    map[int]*ingredient{},
+		steps:          map[int]*models.RecipeStep{},
+		daily:          map[string]dailyRecipe{},
+		homeSlots:      map[string][]int{},
+		shoppingLists:  map[int]*models.ShoppingList{},
+		meals:          map[mealKey]int{},
+		bannedWords:    map[string]bool{},
+		suggestions:    map[int]*models.Suggestion{},
+		tagSuggestions: map[int]*models.TagSuggestion{},
+		prepLogs:       map[int]*models.PrepLog{},
+		customFields:   map[string]*models.CustomField{},
+		imageImports:   map[string]*models.ImageImport{},
+	}
+
+	for _, name := range seedCategories {
+		s.insertCategory(name)
+	}
+
+	return s
+}
+
+// next returns the next serial id of table.
+func (s *Store) next(table string) int {
+	s.serials[table]++
+	return s.serials[table]
+}
+
+func newUUID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+func (s *Store) Health(ctx context.Context) map[string]string {
+	return map[string]string{"status": "up", "message": "In-memory database"}
+}
+
+// Migrate has nothing to do: the store always has the latest schema.
+func (s *Store) Migrate(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) MigrateDown(ctx context.Context, steps int) error {
+	return nil
+}
+
+func (s *Store) SchemaVersion(ctx context.Context) (int, error) {
+	return migrations.Latest()
+}
+
+func (s *Store) CheckSchema(ctx context.Context) error {
+	return nil
+}
+
+func (s *Store) MissingIndexes(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (s *Store) Close() error {
+	return nil
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[K int | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// clone returns a copy of v, so callers can't change the stored row.
+func clone[T any](v *T) *T {
+	c := *v
+	return &c
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// nullIfEmpty stores empty optional text as nil, like the Postgres service.
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// copyRaw returns a deep copy of a map of JSON values.
+func copyRaw(values map[string]json.RawMessage) map[string]json.RawMessage {
+	c := make(map[string]json.RawMessage, len(values))
+	for name, value := range values {
+		c[name] = slices.Clone(value)
+	}
+	return c
+}
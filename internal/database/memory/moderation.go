@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"time"
+)
+
+func (s *Store) GetBannedWords(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.bannedWords) == 0 {
+		return nil, nil
+	}
+	return sortedKeys(s.bannedWords), nil
+}
+
+func (s *Store) InsertBannedWord(ctx context.Context, word string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bannedWords[word] = true
+	return nil
+}
+
+func (s *Store) DeleteBannedWord(ctx context.Context, word string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.bannedWords, word)
+	return nil
+}
+
+// InsertSuggestion stores a pending suggestion. recipeId may be nil.
+func (s *Store) InsertSuggestion(ctx context.Context, kind string, message string, recipeId *int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if recipeId != nil {
+		if _, ok := s.recipes[*recipeId]; !ok {
+			return 0, database.ErrUnknownReference
+		}
+	}
+
+	id := s.next("suggestion")
+	s.suggestions[id] = &models.Suggestion{
+		Id:        id,
+		Kind:      kind,
+		Message:   message,
+		RecipeId:  copyPtr(recipeId),
+		Status:    models.SuggestionPending,
+		CreatedAt: time.Now(),
+	}
+
+	return id, nil
+}
+
+// GetSuggestions returns the suggestions with status, oldest first.
+func (s *Store) GetSuggestions(ctx context.Context, status string) ([]models.Suggestion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suggestions := []models.Suggestion{}
+
+	for _, id := range sortedKeys(s.suggestions) {
+		if suggestion := s.suggestions[id]; suggestion.Status == status {
+			row := *suggestion
+			row.RecipeId = copyPtr(suggestion.RecipeId)
+			row.ReviewedAt = copyPtr(suggestion.ReviewedAt)
+			suggestions = append(suggestions, row)
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b models.Suggestion) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	return suggestions[:min(len(suggestions), database.MaxListRows)], nil
+}
+
+// ReviewSuggestion sets the moderation status of a suggestion.
+func (s *Store) ReviewSuggestion(ctx context.Context, id int, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suggestion, ok := s.suggestions[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	suggestion.Status = status
+	suggestion.ReviewedAt = ptr(time.Now())
+	return nil
+}
+
+// GetRecipesToClassify returns up to limit recipes with an id above afterId,
+// in id order, with their category and ingredient names.
+func (s *Store) GetRecipesToClassify(ctx context.Context, afterId int, limit int) ([]models.ClassifiableRecipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipes := []models.ClassifiableRecipe{}
+
+	for _, id := range sortedKeys(s.recipes) {
+		if id <= afterId {
+			continue
+		}
+
+		if len(recipes) == limit {
+			break
+		}
+
+		stored := s.recipes[id]
+		recipe := models.ClassifiableRecipe{
+			Id:          id,
+			Name:        stored.Name,
+			Description: stored.Description,
+			Category:    s.categoryName(stored.CategoryId),
+			Ingredients: []string{},
+		}
+
+		if stored.LongDescription != nil {
+			recipe.LongDescription = *stored.LongDescription
+		}
+
+		for _, l := range s.links {
+			if l.recipeId == id {
+				recipe.Ingredients = append(recipe.Ingredients, s.ingredients[l.ingredientId].Name)
+			}
+		}
+
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, nil
+}
+
+// InsertTagSuggestions queues the proposed tags of a recipe for review,
+// skipping the ones proposed before whatever their status. It returns how
+// many were queued.
+func (s *Store) InsertTagSuggestions(ctx context.Context, recipeId int, proposals []models.TagProposal) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipeId]; !ok {
+		// The recipe was deleted since it was read.
+		return 0, nil
+	}
+
+	queued := 0
+
+	for _, proposal := range proposals {
+		proposed := false
+		for _, suggestion := range s.tagSuggestions {
+			if suggestion.RecipeId == recipeId && suggestion.Tag == proposal.Tag {
+				proposed = true
+				break
+			}
+		}
+
+		if proposed {
+			continue
+		}
+
+		id := s.next("tag_suggestion")
+		s.tagSuggestions[id] = &models.TagSuggestion{
+			Id:        id,
+			RecipeId:  recipeId,
+			Tag:       proposal.Tag,
+			Reason:    proposal.Reason,
+			Status:    models.SuggestionPending,
+			CreatedAt: time.Now(),
+		}
+		queued++
+	}
+
+	return queued, nil
+}
+
+// GetTagSuggestions returns the tag suggestions with status, oldest first.
+func (s *Store) GetTagSuggestions(ctx context.Context, status string) ([]models.TagSuggestion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suggestions := []models.TagSuggestion{}
+
+	for _, id := range sortedKeys(s.tagSuggestions) {
+		if suggestion := s.tagSuggestions[id]; suggestion.Status == status {
+			row := *suggestion
+			row.RecipeName = s.recipes[suggestion.RecipeId].Name
+			row.ReviewedAt = copyPtr(suggestion.ReviewedAt)
+			suggestions = append(suggestions, row)
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b models.TagSuggestion) int { return a.CreatedAt.Compare(b.CreatedAt) })
+
+	return suggestions[:min(len(suggestions), database.MaxListRows)], nil
+}
+
+// ReviewTagSuggestion sets the moderation status of a tag suggestion.
+// Accepting it adds the tag to its recipe.
+func (s *Store) ReviewTagSuggestion(ctx context.Context, id int, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suggestion, ok := s.tagSuggestions[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	suggestion.Status = status
+	suggestion.ReviewedAt = ptr(time.Now())
+
+	if status == models.SuggestionAccepted {
+		s.attachTags(suggestion.RecipeId, models.NormalizeTags([]string{suggestion.Tag}))
+	}
+
+	return nil
+}
@@ -0,0 +1,515 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// row returns the recipe as the list reads return it, without its custom
+// fields.
+func (r *recipe) row() models.Recipe {
+	row := r.Recipe
+	row.CustomFields = nil
+	return row
+}
+
+// full returns the recipe as the single recipe reads return it.
+func (r *recipe) full() models.Recipe {
+	row := r.Recipe
+	row.CustomFields = copyRaw(r.CustomFields)
+	return row
+}
+
+// InsertRecipe inserts a recipe and links its ingredients. It returns
+// database.ErrUnknownReference, and stores nothing, when the category or an
+// ingredient doesn't exist.
+func (s *Store) InsertRecipe(ctx context.Context, name string, description string, longDescription string, url string, categoryId int, ingredientIds []int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categories[categoryId]; !ok {
+		return -1, database.ErrUnknownReference
+	}
+
+	if !s.ingredientsExist(ingredientIds) {
+		return -1, database.ErrUnknownReference
+	}
+
+	id := s.insertRecipe(name, description, longDescription, url, categoryId)
+	s.link(id, ingredientIds)
+
+	return id, nil
+}
+
+func (s *Store) insertRecipe(name string, description string, longDescription string, url string, categoryId int) int {
+	id := s.next("recipe")
+	s.recipes[id] = &recipe{Recipe: models.Recipe{
+		Id:              id,
+		Uuid:            newUUID(),
+		CategoryId:      &categoryId,
+		Name:            name,
+		Url:             nullIfEmpty(url),
+		Description:     description,
+		LongDescription: nullIfEmpty(longDescription),
+		CustomFields:    map[string]json.RawMessage{},
+	}}
+	return id
+}
+
+func (s *Store) ingredientsExist(ids []int) bool {
+	for _, id := range ids {
+		if _, ok := s.ingredients[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// link adds the ingredients to a recipe, duplicates included.
+func (s *Store) link(recipeId int, ingredientIds []int) {
+	for _, ingredientId := range ingredientIds {
+		s.links = append(s.links, link{id: s.next("ingredient_recipe"), recipeId: recipeId, ingredientId: ingredientId})
+	}
+}
+
+// InsertImportedRecipe inserts a recipe read from another site. Its
+// ingredients are matched to the catalog by name, ignoring case, and the
+// missing ones are created as unavailable.
+func (s *Store) InsertImportedRecipe(ctx context.Context, imported models.ImportedRecipe) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	categoryId := imported.CategoryId
+
+	if categoryId == 0 {
+		if len(imported.Categories) == 0 {
+			return -1, database.ErrUnknownReference
+		}
+
+		found := false
+		for _, name := range imported.Categories {
+			if categoryId, found = s.categoryByName(name); found {
+				break
+			}
+		}
+
+		if !found {
+			categoryId = s.insertCategory(imported.Categories[0])
+		}
+	} else if _, ok := s.categories[categoryId]; !ok {
+		return -1, database.ErrUnknownReference
+	}
+
+	ingredientIds := make([]int, 0, len(imported.Ingredients))
+
+	for _, line := range imported.Ingredients {
+		id, ok := s.ingredientByName(line.Name)
+
+		if !ok {
+			id = s.next("ingredient")
+			s.ingredients[id] = &ingredient{Ingedient: models.Ingedient{
+				Id:       id,
+				Uuid:     newUUID(),
+				Name:     line.Name,
+				Amount:   nullIfEmpty(line.Amount),
+				Quantity: copyPtr(line.Quantity),
+				Unit:     nullIfEmpty(line.Unit),
+			}}
+		}
+
+		ingredientIds = append(ingredientIds, id)
+	}
+
+	id := s.insertRecipe(imported.Name, imported.Description, imported.LongDescription, imported.Url, categoryId)
+	s.link(id, ingredientIds)
+
+	return id, nil
+}
+
+// UpdateRecipe updates a recipe and replaces its ingredient list. It
+// returns database.ErrNotFound when the recipe doesn't exist and
+// database.ErrUnknownReference, changing nothing, when an ingredient
+// doesn't.
+func (s *Store) UpdateRecipe(ctx context.Context, id int, name string, description string, url string, ingredientIds []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.recipes[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	if !s.ingredientsExist(ingredientIds) {
+		return database.ErrUnknownReference
+	}
+
+	stored.Name = name
+	stored.Description = description
+	stored.Url = nullIfEmpty(url)
+
+	s.replaceLinks(id, ingredientIds)
+	return nil
+}
+
+func (s *Store) UpdateRecipeImage(ctx context.Context, id int, url string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.recipes[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	stored.Url = &url
+	return nil
+}
+
+func (s *Store) SetRecipeCustomFields(ctx context.Context, recipeId int, values map[string]json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.recipes[recipeId]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	stored.CustomFields = copyRaw(values)
+	return nil
+}
+
+// DeleteRecipe removes a recipe with everything that belongs to it: its
+// ingredient links, tag suggestions and its places in the meal plan, the
+// home page and the daily recipes. Suggestions and prep logs about it are
+// kept.
+func (s *Store) DeleteRecipe(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[id]; !ok {
+		return database.ErrNotFound
+	}
+
+	s.links = slices.DeleteFunc(s.links, func(l link) bool { return l.recipeId == id })
+
+	for stepId, step := range s.steps {
+		if step.RecipeId == id {
+			delete(s.steps, stepId)
+		}
+	}
+
+	s.recipeTags = slices.DeleteFunc(s.recipeTags, func(t recipeTag) bool { return t.recipeId == id })
+
+	for day, daily := range s.daily {
+		if daily.recipeId == id {
+			delete(s.daily, day)
+		}
+	}
+
+	for slot, recipeIds := range s.homeSlots {
+		s.homeSlots[slot] = slices.DeleteFunc(recipeIds, func(recipeId int) bool { return recipeId == id })
+	}
+
+	for key, recipeId := range s.meals {
+		if recipeId == id {
+			delete(s.meals, key)
+		}
+	}
+
+	for _, log := range s.prepLogs {
+		if log.RecipeId != nil && *log.RecipeId == id {
+			log.RecipeId = nil
+		}
+	}
+
+	for suggestionId, suggestion := range s.tagSuggestions {
+		if suggestion.RecipeId == id {
+			delete(s.tagSuggestions, suggestionId)
+		}
+	}
+
+	for _, suggestion := range s.suggestions {
+		if suggestion.RecipeId != nil && *suggestion.RecipeId == id {
+			suggestion.RecipeId = nil
+		}
+	}
+
+	delete(s.recipes, id)
+	return nil
+}
+
+// ReplaceRecipeIngredients makes ingredientIds the complete ingredient list
+// of a recipe. Links that stay are left untouched and duplicated links are
+// collapsed into one.
+func (s *Store) ReplaceRecipeIngredients(ctx context.Context, recipeId int, ingredientIds []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipeId]; !ok {
+		return database.ErrNotFound
+	}
+
+	if !s.ingredientsExist(ingredientIds) {
+		return database.ErrUnknownReference
+	}
+
+	s.replaceLinks(recipeId, ingredientIds)
+	return nil
+}
+
+// replaceLinks makes ingredientIds the complete ingredient list of a recipe.
+func (s *Store) replaceLinks(recipeId int, ingredientIds []int) {
+	kept := map[int]bool{}
+
+	s.links = slices.DeleteFunc(s.links, func(l link) bool {
+		if l.recipeId != recipeId {
+			return false
+		}
+		if kept[l.ingredientId] || !slices.Contains(ingredientIds, l.ingredientId) {
+			return true
+		}
+		kept[l.ingredientId] = true
+		return false
+	})
+
+	for _, ingredientId := range ingredientIds {
+		if !kept[ingredientId] {
+			s.link(recipeId, []int{ingredientId})
+			kept[ingredientId] = true
+		}
+	}
+
+}
+
+func (s *Store) GetRecipes(ctx context.Context, filter models.RecipeFilter) ([]models.Recipe, error) {
+	var recipes []models.Recipe
+
+	err := s.StreamRecipes(ctx, filter, func(recipe models.Recipe) error {
+		recipes = append(recipes, recipe)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// StreamRecipes calls fn for every recipe matching filter. It returns a
+// *database.TooManyRowsError, without calling fn, when there are more than
+// database.MaxListRows.
+func (s *Store) StreamRecipes(ctx context.Context, filter models.RecipeFilter, fn func(models.Recipe) error) error {
+	recipes := s.matchingRecipes(filter)
+
+	if len(recipes) > database.MaxListRows {
+		return &database.TooManyRowsError{Limit: database.MaxListRows}
+	}
+
+	for _, recipe := range recipes {
+		if err := fn(recipe); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) GetRecipesPage(ctx context.Context, filter models.RecipeFilter, limit int, offset int) ([]models.Recipe, int, error) {
+	recipes := s.matchingRecipes(filter)
+	return page(recipes, limit, offset), len(recipes), nil
+}
+
+// matchingRecipes returns the recipes matching filter, by id.
+func (s *Store) matchingRecipes(filter models.RecipeFilter) []models.Recipe {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var recipes []models.Recipe
+
+	for _, id := range sortedKeys(s.recipes) {
+		stored := s.recipes[id]
+
+		if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, s.categoryName(stored.CategoryId)) {
+			continue
+		}
+
+		if len(filter.Tags) > 0 && !s.hasTags(id, filter.Tags, filter.AnyTag) {
+			continue
+		}
+
+		recipes = append(recipes, stored.row())
+	}
+
+	return recipes
+}
+
+// categoryName returns the name of the category id, "" when there is none.
+func (s *Store) categoryName(id *int) string {
+	if id == nil {
+		return ""
+	}
+	if category, ok := s.categories[*id]; ok {
+		return category.Name
+	}
+	return ""
+}
+
+// FindRecipesByName returns the recipes whose name contains name, ignoring
+// case, by name.
+func (s *Store) FindRecipesByName(ctx context.Context, name string, limit int) ([]models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var recipes []models.Recipe
+
+	for _, id := range sortedKeys(s.recipes) {
+		if stored := s.recipes[id]; strings.Contains(strings.ToLower(stored.Name), strings.ToLower(name)) {
+			recipes = append(recipes, stored.row())
+		}
+	}
+
+	slices.SortStableFunc(recipes, func(a, b models.Recipe) int { return strings.Compare(a.Name, b.Name) })
+
+	return recipes[:min(len(recipes), limit, database.MaxListRows)], nil
+}
+
+// GetRandomRecipe returns a random recipe, optionally restricted to a
+// category. It returns nil when there is no matching recipe.
+func (s *Store) GetRandomRecipe(ctx context.Context, category string) (*models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matching []*recipe
+
+	for _, id := range sortedKeys(s.recipes) {
+		if stored := s.recipes[id]; category == "" || strings.EqualFold(s.categoryName(stored.CategoryId), category) {
+			matching = append(matching, stored)
+		}
+	}
+
+	if len(matching) == 0 {
+		return nil, nil
+	}
+
+	row := matching[rand.IntN(len(matching))].row()
+	return &row, nil
+}
+
+// GetRandomRecipeMatching returns a random recipe among those matching
+// filter, or nil when none does.
+func (s *Store) GetRandomRecipeMatching(ctx context.Context, filter models.RecipeFilter) (*models.Recipe, error) {
+	recipes := s.matchingRecipes(filter)
+
+	if len(recipes) == 0 {
+		return nil, nil
+	}
+
+	recipe := recipes[rand.IntN(len(recipes))]
+	return &recipe, nil
+}
+
+// GetCookableRecipes returns the recipes lacking at most maxMissing of their
+// ingredients, fewest missing first. The ingredients on hand are
+// ingredientIds, or the ones marked as available when ingredientIds is nil.
+// Any ingredient of the same family counts.
+func (s *Store) GetCookableRecipes(ctx context.Context, ingredientIds []int, maxMissing int) ([]models.CookableRecipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	onHand := map[int]bool{}
+	for _, stored := range s.ingredients {
+		if (ingredientIds == nil && stored.IsAvailable) || slices.Contains(ingredientIds, stored.Id) {
+			onHand[stored.family()] = true
+		}
+	}
+
+	uses := map[int]map[int]bool{}
+	for _, l := range s.links {
+		if uses[l.recipeId] == nil {
+			uses[l.recipeId] = map[int]bool{}
+		}
+		uses[l.recipeId][l.ingredientId] = true
+	}
+
+	recipes := []models.CookableRecipe{}
+
+	for _, recipeId := range sortedKeys(uses) {
+		missing := []int{}
+		for _, ingredientId := range sortedKeys(uses[recipeId]) {
+			if !onHand[s.ingredients[ingredientId].family()] {
+				missing = append(missing, ingredientId)
+			}
+		}
+
+		if len(missing) <= maxMissing {
+			recipes = append(recipes, models.CookableRecipe{Recipe: s.recipes[recipeId].row(), MissingIngredientIds: missing})
+		}
+	}
+
+	slices.SortStableFunc(recipes, func(a, b models.CookableRecipe) int {
+		return len(a.MissingIngredientIds) - len(b.MissingIngredientIds)
+	})
+
+	return recipes[:min(len(recipes), database.MaxListRows)], nil
+}
+
+// GetRecipeWithIngredients returns a recipe with its ingredients, or nil
+// when it doesn't exist.
+func (s *Store) GetRecipeWithIngredients(ctx context.Context, recipeId int) (*models.RecipeWithIngredients, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.recipes[recipeId]
+	if !ok {
+		return nil, nil
+	}
+
+	var ingredients []models.Ingedient
+	for _, l := range s.links {
+		if l.recipeId == recipeId {
+			ingredients = append(ingredients, s.ingredients[l.ingredientId].row())
+		}
+	}
+
+	return &models.RecipeWithIngredients{
+		Recipe:      stored.full(),
+		Ingredients: ingredients,
+		Nutrition:   models.NutritionOf(ingredients),
+		Steps:       s.recipeSteps(recipeId),
+		Tags:        s.tagsOf(recipeId),
+	}, nil
+}
+
+func (s *Store) RecipeIdByUUID(ctx context.Context, id string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.recipeByUUID(id); ok {
+		return id, nil
+	}
+	return 0, database.ErrNotFound
+}
+
+func (s *Store) RecipeUUIDById(ctx context.Context, id int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.recipes[id]; ok {
+		return stored.Uuid, nil
+	}
+	return "", database.ErrNotFound
+}
+
+func (s *Store) recipeByUUID(uuid string) (int, bool) {
+	for id, stored := range s.recipes {
+		if stored.Uuid == uuid {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,375 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// InsertPrepLog stores a prep batch and returns its id.
+func (s *Store) InsertPrepLog(ctx context.Context, log models.PrepLog) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if log.RecipeId == nil {
+		return 0, database.ErrUnknownReference
+	}
+
+	recipe, ok := s.recipes[*log.RecipeId]
+	if !ok {
+		return 0, database.ErrUnknownReference
+	}
+
+	log.Id = s.next("prep_log")
+	log.RecipeId = copyPtr(log.RecipeId)
+	log.RecipeName = recipe.Name
+	log.Temperatures = slices.Clone(log.Temperatures)
+	if log.Temperatures == nil {
+		log.Temperatures = []models.TemperatureReading{}
+	}
+
+	s.prepLogs[log.Id] = &log
+	return log.Id, nil
+}
+
+// GetPrepLogs returns the prep batches made in [from, to), of one recipe
+// or of all of them when recipeId is nil, oldest first.
+func (s *Store) GetPrepLogs(ctx context.Context, recipeId *int, from time.Time, to time.Time) ([]models.PrepLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []models.PrepLog{}
+
+	for _, id := range sortedKeys(s.prepLogs) {
+		log := *s.prepLogs[id]
+
+		if recipeId != nil && (log.RecipeId == nil || *log.RecipeId != *recipeId) {
+			continue
+		}
+
+		if log.PreparedAt.Before(from) || !log.PreparedAt.Before(to) {
+			continue
+		}
+
+		log.RecipeId = copyPtr(log.RecipeId)
+		log.Temperatures = slices.Clone(log.Temperatures)
+		logs = append(logs, log)
+	}
+
+	slices.SortStableFunc(logs, func(a, b models.PrepLog) int { return a.PreparedAt.Compare(b.PreparedAt) })
+
+	return logs[:min(len(logs), database.MaxListRows)], nil
+}
+
+// DeletePrepLogsBefore removes the prep batches made before before and
+// returns how many were removed.
+func (s *Store) DeletePrepLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+
+	for id, log := range s.prepLogs {
+		if log.PreparedAt.Before(before) {
+			delete(s.prepLogs, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// InsertAnalyticsEvents stores a batch of usage events.
+func (s *Store) InsertAnalyticsEvents(ctx context.Context, events []models.AnalyticsEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, events...)
+	return nil
+}
+
+// DeleteAnalyticsEventsBefore removes the events older than before and
+// returns how many were removed.
+func (s *Store) DeleteAnalyticsEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := len(s.events)
+	s.events = slices.DeleteFunc(s.events, func(event models.AnalyticsEvent) bool { return event.OccurredAt.Before(before) })
+
+	return int64(kept - len(s.events)), nil
+}
+
+// GetAnalyticsCounts returns the limit most frequent events of kind since
+// since, most frequent first.
+func (s *Store) GetAnalyticsCounts(ctx context.Context, kind string, since time.Time, limit int) ([]models.AnalyticsCount, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byName := map[string]int{}
+	for _, event := range s.events {
+		if event.Kind == kind && !event.OccurredAt.Before(since) {
+			byName[event.Name]++
+		}
+	}
+
+	counts := []models.AnalyticsCount{}
+	for _, name := range sortedKeys(byName) {
+		counts = append(counts, models.AnalyticsCount{Name: name, Count: byName[name]})
+	}
+
+	slices.SortStableFunc(counts, func(a, b models.AnalyticsCount) int { return b.Count - a.Count })
+
+	return counts[:min(len(counts), limit)], nil
+}
+
+// imageRow is a row with an image URL the link checker looks at.
+type imageRow struct {
+	link  models.ImageLink
+	name  string
+	url   *string
+	check *linkCheck
+}
+
+// imageRows returns the recipes and then the ingredients, each by id.
+func (s *Store) imageRows() []imageRow {
+	var rows []imageRow
+
+	for _, id := range sortedKeys(s.recipes) {
+		stored := s.recipes[id]
+		rows = append(rows, imageRow{models.ImageLink{Kind: models.LinkKindRecipe, Id: id}, stored.Name, stored.Url, &stored.check})
+	}
+
+	for _, id := range sortedKeys(s.ingredients) {
+		stored := s.ingredients[id]
+		rows = append(rows, imageRow{models.ImageLink{Kind: models.LinkKindIngredient, Id: id}, stored.Name, stored.Url, &stored.check})
+	}
+
+	return rows
+}
+
+// GetImageLinksToCheck returns up to limit external image URLs that were not
+// checked since checkedBefore, or whose URL changed after the last check.
+// Never checked URLs come first.
+func (s *Store) GetImageLinksToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]models.ImageLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []imageRow
+
+	for _, row := range s.imageRows() {
+		if row.url == nil || !(strings.HasPrefix(*row.url, "http://") || strings.HasPrefix(*row.url, "https://")) {
+			continue
+		}
+
+		changed := row.check.url == nil || *row.check.url != *row.url
+		if changed || row.check.checkedAt.Before(checkedBefore) {
+			row.link.Url = *row.url
+			due = append(due, row)
+		}
+	}
+
+	slices.SortStableFunc(due, func(a, b imageRow) int {
+		aChanged := a.check.url == nil || *a.check.url != a.link.Url
+		bChanged := b.check.url == nil || *b.check.url != b.link.Url
+
+		switch {
+		case aChanged != bChanged && aChanged:
+			return -1
+		case aChanged != bChanged:
+			return 1
+		case a.check.checkedAt == nil && b.check.checkedAt == nil:
+			return 0
+		case a.check.checkedAt == nil:
+			return -1
+		case b.check.checkedAt == nil:
+			return 1
+		}
+		return a.check.checkedAt.Compare(*b.check.checkedAt)
+	})
+
+	var links []models.ImageLink
+	for _, row := range due[:min(len(due), limit)] {
+		links = append(links, row.link)
+	}
+
+	return links, nil
+}
+
+// SetImageLinkStatus records the result of checking link at checkedAt. An
+// empty checkErr means the link works. Nothing is recorded when the row no
+// longer stores link.Url.
+func (s *Store) SetImageLinkStatus(ctx context.Context, link models.ImageLink, checkErr string, checkedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var url *string
+	var check *linkCheck
+
+	switch link.Kind {
+	case models.LinkKindRecipe:
+		if stored, ok := s.recipes[link.Id]; ok {
+			url, check = stored.Url, &stored.check
+		}
+	case models.LinkKindIngredient:
+		if stored, ok := s.ingredients[link.Id]; ok {
+			url, check = stored.Url, &stored.check
+		}
+	default:
+		return fmt.Errorf("unknown image link kind %q", link.Kind)
+	}
+
+	if url == nil || *url != link.Url {
+		return nil
+	}
+
+	switch {
+	case checkErr == "":
+		check.brokenSince = nil
+	case check.brokenSince == nil || check.url == nil || *check.url != link.Url:
+		check.brokenSince = ptr(checkedAt)
+	}
+
+	check.err = checkErr
+	check.url = ptr(link.Url)
+	check.checkedAt = ptr(checkedAt)
+	return nil
+}
+
+// GetBrokenLinks returns the image links that failed their latest check,
+// longest broken first.
+func (s *Store) GetBrokenLinks(ctx context.Context) ([]models.BrokenLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := []models.BrokenLink{}
+
+	for _, row := range s.imageRows() {
+		if row.check.brokenSince == nil || row.url == nil || row.check.url == nil || *row.check.url != *row.url {
+			continue
+		}
+
+		row.link.Url = *row.url
+		links = append(links, models.BrokenLink{
+			ImageLink:   row.link,
+			Name:        row.name,
+			Error:       row.check.err,
+			BrokenSince: *row.check.brokenSince,
+			CheckedAt:   *row.check.checkedAt,
+		})
+	}
+
+	slices.SortStableFunc(links, func(a, b models.BrokenLink) int { return a.BrokenSince.Compare(b.BrokenSince) })
+
+	return links, nil
+}
+
+func (s *Store) GetCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fields := []models.CustomField{}
+	for _, name := range sortedKeys(s.customFields) {
+		field := *s.customFields[name]
+		field.Schema = slices.Clone(field.Schema)
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// SetCustomField defines a custom field, or replaces the schema of an
+// existing one. Values already stored are not checked against the new
+// schema.
+func (s *Store) SetCustomField(ctx context.Context, name string, schema json.RawMessage) (models.CustomField, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	field := models.CustomField{Name: name, Schema: slices.Clone(schema), UpdatedAt: time.Now()}
+	s.customFields[name] = &field
+
+	updated := field
+	updated.Schema = slices.Clone(schema)
+	return updated, nil
+}
+
+// DeleteCustomField deletes a custom field and its values on every recipe.
+func (s *Store) DeleteCustomField(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.customFields[name]; !ok {
+		return database.ErrNotFound
+	}
+
+	delete(s.customFields, name)
+
+	for _, stored := range s.recipes {
+		delete(stored.CustomFields, name)
+	}
+
+	return nil
+}
+
+// InsertImageImport records a queued image import of files images and
+// returns its id.
+func (s *Store) InsertImageImport(ctx context.Context, files int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := newUUID()
+	s.imageImports[id] = &models.ImageImport{
+		Id:        id,
+		Status:    models.ImageImportQueued,
+		Files:     files,
+		Results:   []models.ImageImportResult{},
+		CreatedAt: time.Now(),
+	}
+
+	return id, nil
+}
+
+// UpdateImageImport saves the status and the results so far of an image
+// import. Imports that are done or failed are marked finished.
+func (s *Store) UpdateImageImport(ctx context.Context, id string, status string, results []models.ImageImportResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	imageImport, ok := s.imageImports[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+
+	imageImport.Status = status
+	imageImport.Results = slices.Clone(results)
+	if imageImport.Results == nil {
+		imageImport.Results = []models.ImageImportResult{}
+	}
+
+	imageImport.FinishedAt = nil
+	if status == models.ImageImportDone || status == models.ImageImportFailed {
+		imageImport.FinishedAt = ptr(time.Now())
+	}
+
+	return nil
+}
+
+func (s *Store) GetImageImport(ctx context.Context, id string) (*models.ImageImport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.imageImports[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+
+	imageImport := clone(stored)
+	imageImport.Results = slices.Clone(stored.Results)
+	imageImport.FinishedAt = copyPtr(stored.FinishedAt)
+	return imageImport, nil
+}
@@ -0,0 +1,244 @@
+package memory
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Weights of a match in each text, after the A, B and C weights of the
+// Postgres search vector.
+const (
+	nameWeight            = 1.0
+	descriptionWeight     = 0.4
+	longDescriptionWeight = 0.2
+)
+
+// snippetWords is how many words of the description a search result shows.
+const snippetWords = 20
+
+// searchGroup is one side of an "or" in a search: every term must match and
+// no excluded one may.
+type searchGroup struct {
+	terms    []string
+	excluded []string
+}
+
+// SearchRecipes matches the words of query against the recipe name,
+// description and long description, ignoring case and accents, best
+// matches first. It reads the same web search syntax as Postgres: "quoted
+// phrases", -excluded words and or. Unlike Postgres it doesn't stem words,
+// but a word also matches longer ones starting with it.
+func (s *Store) SearchRecipes(ctx context.Context, query string, limit int, offset int) ([]models.RecipeSearchResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := parseSearch(query)
+	results := []models.RecipeSearchResult{}
+
+	for _, id := range sortedKeys(s.recipes) {
+		stored := s.recipes[id]
+
+		texts := []string{fold(stored.Name), fold(stored.Description), ""}
+		if stored.LongDescription != nil {
+			texts[2] = fold(*stored.LongDescription)
+		}
+
+		var terms []string
+		for _, group := range groups {
+			if group.matches(texts) {
+				terms = append(terms, group.terms...)
+			}
+		}
+
+		if len(terms) == 0 {
+			continue
+		}
+
+		var rank float32
+		for _, term := range terms {
+			for i, weight := range []float32{nameWeight, descriptionWeight, longDescriptionWeight} {
+				if containsWord(texts[i], term) {
+					rank += weight
+				}
+			}
+		}
+
+		results = append(results, models.RecipeSearchResult{
+			Recipe:             stored.row(),
+			Rank:               rank,
+			NameHighlight:      highlight(stored.Name, terms),
+			DescriptionSnippet: snippet(highlight(stored.Description, terms)),
+		})
+	}
+
+	slices.SortStableFunc(results, func(a, b models.RecipeSearchResult) int {
+		switch {
+		case a.Rank > b.Rank:
+			return -1
+		case a.Rank < b.Rank:
+			return 1
+		}
+		return 0
+	})
+
+	return page(results, min(limit, database.MaxListRows), offset), nil
+}
+
+func parseSearch(query string) []searchGroup {
+	var groups []searchGroup
+	var group searchGroup
+
+	for _, token := range searchTokens(query) {
+		switch {
+		case token == "or":
+			if len(group.terms) > 0 {
+				groups = append(groups, group)
+			}
+			group = searchGroup{}
+		case strings.HasPrefix(token, "-") && len(token) > 1:
+			group.excluded = append(group.excluded, strings.TrimPrefix(token, "-"))
+		default:
+			group.terms = append(group.terms, token)
+		}
+	}
+
+	if len(group.terms) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// searchTokens splits a folded query into words, keeping quoted phrases
+// together.
+func searchTokens(query string) []string {
+	var tokens []string
+
+	for i, part := range strings.Split(fold(query), `"`) {
+		if i%2 == 1 {
+			if phrase := strings.Join(strings.FieldsFunc(part, isSeparator), " "); phrase != "" {
+				tokens = append(tokens, phrase)
+			}
+			continue
+		}
+
+		for _, word := range strings.Fields(part) {
+			negated := strings.HasPrefix(word, "-")
+			if word = strings.Join(strings.FieldsFunc(word, isSeparator), " "); word == "" {
+				continue
+			}
+			if negated {
+				word = "-" + word
+			}
+			tokens = append(tokens, word)
+		}
+	}
+
+	return tokens
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func (g searchGroup) matches(texts []string) bool {
+	found := func(term string) bool {
+		return slices.ContainsFunc(texts, func(text string) bool { return containsWord(text, term) })
+	}
+
+	for _, term := range g.terms {
+		if !found(term) {
+			return false
+		}
+	}
+
+	return !slices.ContainsFunc(g.excluded, found)
+}
+
+// containsWord reports whether the folded text has term at the start of a
+// word.
+func containsWord(text string, term string) bool {
+	for from := 0; from <= len(text); {
+		i := strings.Index(text[from:], term)
+		if i < 0 {
+			return false
+		}
+		i += from
+		if before, _ := utf8.DecodeLastRuneInString(text[:i]); i == 0 || isSeparator(before) {
+			return true
+		}
+		from = i + 1
+	}
+	return false
+}
+
+// fold lowers text and strips its accents. Each rune folds to exactly one
+// rune, so positions in the folded text are positions in the original.
+func fold(text string) string {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = foldRune(r)
+	}
+	return string(runes)
+}
+
+func foldRune(r rune) rune {
+	for _, decomposed := range norm.NFD.String(string(r)) {
+		return unicode.ToLower(decomposed)
+	}
+	return r
+}
+
+// highlight marks the words of text starting with one of terms, whole, with
+// models.HighlightStart and models.HighlightStop.
+func highlight(text string, terms []string) string {
+	original := []rune(text)
+	folded := []rune(fold(text))
+	marked := make([]bool, len(original))
+
+	for _, term := range terms {
+		termRunes := []rune(term)
+		for i := 0; i+len(termRunes) <= len(folded); i++ {
+			if (i == 0 || isSeparator(folded[i-1])) && slices.Equal(folded[i:i+len(termRunes)], termRunes) {
+				for j := i; j < len(folded) && (j < i+len(termRunes) || !isSeparator(folded[j])); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	for i, r := range original {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			b.WriteString(models.HighlightStart)
+		}
+		b.WriteRune(r)
+		if marked[i] && (i == len(original)-1 || !marked[i+1]) {
+			b.WriteString(models.HighlightStop)
+		}
+	}
+
+	return b.String()
+}
+
+// snippet keeps snippetWords words of a highlighted text, starting a few
+// words before the first match.
+func snippet(text string) string {
+	words := strings.Fields(text)
+
+	if len(words) <= snippetWords {
+		return text
+	}
+
+	start := slices.IndexFunc(words, func(word string) bool { return strings.Contains(word, models.HighlightStart) })
+	start = max(0, min(start-3, len(words)-snippetWords))
+
+	return strings.Join(words[start:start+snippetWords], " ")
+}
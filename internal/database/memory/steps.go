@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+)
+
+func (s *Store) GetRecipeSteps(ctx context.Context, recipeId int) ([]models.RecipeStep, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.recipeSteps(recipeId), nil
+}
+
+// recipeSteps returns a copy of the steps of a recipe, in order.
+func (s *Store) recipeSteps(recipeId int) []models.RecipeStep {
+	steps := []models.RecipeStep{}
+
+	for _, id := range sortedKeys(s.steps) {
+		if step := s.steps[id]; step.RecipeId == recipeId {
+			steps = append(steps, stepRow(step))
+		}
+	}
+
+	slices.SortFunc(steps, func(a, b models.RecipeStep) int { return a.Position - b.Position })
+
+	return steps
+}
+
+func stepRow(step *models.RecipeStep) models.RecipeStep {
+	row := *step
+	row.ImageUrl = copyPtr(step.ImageUrl)
+	row.DurationMinutes = copyPtr(step.DurationMinutes)
+	return row
+}
+
+// InsertRecipeStep appends a step to the recipe of step and returns its id.
+// It returns database.ErrNotFound when the recipe doesn't exist.
+func (s *Store) InsertRecipeStep(ctx context.Context, step models.RecipeStep) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[step.RecipeId]; !ok {
+		return 0, database.ErrNotFound
+	}
+
+	step.Id = s.next("recipe_step")
+	step.Position = len(s.recipeSteps(step.RecipeId)) + 1
+	step.ImageUrl = copyPtr(step.ImageUrl)
+	step.DurationMinutes = copyPtr(step.DurationMinutes)
+
+	s.steps[step.Id] = &step
+	return step.Id, nil
+}
+
+// UpdateRecipeStep replaces the text, image and duration of a step, keeping
+// its position. It returns database.ErrNotFound when the recipe has no such
+// step.
+func (s *Store) UpdateRecipeStep(ctx context.Context, step models.RecipeStep) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.steps[step.Id]
+	if !ok || stored.RecipeId != step.RecipeId {
+		return database.ErrNotFound
+	}
+
+	stored.Text = step.Text
+	stored.ImageUrl = copyPtr(step.ImageUrl)
+	stored.DurationMinutes = copyPtr(step.DurationMinutes)
+	return nil
+}
+
+// DeleteRecipeStep deletes a step and moves the steps after it up one
+// position. It returns database.ErrNotFound when the recipe has no such
+// step.
+func (s *Store) DeleteRecipeStep(ctx context.Context, recipeId int, stepId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.steps[stepId]
+	if !ok || stored.RecipeId != recipeId {
+		return database.ErrNotFound
+	}
+
+	delete(s.steps, stepId)
+
+	for _, step := range s.steps {
+		if step.RecipeId == recipeId && step.Position > stored.Position {
+			step.Position--
+		}
+	}
+
+	return nil
+}
+
+// ReorderRecipeSteps numbers the steps of a recipe in the order of stepIds.
+// It returns database.ErrNotFound when the recipe doesn't exist, and
+// database.ErrStepOrder when stepIds doesn't list each of its steps exactly
+// once.
+func (s *Store) ReorderRecipeSteps(ctx context.Context, recipeId int, stepIds []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipeId]; !ok {
+		return database.ErrNotFound
+	}
+
+	current := []int{}
+	for _, step := range s.recipeSteps(recipeId) {
+		current = append(current, step.Id)
+	}
+
+	slices.Sort(current)
+
+	sorted := slices.Clone(stepIds)
+	slices.Sort(sorted)
+
+	if !slices.Equal(sorted, current) {
+		return database.ErrStepOrder
+	}
+
+	for i, id := range stepIds {
+		s.steps[id].Position = i + 1
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+)
+
+// GetTags returns the tags carried by at least one recipe, by name.
+func (s *Store) GetTags(ctx context.Context) ([]models.Tag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, t := range s.recipeTags {
+		counts[t.tag]++
+	}
+
+	tags := []models.Tag{}
+	for _, name := range sortedKeys(counts) {
+		tags = append(tags, models.Tag{Name: name, Recipes: counts[name]})
+	}
+
+	return tags[:min(len(tags), database.MaxListRows)], nil
+}
+
+func (s *Store) GetRecipeTags(ctx context.Context, recipeId int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tagsOf(recipeId), nil
+}
+
+// tagsOf returns the tags of a recipe, by name.
+func (s *Store) tagsOf(recipeId int) []string {
+	tags := []string{}
+	for _, t := range s.recipeTags {
+		if t.recipeId == recipeId {
+			tags = append(tags, t.tag)
+		}
+	}
+
+	slices.Sort(tags)
+	return tags
+}
+
+// hasTags reports whether a recipe carries all of tags, or any of them when
+// anyTag is set.
+func (s *Store) hasTags(recipeId int, tags []string, anyTag bool) bool {
+	carried := s.tagsOf(recipeId)
+
+	for _, tag := range tags {
+		found := slices.Contains(carried, tag)
+		if found == anyTag {
+			return found
+		}
+	}
+
+	return !anyTag
+}
+
+// AttachRecipeTags adds tags to a recipe. Tags the recipe already has are
+// left alone. It returns database.ErrNotFound when the recipe doesn't exist.
+func (s *Store) AttachRecipeTags(ctx context.Context, recipeId int, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipeId]; !ok {
+		return database.ErrNotFound
+	}
+
+	s.attachTags(recipeId, tags)
+	return nil
+}
+
+func (s *Store) attachTags(recipeId int, tags []string) {
+	for _, tag := range tags {
+		if !slices.Contains(s.recipeTags, recipeTag{recipeId, tag}) {
+			s.recipeTags = append(s.recipeTags, recipeTag{recipeId, tag})
+		}
+	}
+}
+
+// DetachRecipeTag removes a tag from a recipe. It returns
+// database.ErrNotFound when the recipe doesn't carry it.
+func (s *Store) DetachRecipeTag(ctx context.Context, recipeId int, tag string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := len(s.recipeTags)
+	s.recipeTags = slices.DeleteFunc(s.recipeTags, func(t recipeTag) bool { return t == recipeTag{recipeId, tag} })
+
+	if len(s.recipeTags) == kept {
+		return database.ErrNotFound
+	}
+
+	return nil
+}
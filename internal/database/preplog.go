@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PrepLogRepository keeps the log of prepared batches.
+type PrepLogRepository interface {
+	InsertPrepLog(ctx context.Context, log models.PrepLog) (int, error)
+	GetPrepLogs(ctx context.Context, recipeId *int, from time.Time, to time.Time) ([]models.PrepLog, error)
+	DeletePrepLogsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// InsertPrepLog stores a prep batch, with the current name of its recipe,
+// and returns its id. It returns ErrUnknownReference when the recipe
+// doesn't exist.
+func (s *service) InsertPrepLog(ctx context.Context, log models.PrepLog) (int, error) {
+
+	stmt := `
+		INSERT INTO prep_log (recipe_id, recipe_name, prepared_by, prepared_at, batch_size, batch_unit, temperatures, notes)
+		SELECT r.id, COALESCE(r.name, ''), $2::text, $3::timestamptz, $4::numeric, $5::text, $6::jsonb, $7::text
+		FROM recipe r WHERE r.id = $1
+		RETURNING id
+	`
+
+	if log.Temperatures == nil {
+		log.Temperatures = []models.TemperatureReading{}
+	}
+
+	var id int
+
+	err := s.db.QueryRow(ctx, stmt, log.RecipeId, log.PreparedBy, log.PreparedAt, log.BatchSize, log.BatchUnit, log.Temperatures, log.Notes).Scan(&id)
+
+	if errors.Is(err, pgx.ErrNoRows) || isForeignKeyViolation(err) {
+		return 0, ErrUnknownReference
+	}
+
+	return id, err
+}
+
+// GetPrepLogs returns the prep batches made in [from, to), of one recipe
+// or of all of them when recipeId is nil, oldest first.
+func (s *service) GetPrepLogs(ctx context.Context, recipeId *int, from time.Time, to time.Time) ([]models.PrepLog, error) {
+
+	query := `
+		SELECT id, recipe_id, recipe_name, prepared_by, prepared_at, batch_size, batch_unit, temperatures, notes
+		FROM prep_log
+		WHERE ($1::int IS NULL OR recipe_id = $1) AND prepared_at >= $2 AND prepared_at < $3
+		ORDER BY prepared_at, id
+		LIMIT $4
+	`
+
+	rows, err := s.db.Query(ctx, query, recipeId, from, to, MaxListRows)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []models.PrepLog{}
+
+	for rows.Next() {
+		var log models.PrepLog
+		if err := rows.Scan(&log.Id, &log.RecipeId, &log.RecipeName, &log.PreparedBy, &log.PreparedAt, &log.BatchSize, &log.BatchUnit, &log.Temperatures, &log.Notes); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// DeletePrepLogsBefore removes the prep batches made before before and
+// returns how many were removed.
+func (s *service) DeletePrepLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM prep_log WHERE prepared_at < $1`, before)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"gastro-galaxy-back/internal/models"
+	"strings"
+)
+
+// Queries behind the hot read paths. They are kept here, rather than inline,
+// so the query plan tests can EXPLAIN exactly what the service runs.
+const (
+	recipesQuery = `
+		SELECT r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id
+		FROM recipe r
+	`
+
+	recipesByCategoryQuery = recipesQuery + `
+		JOIN category c ON r.category_id = c.id
+		WHERE c.name = ANY($1)
+	`
+
+	recipesByNameQuery = recipesQuery + `
+		WHERE r.name ILIKE '%' || $1 || '%' ESCAPE '\'
+		ORDER BY r.name
+		LIMIT $2
+	`
+
+	// recipeSearchVector must stay identical to the expression of
+	// idx_recipe_search in migration 0002_indexes, or the index won't be used.
+	recipeSearchVector = `(setweight(to_tsvector('portuguese', coalesce(r.name, '')), 'A') || setweight(to_tsvector('portuguese', coalesce(r.description, '')), 'B') || setweight(to_tsvector('portuguese', coalesce(r.long_description, '')), 'C'))`
+
+	// recipeSearchQuery ranks the matches first and only builds the
+	// highlights, which are expensive, for the page being returned.
+	recipeSearchQuery = `
+		SELECT r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id, r.rank,
+			ts_headline('portuguese', coalesce(r.name, ''), r.q, '` + nameHeadlineOptions + `'),
+			ts_headline('portuguese', coalesce(r.description, ''), r.q, '` + descriptionHeadlineOptions + `')
+		FROM (
+			SELECT r.*, ts_rank(` + recipeSearchVector + `, q) AS rank, q
+			FROM recipe r, websearch_to_tsquery('portuguese', $1) q
+			WHERE ` + recipeSearchVector + ` @@ q
+			ORDER BY rank DESC, r.id
+			LIMIT $2 OFFSET $3
+		) r
+		ORDER BY r.rank DESC, r.id
+	`
+
+	nameHeadlineOptions        = `StartSel=` + models.HighlightStart + `, StopSel=` + models.HighlightStop + `, HighlightAll=true`
+	descriptionHeadlineOptions = `StartSel=` + models.HighlightStart + `, StopSel=` + models.HighlightStop + `, MaxWords=20, MinWords=8, MaxFragments=2, FragmentDelimiter=" … "`
+
+	recipeByIdQuery = `
+		SELECT r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id, r.custom_fields
+		FROM recipe r
+		WHERE r.id = $1
+	`
+
+	// ingredientColumns are read by scanIngredient.
+	ingredientColumns = `i.id, i.uuid, i.name, i.amount, i.imageUrl, COALESCE(i.isAvailable, false), i.quantity, i.unit, i.calories, i.protein, i.fat, i.carbs, i.base_id`
+
+	ingredientsQuery = `
+		SELECT ` + ingredientColumns + `
+		FROM ingredient i
+	`
+
+	ingredientsByAvailabilityQuery = ingredientsQuery + `
+		WHERE COALESCE(i.isAvailable, false) = $1
+	`
+
+	recipeIngredientsQuery = `
+		SELECT ` + ingredientColumns + `
+		FROM ingredient i
+		INNER JOIN ingredient_recipe ir ON i.id = ir.ingredient_id WHERE ir.recipe_id = $1
+	`
+)
+
+// KeyQuery is a hot read query with representative arguments.
+type KeyQuery struct {
+	Name string
+	SQL  string
+	Args []any
+}
+
+// KeyQueries returns the queries that must stay index-backed as the tables
+// grow. Arguments match the data seeded by the query plan tests.
+func KeyQueries() []KeyQuery {
+	return []KeyQuery{
+		{Name: "recipes by category", SQL: recipesByCategoryQuery, Args: []any{[]string{"Category 42"}}},
+		{Name: "recipes by name", SQL: recipesByNameQuery, Args: []any{"Recipe 12345", 5}},
+		{Name: "recipe search", SQL: recipeSearchQuery, Args: []any{"12345", 20, 0}},
+		{Name: "recipe by id", SQL: recipeByIdQuery, Args: []any{42}},
+		{Name: "recipe by uuid", SQL: `SELECT id FROM recipe WHERE uuid = $1`, Args: []any{"01890a5d-ac96-774b-bcce-b302099a8057"}},
+		{Name: "recipe ingredients", SQL: recipeIngredientsQuery, Args: []any{42}},
+	}
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, for queries that use
+// ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike returns s as a LIKE pattern matching s literally, so text
+// from users such as "%" doesn't match every row.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,517 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/models"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RecipeRepository reads and writes recipes and the ingredients they link.
+type RecipeRepository interface {
+	InsertRecipe(ctx context.Context, name string, description string, longDescription string, url string, categoryId int, ingredientIds []int) (int, error)
+	InsertImportedRecipe(ctx context.Context, recipe models.ImportedRecipe) (int, error)
+	UpdateRecipe(ctx context.Context, id int, name string, description string, url string, ingredientIds []int) error
+	UpdateRecipeImage(ctx context.Context, id int, url string) error
+	SetRecipeCustomFields(ctx context.Context, recipeId int, values map[string]json.RawMessage) error
+	DeleteRecipe(ctx context.Context, id int) error
+	ReplaceRecipeIngredients(ctx context.Context, recipeId int, ingredientIds []int) error
+	GetRecipes(ctx context.Context, filter models.RecipeFilter) ([]models.Recipe, error)
+	StreamRecipes(ctx context.Context, filter models.RecipeFilter, fn func(models.Recipe) error) error
+	GetRecipesPage(ctx context.Context, filter models.RecipeFilter, limit int, offset int) ([]models.Recipe, int, error)
+	FindRecipesByName(ctx context.Context, name string, limit int) ([]models.Recipe, error)
+	SearchRecipes(ctx context.Context, query string, limit int, offset int) ([]models.RecipeSearchResult, error)
+	GetRandomRecipe(ctx context.Context, category string) (*models.Recipe, error)
+	GetRandomRecipeMatching(ctx context.Context, filter models.RecipeFilter) (*models.Recipe, error)
+	GetCookableRecipes(ctx context.Context, ingredientIds []int, maxMissing int) ([]models.CookableRecipe, error)
+	GetRecipeWithIngredients(ctx context.Context, recipeId int) (*models.RecipeWithIngredients, error)
+	RecipeIdByUUID(ctx context.Context, id string) (int, error)
+	RecipeUUIDById(ctx context.Context, id int) (string, error)
+}
+
+// InsertRecipe inserts a recipe and links its ingredients in one
+// transaction, so a failed link leaves no partial recipe behind.
+func (s *service) InsertRecipe(ctx context.Context, name string, description string, longDescription string, url string, categoryId int, ingredientIds []int) (int, error) {
+
+	log.Printf("Inserting new recipe")
+	stmt := `INSERT INTO recipe (uuid, name, description, long_description, imageurl, category_id) VALUES($1,$2,$3,$4,$5,$6) RETURNING id`
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int
+
+	err = tx.QueryRow(ctx, stmt, newUUID(), name, description, nullIfEmpty(longDescription), nullIfEmpty(url), categoryId).Scan(&id)
+
+	if err != nil {
+		return -1, err
+	}
+
+	if err := insertRecipeIngredients(ctx, tx, id, ingredientIds); err != nil {
+		return -1, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (s *service) GetRecipes(ctx context.Context, filter models.RecipeFilter) ([]models.Recipe, error) {
+
+	var recipes []models.Recipe
+
+	err := s.StreamRecipes(ctx, filter, func(recipe models.Recipe) error {
+		recipes = append(recipes, recipe)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// recipesListQuery builds the recipe list query for filter.
+func recipesListQuery(filter models.RecipeFilter) (string, []any) {
+	query, args, where := recipesQuery, []any{}, "WHERE"
+
+	if len(filter.Categories) > 0 {
+		query, args, where = recipesByCategoryQuery, []any{filter.Categories}, "AND"
+	}
+
+	if len(filter.Tags) == 0 {
+		return query, args
+	}
+
+	args = append(args, filter.Tags)
+
+	tagged := fmt.Sprintf(`
+		SELECT rt.recipe_id FROM recipe_tag rt
+		JOIN tag t ON t.id = rt.tag_id
+		WHERE t.name = ANY($%d)
+	`, len(args))
+
+	if !filter.AnyTag {
+		tagged += fmt.Sprintf(`GROUP BY rt.recipe_id HAVING COUNT(*) = cardinality($%d::text[])`, len(args))
+	}
+
+	return fmt.Sprintf("%s %s r.id IN (%s)", query, where, tagged), args
+}
+
+// StreamRecipes calls fn for every recipe matching filter without holding
+// the whole result set in memory. It stops at the first error returned by
+// fn.
+func (s *service) StreamRecipes(ctx context.Context, filter models.RecipeFilter, fn func(models.Recipe) error) error {
+
+	query, args := recipesListQuery(filter)
+
+	if err := s.checkRowLimit(ctx, query, args...); err != nil {
+		return err
+	}
+
+	query, args = limitQuery(query, args...)
+
+	rows, err := s.db.Query(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipe models.Recipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
+			return err
+		}
+		if err := fn(recipe); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetRecipesPage returns one page of the recipes matching filter ordered by
+// id, along with the total number of matching recipes.
+func (s *service) GetRecipesPage(ctx context.Context, filter models.RecipeFilter, limit int, offset int) ([]models.Recipe, int, error) {
+
+	query, args := recipesListQuery(filter)
+
+	var total int
+
+	err := s.db.QueryRow(ctx, `SELECT COUNT(*) FROM (`+query+`) matching`, args...).Scan(&total)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pageQuery := fmt.Sprintf("%s ORDER BY r.id LIMIT $%d OFFSET $%d", query, len(args)+1, len(args)+2)
+
+	rows, err := s.db.Query(ctx, pageQuery, append(args, limit, offset)...)
+
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var recipes []models.Recipe
+
+	for rows.Next() {
+		var recipe models.Recipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
+			return nil, 0, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return recipes, total, nil
+}
+
+func (s *service) FindRecipesByName(ctx context.Context, name string, limit int) ([]models.Recipe, error) {
+
+	limit = min(limit, MaxListRows)
+
+	rows, err := s.db.Query(ctx, recipesByNameQuery, escapeLike(name), limit)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []models.Recipe
+
+	for rows.Next() {
+		var recipe models.Recipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return recipes, nil
+}
+
+// SearchRecipes runs a full-text search over the recipe name, description
+// and long description, best matches first. Name matches weigh the most.
+// query accepts web search syntax ("quoted phrases", -excluded, or).
+func (s *service) SearchRecipes(ctx context.Context, query string, limit int, offset int) ([]models.RecipeSearchResult, error) {
+
+	rows, err := s.db.Query(ctx, recipeSearchQuery, query, min(limit, MaxListRows), offset)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.RecipeSearchResult{}
+
+	for rows.Next() {
+		var result models.RecipeSearchResult
+		recipe := &result.Recipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId, &result.Rank, &result.NameHighlight, &result.DescriptionSnippet); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetRandomRecipe returns a random recipe, optionally restricted to a
+// category. It returns nil when there is no matching recipe.
+func (s *service) GetRandomRecipe(ctx context.Context, category string) (*models.Recipe, error) {
+
+	query := `
+		SELECT r.id, r.uuid, r.name, r.description, r.long_description, r.imageurl, r.category_id
+		FROM recipe r
+		LEFT JOIN category c ON r.category_id = c.id
+		WHERE $1 = '' OR lower(c.name) = lower($1)
+		ORDER BY random()
+		LIMIT 1
+	`
+
+	var recipe models.Recipe
+
+	err := s.db.QueryRow(ctx, query, category).Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &recipe, nil
+}
+
+// GetRandomRecipeMatching returns a random recipe among those matching
+// filter, or nil when none does.
+func (s *service) GetRandomRecipeMatching(ctx context.Context, filter models.RecipeFilter) (*models.Recipe, error) {
+
+	query, args := recipesListQuery(filter)
+	query = `SELECT id, uuid, name, description, long_description, imageurl, category_id FROM (` + query + `) matching ORDER BY random() LIMIT 1`
+
+	var recipe models.Recipe
+
+	err := s.db.QueryRow(ctx, query, args...).Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &recipe, nil
+}
+
+func (s *service) GetRecipeWithIngredients(ctx context.Context, recipeId int) (*models.RecipeWithIngredients, error) {
+
+	log.Printf("Getting recipe with ingredients")
+
+	var recipe models.Recipe
+
+	err := s.db.QueryRow(ctx, recipeByIdQuery, recipeId).Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId, &recipe.CustomFields)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, recipeIngredientsQuery, recipeId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ingredients []models.Ingedient
+
+	for rows.Next() {
+		var ingredient models.Ingedient
+
+		if err := scanIngredient(rows, &ingredient); err != nil {
+			return nil, err
+		}
+
+		ingredients = append(ingredients, ingredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	steps, err := s.GetRecipeSteps(ctx, recipeId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.GetRecipeTags(ctx, recipeId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RecipeWithIngredients{
+		Recipe:      recipe,
+		Ingredients: ingredients,
+		Nutrition:   models.NutritionOf(ingredients),
+		Steps:       steps,
+		Tags:        tags,
+	}, nil
+
+}
+
+// UpdateRecipe updates a recipe and makes ingredientIds its ingredient list
+// in one transaction, so an unknown ingredient leaves the recipe as it was. It
+// returns ErrNotFound when the recipe doesn't exist and ErrUnknownReference
+// when an ingredient doesn't.
+func (s *service) UpdateRecipe(ctx context.Context, id int, name string, description string, url string, ingredientIds []int) error {
+
+	updateRecipeQuery := `
+		UPDATE recipe 
+		SET name = $2, description = $3, imageurl = $4
+		WHERE id = $1
+	`
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, updateRecipeQuery, id, name, description, nullIfEmpty(url))
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := replaceRecipeIngredients(ctx, tx, id, ingredientIds); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (s *service) UpdateRecipeImage(ctx context.Context, id int, url string) error {
+
+	tag, err := s.db.Exec(ctx, `UPDATE recipe SET imageurl = $2 WHERE id = $1`, id, url)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteRecipe removes a recipe together with its ingredient links in a
+// single transaction.
+func (s *service) DeleteRecipe(ctx context.Context, id int) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM ingredient_recipe WHERE recipe_id = $1`, id); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM recipe WHERE id = $1`, id)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReplaceRecipeIngredients makes ingredientIds the complete ingredient list
+// of a recipe in one transaction. Links that stay are left untouched and
+// duplicated links are collapsed into one.
+func (s *service) ReplaceRecipeIngredients(ctx context.Context, recipeId int, ingredientIds []int) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockRecipe(ctx, tx, recipeId); err != nil {
+		return err
+	}
+
+	if err := replaceRecipeIngredients(ctx, tx, recipeId, ingredientIds); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// replaceRecipeIngredients makes ingredientIds the complete ingredient list
+// of a recipe in tx. See ReplaceRecipeIngredients.
+func replaceRecipeIngredients(ctx context.Context, tx pgx.Tx, recipeId int, ingredientIds []int) error {
+
+	if ingredientIds == nil {
+		ingredientIds = []int{}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM ingredient_recipe WHERE recipe_id = $1 AND NOT (ingredient_id = ANY($2))`, recipeId, ingredientIds); err != nil {
+		return err
+	}
+
+	dedupe := `
+		DELETE FROM ingredient_recipe a
+		USING ingredient_recipe b
+		WHERE a.recipe_id = $1 AND b.recipe_id = $1 AND a.ingredient_id = b.ingredient_id AND a.id > b.id
+	`
+
+	if _, err := tx.Exec(ctx, dedupe, recipeId); err != nil {
+		return err
+	}
+
+	insert := `
+		INSERT INTO ingredient_recipe (ingredient_id, recipe_id)
+		SELECT DISTINCT i, $1::int FROM unnest($2::int[]) i
+		WHERE NOT EXISTS (SELECT 1 FROM ingredient_recipe ir WHERE ir.recipe_id = $1 AND ir.ingredient_id = i)
+	`
+
+	_, err := tx.Exec(ctx, insert, recipeId, ingredientIds)
+
+	if isForeignKeyViolation(err) {
+		return ErrUnknownReference
+	}
+
+	return err
+}
+
+// batchSender is implemented by both the pool and a transaction.
+type batchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+func insertRecipeIngredients(ctx context.Context, db batchSender, recipeId int, ingredientIds []int) error {
+
+	stmt := `INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES($1,$2)`
+
+	batch := &pgx.Batch{}
+
+	for _, ingredientId := range ingredientIds {
+		batch.Queue(stmt, ingredientId, recipeId)
+	}
+
+	results := db.SendBatch(ctx, batch)
+
+	for range ingredientIds {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return err
+		}
+	}
+
+	return results.Close()
+}
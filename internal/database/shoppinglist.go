@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ShoppingListRepository keeps the shopping lists made from recipes.
+type ShoppingListRepository interface {
+	CreateShoppingList(ctx context.Context, recipeIds []int) (int, error)
+	GetShoppingList(ctx context.Context, id int) (*models.ShoppingList, error)
+	CheckShoppingListItem(ctx context.Context, listId int, itemId int, checked *bool) (models.ShoppingListItem, error)
+	DeleteShoppingList(ctx context.Context, id int) error
+	ShoppingListIdByUUID(ctx context.Context, id string) (int, error)
+}
+
+// CreateShoppingList makes a list of the ingredients the recipes need that
+// aren't available, one item per ingredient. It returns ErrUnknownReference
+// when a recipe doesn't exist.
+func (s *service) CreateShoppingList(ctx context.Context, recipeIds []int) (int, error) {
+
+	recipeIds = slices.Clone(recipeIds)
+	slices.Sort(recipeIds)
+	recipeIds = slices.Compact(recipeIds)
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return -1, err
+	}
+	defer tx.Rollback(ctx)
+
+	var found int
+
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM recipe WHERE id = ANY($1)`, recipeIds).Scan(&found); err != nil {
+		return -1, err
+	}
+
+	if found != len(recipeIds) {
+		return -1, ErrUnknownReference
+	}
+
+	var id int
+
+	if err := tx.QueryRow(ctx, `INSERT INTO shopping_list (uuid, recipe_ids) VALUES($1,$2) RETURNING id`, newUUID(), recipeIds).Scan(&id); err != nil {
+		return -1, err
+	}
+
+	items := `
+		INSERT INTO shopping_list_item (shopping_list_id, ingredient_id, name, amount, quantity, unit, recipe_ids)
+		SELECT $1, i.id, i.name, i.amount, i.quantity, i.unit, array_agg(DISTINCT ir.recipe_id ORDER BY ir.recipe_id)
+		FROM ingredient_recipe ir
+		JOIN ingredient i ON i.id = ir.ingredient_id
+		WHERE ir.recipe_id = ANY($2) AND NOT COALESCE(i.isavailable, false)
+		GROUP BY i.id
+		ORDER BY i.name
+	`
+
+	if _, err := tx.Exec(ctx, items, id, recipeIds); err != nil {
+		return -1, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+func (s *service) GetShoppingList(ctx context.Context, id int) (*models.ShoppingList, error) {
+
+	list := models.ShoppingList{Items: []models.ShoppingListItem{}}
+
+	err := s.db.QueryRow(ctx, `SELECT id, uuid, recipe_ids, created_at FROM shopping_list WHERE id = $1`, id).
+		Scan(&list.Id, &list.Uuid, &list.RecipeIds, &list.CreatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT id, ingredient_id, name, amount, quantity, unit, recipe_ids, checked
+		FROM shopping_list_item
+		WHERE shopping_list_id = $1
+		ORDER BY id
+	`, id)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.ShoppingListItem
+		if err := rows.Scan(&item.Id, &item.IngredientId, &item.Name, &item.Amount, &item.Quantity, &item.Unit, &item.RecipeIds, &item.Checked); err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// CheckShoppingListItem sets whether an item of a list is checked off, or
+// flips it when checked is nil. It returns ErrNotFound when the list has no
+// such item.
+func (s *service) CheckShoppingListItem(ctx context.Context, listId int, itemId int, checked *bool) (models.ShoppingListItem, error) {
+
+	stmt := `
+		UPDATE shopping_list_item SET checked = COALESCE($3, NOT checked)
+		WHERE shopping_list_id = $1 AND id = $2
+		RETURNING id, ingredient_id, name, amount, quantity, unit, recipe_ids, checked
+	`
+
+	var item models.ShoppingListItem
+
+	err := s.db.QueryRow(ctx, stmt, listId, itemId, checked).
+		Scan(&item.Id, &item.IngredientId, &item.Name, &item.Amount, &item.Quantity, &item.Unit, &item.RecipeIds, &item.Checked)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return item, ErrNotFound
+	}
+
+	return item, err
+}
+
+func (s *service) DeleteShoppingList(ctx context.Context, id int) error {
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM shopping_list WHERE id = $1`, id)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *service) ShoppingListIdByUUID(ctx context.Context, id string) (int, error) {
+	return s.idByUUID(ctx, `SELECT id FROM shopping_list WHERE uuid = $1`, id)
+}
@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// StepRepository keeps the steps of recipes, numbered from 1 in order.
+type StepRepository interface {
+	GetRecipeSteps(ctx context.Context, recipeId int) ([]models.RecipeStep, error)
+	InsertRecipeStep(ctx context.Context, step models.RecipeStep) (int, error)
+	UpdateRecipeStep(ctx context.Context, step models.RecipeStep) error
+	DeleteRecipeStep(ctx context.Context, recipeId int, stepId int) error
+	ReorderRecipeSteps(ctx context.Context, recipeId int, stepIds []int) error
+}
+
+const recipeStepsQuery = `
+	SELECT id, recipe_id, position, text, image_url, duration_minutes
+	FROM recipe_step
+	WHERE recipe_id = $1
+	ORDER BY position
+`
+
+func (s *service) GetRecipeSteps(ctx context.Context, recipeId int) ([]models.RecipeStep, error) {
+
+	rows, err := s.db.Query(ctx, recipeStepsQuery, recipeId)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	steps := []models.RecipeStep{}
+
+	for rows.Next() {
+		var step models.RecipeStep
+		if err := rows.Scan(&step.Id, &step.RecipeId, &step.Position, &step.Text, &step.ImageUrl, &step.DurationMinutes); err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, rows.Err()
+}
+
+// InsertRecipeStep appends a step to the recipe of step and returns its id.
+// It returns ErrNotFound when the recipe doesn't exist.
+func (s *service) InsertRecipeStep(ctx context.Context, step models.RecipeStep) (int, error) {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockRecipe(ctx, tx, step.RecipeId); err != nil {
+		return 0, err
+	}
+
+	stmt := `
+		INSERT INTO recipe_step (recipe_id, position, text, image_url, duration_minutes)
+		SELECT $1, COALESCE(MAX(position), 0) + 1, $2, $3, $4 FROM recipe_step WHERE recipe_id = $1
+		RETURNING id
+	`
+
+	var id int
+
+	if err := tx.QueryRow(ctx, stmt, step.RecipeId, step.Text, step.ImageUrl, step.DurationMinutes).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit(ctx)
+}
+
+// UpdateRecipeStep replaces the text, image and duration of a step, keeping
+// its position. It returns ErrNotFound when the recipe has no such step.
+func (s *service) UpdateRecipeStep(ctx context.Context, step models.RecipeStep) error {
+
+	stmt := `
+		UPDATE recipe_step SET text = $3, image_url = $4, duration_minutes = $5
+		WHERE id = $1 AND recipe_id = $2
+	`
+
+	tag, err := s.db.Exec(ctx, stmt, step.Id, step.RecipeId, step.Text, step.ImageUrl, step.DurationMinutes)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteRecipeStep deletes a step and moves the steps after it up one
+// position. It returns ErrNotFound when the recipe has no such step.
+func (s *service) DeleteRecipeStep(ctx context.Context, recipeId int, stepId int) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var position int
+
+	err = tx.QueryRow(ctx, `DELETE FROM recipe_step WHERE id = $1 AND recipe_id = $2 RETURNING position`, stepId, recipeId).Scan(&position)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE recipe_step SET position = position - 1 WHERE recipe_id = $1 AND position > $2`, recipeId, position); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ReorderRecipeSteps numbers the steps of a recipe in the order of stepIds.
+// It returns ErrNotFound when the recipe doesn't exist, and ErrStepOrder
+// when stepIds doesn't list each of its steps exactly once.
+func (s *service) ReorderRecipeSteps(ctx context.Context, recipeId int, stepIds []int) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockRecipe(ctx, tx, recipeId); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, `SELECT id FROM recipe_step WHERE recipe_id = $1`, recipeId)
+
+	if err != nil {
+		return err
+	}
+
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int])
+
+	if err != nil {
+		return err
+	}
+
+	if !isPermutation(stepIds, current) {
+		return ErrStepOrder
+	}
+
+	stmt := `
+		UPDATE recipe_step s SET position = o.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+		WHERE s.id = o.id AND s.recipe_id = $1
+	`
+
+	if _, err := tx.Exec(ctx, stmt, recipeId, stepIds); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockRecipe locks the row of a recipe for the rest of tx, so writes to its
+// steps are serialized. It returns ErrNotFound when the recipe doesn't
+// exist.
+func lockRecipe(ctx context.Context, tx pgx.Tx, recipeId int) error {
+
+	var locked int
+
+	err := tx.QueryRow(ctx, `SELECT id FROM recipe WHERE id = $1 FOR UPDATE`, recipeId).Scan(&locked)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// isPermutation reports whether ids holds exactly the ids of current, each
+// once.
+func isPermutation(ids []int, current []int) bool {
+	if len(ids) != len(current) {
+		return false
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	slices.Sort(current)
+
+	return slices.Equal(sorted, current)
+}
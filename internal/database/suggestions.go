@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+)
+
+// SuggestionRepository keeps the suggestions sent by users and their
+// review.
+type SuggestionRepository interface {
+	InsertSuggestion(ctx context.Context, kind string, message string, recipeId *int) (int, error)
+	GetSuggestions(ctx context.Context, status string) ([]models.Suggestion, error)
+	ReviewSuggestion(ctx context.Context, id int, status string) error
+}
+
+// InsertSuggestion stores a pending suggestion. recipeId may be nil.
+func (s *service) InsertSuggestion(ctx context.Context, kind string, message string, recipeId *int) (int, error) {
+
+	stmt := `INSERT INTO suggestion (kind, message, recipe_id) VALUES ($1, $2, $3) RETURNING id`
+
+	var id int
+
+	err := s.db.QueryRow(ctx, stmt, kind, message, recipeId).Scan(&id)
+
+	if isForeignKeyViolation(err) {
+		return 0, ErrUnknownReference
+	}
+
+	return id, err
+}
+
+// GetSuggestions returns the suggestions with status, oldest first, so the
+// moderation queue is worked through in order.
+func (s *service) GetSuggestions(ctx context.Context, status string) ([]models.Suggestion, error) {
+
+	query := `
+		SELECT id, kind, message, recipe_id, status, created_at, reviewed_at
+		FROM suggestion
+		WHERE status = $1
+		ORDER BY created_at, id
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(ctx, query, status, MaxListRows)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []models.Suggestion{}
+
+	for rows.Next() {
+		var suggestion models.Suggestion
+		if err := rows.Scan(&suggestion.Id, &suggestion.Kind, &suggestion.Message, &suggestion.RecipeId, &suggestion.Status, &suggestion.CreatedAt, &suggestion.ReviewedAt); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// ReviewSuggestion sets the moderation status of a suggestion.
+func (s *service) ReviewSuggestion(ctx context.Context, id int, status string) error {
+
+	tag, err := s.db.Exec(ctx, `UPDATE suggestion SET status = $2, reviewed_at = now() WHERE id = $1`, id, status)
+
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
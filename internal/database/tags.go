@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TagRepository keeps the tags of recipes. Tag names are stored as given,
+// callers normalize them with models.NormalizeTags.
+type TagRepository interface {
+	GetTags(ctx context.Context) ([]models.Tag, error)
+	GetRecipeTags(ctx context.Context, recipeId int) ([]string, error)
+	AttachRecipeTags(ctx context.Context, recipeId int, tags []string) error
+	DetachRecipeTag(ctx context.Context, recipeId int, tag string) error
+}
+
+// GetTags returns the tags carried by at least one recipe, by name.
+func (s *service) GetTags(ctx context.Context) ([]models.Tag, error) {
+
+	query := `
+		SELECT t.name, COUNT(*)
+		FROM tag t
+		JOIN recipe_tag rt ON rt.tag_id = t.id
+		GROUP BY t.name
+		ORDER BY t.name
+		LIMIT $1
+	`
+
+	rows, err := s.db.Query(ctx, query, MaxListRows)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.Name, &tag.Recipes); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// GetRecipeTags returns the tags of a recipe, by name.
+func (s *service) GetRecipeTags(ctx context.Context, recipeId int) ([]string, error) {
+
+	query := `
+		SELECT t.name
+		FROM recipe_tag rt
+		JOIN tag t ON t.id = rt.tag_id
+		WHERE rt.recipe_id = $1
+		ORDER BY t.name
+	`
+
+	rows, err := s.db.Query(ctx, query, recipeId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := pgx.CollectRows(rows, pgx.RowTo[string])
+
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return tags, err
+}
+
+// AttachRecipeTags adds tags to a recipe, creating the ones that don't
+// exist yet. Tags the recipe already has are left alone. It returns
+// ErrNotFound when the recipe doesn't exist.
+func (s *service) AttachRecipeTags(ctx context.Context, recipeId int, tags []string) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockRecipe(ctx, tx, recipeId); err != nil {
+		return err
+	}
+
+	if err := attachTags(ctx, tx, recipeId, tags); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// attachTags adds tags to a recipe in tx, creating the ones that don't
+// exist yet.
+func attachTags(ctx context.Context, tx pgx.Tx, recipeId int, tags []string) error {
+
+	if _, err := tx.Exec(ctx, `INSERT INTO tag (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, tags); err != nil {
+		return err
+	}
+
+	stmt := `
+		INSERT INTO recipe_tag (recipe_id, tag_id)
+		SELECT $1, id FROM tag WHERE name = ANY($2)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := tx.Exec(ctx, stmt, recipeId, tags)
+
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// DetachRecipeTag removes a tag from a recipe. It returns ErrNotFound when
+// the recipe doesn't carry it.
+func (s *service) DetachRecipeTag(ctx context.Context, recipeId int, tag string) error {
+
+	stmt := `
+		DELETE FROM recipe_tag
+		WHERE recipe_id = $1 AND tag_id = (SELECT id FROM tag WHERE name = $2)
+	`
+
+	result, err := s.db.Exec(ctx, stmt, recipeId, tag)
+
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetIngredientBase makes an ingredient a variant of baseId, or a base
+// again when baseId is nil. It returns ErrUnknownReference when the base
+// doesn't exist and ErrNestedVariant when the base is a variant itself or
+// the ingredient has variants of its own.
+func (s *service) SetIngredientBase(ctx context.Context, id int, baseId *int) error {
+
+	tx, err := s.db.Begin(ctx)
+
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock both rows, so a concurrent change can't nest them after the
+	// checks.
+	rows, err := tx.Query(ctx, `SELECT id, base_id FROM ingredient WHERE id = $1 OR id = $2 ORDER BY id FOR UPDATE`, id, baseId)
+
+	if err != nil {
+		return err
+	}
+
+	bases := map[int]*int{}
+
+	for rows.Next() {
+		var rowId int
+		var rowBase *int
+		if err := rows.Scan(&rowId, &rowBase); err != nil {
+			rows.Close()
+			return err
+		}
+		bases[rowId] = rowBase
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := bases[id]; !ok {
+		return ErrNotFound
+	}
+
+	if baseId != nil {
+		base, ok := bases[*baseId]
+
+		if !ok {
+			return ErrUnknownReference
+		}
+
+		if base != nil || *baseId == id {
+			return ErrNestedVariant
+		}
+
+		var variants int
+
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM ingredient WHERE base_id = $1`, id).Scan(&variants); err != nil {
+			return err
+		}
+
+		if variants > 0 {
+			return ErrNestedVariant
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE ingredient SET base_id = $2 WHERE id = $1`, id, baseId); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetIngredientSubstitutes returns the other ingredients of the family of
+// id: its base and the variants of that base. Available ones come first.
+func (s *service) GetIngredientSubstitutes(ctx context.Context, id int) ([]models.Ingedient, error) {
+
+	var family int
+
+	err := s.db.QueryRow(ctx, `SELECT COALESCE(base_id, id) FROM ingredient WHERE id = $1`, id).Scan(&family)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(ctx, ingredientsQuery+`
+		WHERE COALESCE(i.base_id, i.id) = $1 AND i.id <> $2
+		ORDER BY COALESCE(i.isavailable, false) DESC, i.name
+	`, family, id)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ingredients := []models.Ingedient{}
+
+	for rows.Next() {
+		var ingredient models.Ingedient
+		if err := scanIngredient(rows, &ingredient); err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, rows.Err()
+}
+
+// GetRecipesUsingIngredient returns the recipes using id or any ingredient
+// of its family, so asking for tomato finds the recipes with cherry
+// tomatoes.
+func (s *service) GetRecipesUsingIngredient(ctx context.Context, id int) ([]models.Recipe, error) {
+
+	var family int
+
+	err := s.db.QueryRow(ctx, `SELECT COALESCE(base_id, id) FROM ingredient WHERE id = $1`, id).Scan(&family)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := recipesQuery + `
+		WHERE r.id IN (
+			SELECT ir.recipe_id FROM ingredient_recipe ir
+			JOIN ingredient i ON i.id = ir.ingredient_id
+			WHERE COALESCE(i.base_id, i.id) = $1
+		)
+		ORDER BY r.name
+		LIMIT $2
+	`
+
+	rows, err := s.db.Query(ctx, query, family, MaxListRows)
+
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipes := []models.Recipe{}
+
+	for rows.Next() {
+		var recipe models.Recipe
+		if err := rows.Scan(&recipe.Id, &recipe.Uuid, &recipe.Name, &recipe.Description, &recipe.LongDescription, &recipe.Url, &recipe.CategoryId); err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, rows.Err()
+}
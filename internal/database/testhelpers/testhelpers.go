@@ -0,0 +1,307 @@
+// Package testhelpers gives database tests a real Postgres: a disposable
+// one started with testcontainers, or the one at TEST_DATABASE_URL when it
+// is set. Tests skip when neither is available.
+//
+// Tests of database.Service open it on a Schema of their own, since the
+// service commits its own transactions. Tests of plain SQL use Tx, a
+// transaction on an already migrated schema that is rolled back when the
+// test ends, so their fixtures never leak into other tests.
+//
+// A package using it runs its tests through Run, which stops the container
+// and drops the shared schema afterwards:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testhelpers.Run(m))
+//	}
+package testhelpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/migrations"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// image is the Postgres the container runs, the major version production
+// uses.
+const image = "postgres:16-alpine"
+
+// server is the Postgres shared by the tests of a package, started on first
+// use.
+var server struct {
+	once      sync.Once
+	dsn       string
+	err       error
+	container *postgres.PostgresContainer
+}
+
+// fixtures is the migrated schema the transactions of Tx run in, created on
+// first use.
+var fixtures struct {
+	once   sync.Once
+	schema string
+	pool   *pgxpool.Pool
+	err    error
+}
+
+// Postgres returns the connection string of the test server. It skips the
+// test when there is no TEST_DATABASE_URL and Docker is not available.
+func Postgres(t *testing.T) string {
+	t.Helper()
+
+	server.once.Do(func() {
+		start()
+		if server.err == nil {
+			server.err = createExtensions(server.dsn)
+		}
+	})
+
+	if errors.Is(server.err, errNoDocker) {
+		t.Skipf("TEST_DATABASE_URL not set and %v", server.err)
+	}
+
+	if server.err != nil {
+		t.Fatalf("error starting test database. Err: %v", server.err)
+	}
+
+	return server.dsn
+}
+
+var errNoDocker = errors.New("docker is not available")
+
+func start() {
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		server.dsn = dsn
+		return
+	}
+
+	ctx := context.Background()
+
+	if err := dockerHealth(ctx); err != nil {
+		server.err = fmt.Errorf("%w: %v", errNoDocker, err)
+		return
+	}
+
+	container, err := postgres.Run(ctx, image,
+		postgres.WithDatabase("gastro_galaxy_test"),
+		postgres.WithUsername("test"),
+		postgres.WithPassword("test"),
+		// The server restarts once after the init scripts, so it is ready
+		// the second time it says so.
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		server.err = err
+		return
+	}
+
+	server.container = container
+	server.dsn, server.err = container.ConnectionString(ctx, "sslmode=disable")
+}
+
+// createExtensions installs the extensions the migrations use in public,
+// once for the whole server. Created from a test schema's migration they
+// would land in that schema, out of the search path of the others.
+func createExtensions(dsn string) error {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS pg_trgm SCHEMA public")
+	return err
+}
+
+// dockerHealth reports whether testcontainers can reach Docker. The client
+// panics when it finds no Docker host at all.
+func dockerHealth(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	return provider.Health(ctx)
+}
+
+// Schema creates a throwaway schema in the test server, runs the
+// migrations in it, and returns a connection string that uses it. The
+// schema is dropped when the test finishes.
+func Schema(t *testing.T) string {
+	t.Helper()
+
+	dsn, schema, err := newSchema(Postgres(t))
+	if err != nil {
+		t.Fatalf("error creating test schema. Err: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := dropSchema(dsn, schema); err != nil {
+			t.Logf("error dropping test schema %s. Err: %v", schema, err)
+		}
+	})
+
+	return dsn
+}
+
+// BareSchema is like Schema but leaves the schema empty, for tests that
+// load an older schema before migrating.
+func BareSchema(t *testing.T) string {
+	t.Helper()
+
+	dsn, schema, err := createSchema(Postgres(t))
+	if err != nil {
+		t.Fatalf("error creating test schema. Err: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := dropSchema(dsn, schema); err != nil {
+			t.Logf("error dropping test schema %s. Err: %v", schema, err)
+		}
+	})
+
+	return dsn
+}
+
+// Tx returns a transaction in a migrated schema shared by the tests of the
+// package. It is rolled back when the test finishes, so fixtures loaded in
+// it are seen by that test only.
+func Tx(t *testing.T) pgx.Tx {
+	t.Helper()
+
+	dsn := Postgres(t)
+
+	fixtures.once.Do(func() {
+		var schemaDsn string
+		schemaDsn, fixtures.schema, fixtures.err = newSchema(dsn)
+		if fixtures.err == nil {
+			fixtures.pool, fixtures.err = pgxpool.New(context.Background(), schemaDsn)
+		}
+	})
+
+	if fixtures.err != nil {
+		t.Fatalf("error creating fixtures schema. Err: %v", fixtures.err)
+	}
+
+	ctx := context.Background()
+
+	tx, err := fixtures.pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("error beginning fixture transaction. Err: %v", err)
+	}
+
+	t.Cleanup(func() { tx.Rollback(ctx) })
+
+	return tx
+}
+
+// Run runs the tests of m, then drops the schema of Tx and stops the
+// container, and returns the exit code for os.Exit.
+func Run(m *testing.M) int {
+	code := m.Run()
+
+	if fixtures.pool != nil {
+		fixtures.pool.Close()
+		if err := dropSchema(server.dsn, fixtures.schema); err != nil {
+			log.Printf("error dropping fixtures schema %s: %v", fixtures.schema, err)
+		}
+	}
+
+	if server.container != nil {
+		if err := testcontainers.TerminateContainer(server.container); err != nil {
+			log.Printf("error stopping test database: %v", err)
+		}
+	}
+
+	return code
+}
+
+// newSchema creates a migrated schema in the database at dsn and returns a
+// connection string using it, with its name.
+func newSchema(dsn string) (string, string, error) {
+	schemaDsn, schema, err := createSchema(dsn)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := migrate(context.Background(), schemaDsn); err != nil {
+		dropSchema(dsn, schema)
+		return "", "", err
+	}
+
+	return schemaDsn, schema, nil
+}
+
+// createSchema creates an empty schema in the database at dsn and returns a
+// connection string using it, with its name.
+func createSchema(dsn string) (string, string, error) {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return "", "", err
+	}
+	defer conn.Close(ctx)
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", err
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		return "", "", err
+	}
+
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	return u.String(), schema, nil
+}
+
+func migrate(ctx context.Context, dsn string) error {
+	db, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return migrations.Up(ctx, db)
+}
+
+func dropSchema(dsn string, schema string) error {
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, "DROP SCHEMA "+schema+" CASCADE")
+	return err
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// newUUID returns the public id of a new row. UUIDv7 keeps insertion order,
+// so the unique indexes on the uuid columns stay compact.
+func newUUID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+func (s *service) RecipeIdByUUID(ctx context.Context, id string) (int, error) {
+	return s.idByUUID(ctx, `SELECT id FROM recipe WHERE uuid = $1`, id)
+}
+
+// RecipeUUIDById returns the public uuid of a recipe, or ErrNotFound.
+func (s *service) RecipeUUIDById(ctx context.Context, id int) (string, error) {
+
+	var recipeUUID string
+
+	err := s.db.QueryRow(ctx, `SELECT uuid FROM recipe WHERE id = $1`, id).Scan(&recipeUUID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return recipeUUID, nil
+}
+
+func (s *service) IngredientIdByUUID(ctx context.Context, id string) (int, error) {
+	return s.idByUUID(ctx, `SELECT id FROM ingredient WHERE uuid = $1`, id)
+}
+
+func (s *service) CategoryIdByUUID(ctx context.Context, id string) (int, error) {
+	return s.idByUUID(ctx, `SELECT id FROM category WHERE uuid = $1`, id)
+}
+
+// idByUUID resolves a public uuid to the serial id the rest of the service
+// works with. It returns ErrNotFound when no row has that uuid.
+func (s *service) idByUUID(ctx context.Context, query string, id string) (int, error) {
+
+	var serial int
+
+	err := s.db.QueryRow(ctx, query, id).Scan(&serial)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return serial, nil
+}
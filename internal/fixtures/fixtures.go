@@ -0,0 +1,213 @@
+// Package fixtures loads catalogs described in YAML or JSON files: the
+// categories, the ingredients and the recipes linking them. Fixtures refer
+// to each other by name, so the same file loads into any database.
+package fixtures
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/quantity"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed demo.yaml
+var demo []byte
+
+type Fixture struct {
+	Categories  []Category   `json:"categories"`
+	Ingredients []Ingredient `json:"ingredients"`
+	Recipes     []Recipe     `json:"recipes"`
+}
+
+type Category struct {
+	Name string `json:"name"`
+}
+
+type Ingredient struct {
+	Name        string               `json:"name"`
+	Amount      string               `json:"amount"`
+	ImageUrl    string               `json:"image_url"`
+	IsAvailable bool                 `json:"is_available"`
+	Quantity    *float64             `json:"quantity"`
+	Unit        string               `json:"unit"`
+	Nutrition   *models.NutritionDto `json:"nutrition"`
+}
+
+// Recipe names its category and ingredients, which must be in the same
+// fixture.
+type Recipe struct {
+	Name            string   `json:"name"`
+	Category        string   `json:"category"`
+	Description     string   `json:"description"`
+	LongDescription string   `json:"long_description"`
+	ImageUrl        string   `json:"image_url"`
+	Ingredients     []string `json:"ingredients"`
+}
+
+// Store is the part of database.Service fixtures are loaded with.
+type Store interface {
+	database.CategoryRepository
+	database.IngredientRepository
+	database.RecipeRepository
+}
+
+// IDs maps the names in a loaded fixture to the ids they were given.
+type IDs struct {
+	Categories  map[string]int
+	Ingredients map[string]int
+	Recipes     map[string]int
+}
+
+// Demo is the catalog loaded by demo mode and by the seed command when no
+// file is given.
+func Demo() (Fixture, error) {
+	return Parse(demo, "yaml")
+}
+
+// ReadFile reads a fixture from a .yaml, .yml or .json file.
+func ReadFile(path string) (Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, err
+	}
+	return Parse(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// Parse decodes a fixture in format, "json" or "yaml".
+func Parse(data []byte, format string) (Fixture, error) {
+	var fixture Fixture
+
+	switch strings.ToLower(format) {
+	case "json":
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+		}
+
+	case "yaml", "yml":
+		// Decode through JSON so both formats share the field names.
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+		}
+		if err := json.Unmarshal(converted, &fixture); err != nil {
+			return Fixture{}, fmt.Errorf("invalid fixture: %w", err)
+		}
+
+	default:
+		return Fixture{}, fmt.Errorf("unknown fixture format %q, use json or yaml", format)
+	}
+
+	return fixture, nil
+}
+
+// Load inserts the fixture into store: categories first, then ingredients,
+// then the recipes with their ingredient links. Names are checked before
+// anything is inserted, so a fixture with a dangling reference changes
+// nothing.
+func Load(ctx context.Context, store Store, fixture Fixture) (IDs, error) {
+	if err := fixture.check(); err != nil {
+		return IDs{}, err
+	}
+
+	ids := IDs{
+		Categories:  map[string]int{},
+		Ingredients: map[string]int{},
+		Recipes:     map[string]int{},
+	}
+
+	for _, category := range fixture.Categories {
+		id, err := store.InsertCategory(ctx, category.Name)
+		if err != nil {
+			return ids, fmt.Errorf("category %q: %w", category.Name, err)
+		}
+		ids.Categories[category.Name] = id
+	}
+
+	for _, ingredient := range fixture.Ingredients {
+		if amount, ok := quantity.Parse(ingredient.Amount); ok && ingredient.Quantity == nil && ingredient.Unit == "" {
+			ingredient.Quantity = &amount.Quantity
+			ingredient.Unit = string(amount.Unit)
+		}
+
+		id, err := store.InsertIngredient(ctx, ingredient.Name, ingredient.Amount, ingredient.ImageUrl, ingredient.IsAvailable, ingredient.Quantity, ingredient.Unit, ingredient.Nutrition.Nutrition())
+		if err != nil {
+			return ids, fmt.Errorf("ingredient %q: %w", ingredient.Name, err)
+		}
+		ids.Ingredients[ingredient.Name] = id
+	}
+
+	for _, recipe := range fixture.Recipes {
+		ingredientIds := make([]int, len(recipe.Ingredients))
+		for i, name := range recipe.Ingredients {
+			ingredientIds[i] = ids.Ingredients[name]
+		}
+
+		id, err := store.InsertRecipe(ctx, recipe.Name, recipe.Description, recipe.LongDescription, recipe.ImageUrl, ids.Categories[recipe.Category], ingredientIds)
+		if err != nil {
+			return ids, fmt.Errorf("recipe %q: %w", recipe.Name, err)
+		}
+		ids.Recipes[recipe.Name] = id
+	}
+
+	return ids, nil
+}
+
+// check reports missing or duplicated names and references to names the
+// fixture doesn't define.
+func (f Fixture) check() error {
+	categories := map[string]bool{}
+	for _, category := range f.Categories {
+		if category.Name == "" {
+			return fmt.Errorf("category without a name")
+		}
+		if categories[category.Name] {
+			return fmt.Errorf("category %q defined twice", category.Name)
+		}
+		categories[category.Name] = true
+	}
+
+	ingredients := map[string]bool{}
+	for _, ingredient := range f.Ingredients {
+		if ingredient.Name == "" {
+			return fmt.Errorf("ingredient without a name")
+		}
+		if ingredients[ingredient.Name] {
+			return fmt.Errorf("ingredient %q defined twice", ingredient.Name)
+		}
+		ingredients[ingredient.Name] = true
+	}
+
+	recipes := map[string]bool{}
+	for _, recipe := range f.Recipes {
+		if recipe.Name == "" {
+			return fmt.Errorf("recipe without a name")
+		}
+		if recipes[recipe.Name] {
+			return fmt.Errorf("recipe %q defined twice", recipe.Name)
+		}
+		recipes[recipe.Name] = true
+
+		if !categories[recipe.Category] {
+			return fmt.Errorf("recipe %q: unknown category %q", recipe.Name, recipe.Category)
+		}
+		for _, name := range recipe.Ingredients {
+			if !ingredients[name] {
+				return fmt.Errorf("recipe %q: unknown ingredient %q", recipe.Name, name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Package httperr turns errors into the JSON error responses of the API.
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Error is an error response. It is written as
+// {"code": ..., "message": ..., "details": ...}.
+type Error struct {
+	// Status is the HTTP status of the response.
+	Status int `json:"-"`
+
+	// Code is a stable, machine readable name of the error, such as
+	// "not_found". Clients should branch on it rather than on Message.
+	Code string `json:"code"`
+
+	Message string `json:"message"`
+
+	// Details optionally carries more about the error, e.g. which fields
+	// failed validation.
+	Details any `json:"details,omitempty"`
+
+	err error
+}
+
+// New returns an Error with status and message. Its code is derived from
+// the status: 404 becomes "not_found", 422 "unprocessable_entity".
+func New(status int, message string) *Error {
+	return &Error{Status: status, Code: statusCode(status), Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// WithCode replaces the code derived from the status.
+func (e *Error) WithCode(code string) *Error {
+	e.Code = code
+	return e
+}
+
+// WithDetails attaches details to the error.
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+	return e
+}
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// From returns the Error to answer err with. Errors that already are an
+// *Error are returned as is, the database errors get their matching 4xx
+// status and anything else is an internal server error whose message does
+// not leak the cause.
+func From(err error) *Error {
+	var httpErr *Error
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var validation models.ValidationErrors
+	var tooLarge *http.MaxBytesError
+	var pgErr *pgconn.PgError
+
+	var e *Error
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		e = New(http.StatusNotFound, "Not found")
+	case errors.Is(err, database.ErrInUse):
+		e = New(http.StatusConflict, "Still referenced by other records").WithCode("in_use")
+	case errors.Is(err, database.ErrUnknownReference):
+		e = New(http.StatusUnprocessableEntity, "Refers to a record that does not exist").WithCode("unknown_reference")
+	case errors.As(err, &validation):
+		e = New(http.StatusUnprocessableEntity, validation.Error()).WithCode("invalid_input").WithDetails(validation)
+	case errors.As(err, &tooLarge):
+		e = New(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.As(err, &pgErr) && pgErr.Code == uniqueViolation:
+		e = New(http.StatusConflict, "Already exists").WithCode("duplicate")
+	case errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation:
+		e = New(http.StatusUnprocessableEntity, "Refers to a record that does not exist").WithCode("unknown_reference")
+	default:
+		e = New(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	e.err = err
+	return e
+}
+
+// Write answers the request with err as a JSON error response.
+func Write(w http.ResponseWriter, err error) {
+	e := From(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(e)
+}
+
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
@@ -0,0 +1,61 @@
+// Package imaging decodes uploaded images and renders thumbnails of them.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the size of images accepted for decoding, so a small
+// file claiming huge dimensions can't exhaust memory.
+const MaxPixels = 40_000_000
+
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Decode decodes a JPEG, PNG or WebP image after checking its dimensions.
+func Decode(data []byte) (image.Image, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if config.Width*config.Height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	return image.Decode(bytes.NewReader(data))
+}
+
+// Thumbnail scales img down to width, keeping its aspect ratio.
+func Thumbnail(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	thumb := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, bounds, draw.Over, nil)
+	return thumb
+}
+
+// Encode writes img as PNG when format is "png", to keep transparency, and
+// as JPEG otherwise. It returns the bytes and their content type.
+func Encode(img image.Image, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	if format == "png" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
@@ -0,0 +1,105 @@
+package imaging
+
+import (
+	"bytes"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	boldFont     *opentype.Font
+	boldFontOnce sync.Once
+	boldFontErr  error
+)
+
+// Placeholder renders a size×size PNG showing the first letter of name on a
+// background color derived from name, so the same name always gets the
+// same image.
+func Placeholder(name string, size int) ([]byte, error) {
+	boldFontOnce.Do(func() { boldFont, boldFontErr = opentype.Parse(gobold.TTF) })
+	if boldFontErr != nil {
+		return nil, boldFontErr
+	}
+
+	face, err := opentype.NewFace(boldFont, &opentype.FaceOptions{Size: float64(size) / 2, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{placeholderColor(name)}, image.Point{}, draw.Src)
+
+	letter := initial(name)
+	bounds, advance := font.BoundString(face, letter)
+	height := bounds.Max.Y - bounds.Min.Y
+
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  image.White,
+		Face: face,
+		Dot: fixed.Point26_6{
+			X: (fixed.I(size) - advance) / 2,
+			Y: (fixed.I(size)+height)/2 - bounds.Max.Y,
+		},
+	}
+	drawer.DrawString(letter)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func initial(name string) string {
+	for _, r := range strings.TrimSpace(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return string(unicode.ToUpper(r))
+		}
+	}
+	return "?"
+}
+
+// placeholderColor picks a hue from a hash of name, with fixed saturation
+// and lightness so white text stays readable on every color.
+func placeholderColor(name string) color.RGBA {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(strings.TrimSpace(name))))
+	hue := float64(h.Sum32()%360) / 60
+
+	const saturation, lightness = 0.55, 0.45
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g, b = chroma, x, 0
+	case 1:
+		r, g, b = x, chroma, 0
+	case 2:
+		r, g, b = 0, chroma, x
+	case 3:
+		r, g, b = 0, x, chroma
+	case 4:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	m := lightness - chroma/2
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 255}
+}
@@ -0,0 +1,224 @@
+// Package importer reads recipes published on other sites. Pages are
+// fetched once and handed to each Parser in turn until one finds a recipe.
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"gastro-galaxy-back/internal/quantity"
+)
+
+const (
+	fetchTimeout = 15 * time.Second
+	maxPageBytes = 5 << 20
+	userAgent    = "GastroGalaxy-Importer/1.0"
+)
+
+// ErrNoRecipe is returned when no parser finds a recipe in the page.
+var ErrNoRecipe = errors.New("no recipe found in the page")
+
+// ErrForbiddenAddress is returned for URLs that resolve to loopback,
+// private or link-local addresses, so imports can't reach internal
+// services.
+var ErrForbiddenAddress = errors.New("address not allowed")
+
+// Recipe is a recipe read from a page, before it is matched against the
+// catalog.
+type Recipe struct {
+	Name            string
+	Description     string
+	LongDescription string
+	ImageUrl        string
+	// Categories are the categories the page files the recipe under.
+	Categories  []string
+	Ingredients []Ingredient
+}
+
+type Ingredient struct {
+	Name   string
+	Amount string
+}
+
+// Parser finds a recipe in a page. It returns ErrNoRecipe when the page has
+// none it understands.
+type Parser interface {
+	Parse(page []byte, pageURL *url.URL) (*Recipe, error)
+}
+
+type Importer struct {
+	client  *http.Client
+	parsers []Parser
+}
+
+// New returns an importer trying parsers in order, or only the JSON-LD
+// parser when none are given.
+func New(parsers ...Parser) *Importer {
+	if len(parsers) == 0 {
+		parsers = []Parser{JSONLD{}}
+	}
+
+	// No proxy: publicOnly checks the address dialed, which would be the
+	// proxy's rather than the page's.
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: publicOnly}
+
+	return &Importer{
+		client: &http.Client{
+			Timeout:   fetchTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+		parsers: parsers,
+	}
+}
+
+// Import fetches rawURL and returns the recipe the first successful parser
+// reads from it.
+func (i *Importer) Import(ctx context.Context, rawURL string) (*Recipe, error) {
+	pageURL, err := url.Parse(rawURL)
+	if err != nil || (pageURL.Scheme != "http" && pageURL.Scheme != "https") || pageURL.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", rawURL)
+	}
+
+	page, err := i.fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parser := range i.parsers {
+		recipe, err := parser.Parse(page, pageURL)
+		if errors.Is(err, ErrNoRecipe) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return recipe, nil
+	}
+
+	return nil, ErrNoRecipe
+}
+
+func (i *Importer) fetch(ctx context.Context, pageURL *url.URL) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", pageURL, resp.Status)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(page) > maxPageBytes {
+		return nil, fmt.Errorf("fetching %s: page larger than %d bytes", pageURL, maxPageBytes)
+	}
+
+	return page, nil
+}
+
+// publicOnly refuses connections to addresses that aren't publicly
+// routable. It runs after name resolution, so DNS can't be used to get
+// around it.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !isPublic(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+
+	return nil
+}
+
+// nat64 is the well-known NAT64 prefix, whose addresses end in the IPv4
+// address they reach.
+var nat64 = netip.MustParsePrefix("64:ff9b::/96")
+
+// blockedPrefixes are the non-public ranges the netip predicates miss.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+	netip.MustParsePrefix("::/96"),          // IPv4-compatible
+}
+
+// isPublic reports whether ip is publicly routable, judging IPv4-mapped and
+// NAT64 addresses by the IPv4 address they carry.
+func isPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	if nat64.Contains(ip) {
+		embedded := ip.As16()
+		ip = netip.AddrFrom4([4]byte(embedded[12:]))
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// leadingAmount matches a quantity, such as "2", "1/2", "1 ½" or "200-250",
+// and the unit word after it, at the start of an ingredient line.
+var leadingAmount = regexp.MustCompile(`^(` + quantity.NumberPattern + `(?:\s*(?:[-–]|a|to)?\s*` + quantity.NumberPattern + `)*)\s*` +
+	`(?:((?i:` + quantity.UnitPattern + `))(?:\.|\s+|$))?\s*(?i:(?:de|of)\s+)?`)
+
+// SplitIngredient separates the amount at the start of an ingredient line
+// from the ingredient, e.g. "2 cups of flour" into "flour" and "2 cups".
+// Lines without a leading amount are returned whole as the name.
+func SplitIngredient(line string) Ingredient {
+	line = strings.Join(strings.Fields(line), " ")
+
+	match := leadingAmount.FindStringSubmatchIndex(line)
+	if match == nil || match[1] == 0 {
+		return Ingredient{Name: line}
+	}
+
+	name := strings.TrimSpace(line[match[1]:])
+	if name == "" {
+		return Ingredient{Name: line}
+	}
+
+	amount := strings.TrimSpace(line[match[2]:match[3]])
+	if match[4] >= 0 {
+		amount += " " + line[match[4]:match[5]]
+	}
+
+	return Ingredient{Name: name, Amount: amount}
+}
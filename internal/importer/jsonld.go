@@ -0,0 +1,225 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// JSONLD reads the schema.org Recipe metadata most recipe sites embed in
+// <script type="application/ld+json"> blocks.
+type JSONLD struct{}
+
+func (JSONLD) Parse(page []byte, pageURL *url.URL) (*Recipe, error) {
+	for _, block := range ldJSONBlocks(page) {
+		var doc any
+		if err := json.Unmarshal(block, &doc); err != nil {
+			// Sites get their metadata wrong often enough that a broken
+			// block shouldn't hide a good one.
+			continue
+		}
+
+		if node := findRecipe(doc); node != nil {
+			return recipeFromNode(node, pageURL), nil
+		}
+	}
+
+	return nil, ErrNoRecipe
+}
+
+// ldJSONBlocks returns the contents of the JSON-LD script tags of page.
+func ldJSONBlocks(page []byte) [][]byte {
+	var blocks [][]byte
+
+	tokens := html.NewTokenizer(bytes.NewReader(page))
+	inBlock := false
+
+	for {
+		switch tokens.Next() {
+		case html.ErrorToken:
+			return blocks
+
+		case html.StartTagToken:
+			name, hasAttr := tokens.TagName()
+			if string(name) != "script" || !hasAttr {
+				continue
+			}
+			for {
+				key, value, more := tokens.TagAttr()
+				if string(key) == "type" && strings.EqualFold(strings.TrimSpace(string(value)), "application/ld+json") {
+					inBlock = true
+				}
+				if !more {
+					break
+				}
+			}
+
+		case html.TextToken:
+			if inBlock {
+				blocks = append(blocks, bytes.Clone(tokens.Text()))
+			}
+
+		case html.EndTagToken:
+			inBlock = false
+		}
+	}
+}
+
+// findRecipe looks for a node of type Recipe in a JSON-LD document, which
+// may be the node itself, a list of nodes or a @graph.
+func findRecipe(doc any) map[string]any {
+	switch value := doc.(type) {
+	case []any:
+		for _, item := range value {
+			if node := findRecipe(item); node != nil {
+				return node
+			}
+		}
+
+	case map[string]any:
+		if hasType(value, "Recipe") {
+			return value
+		}
+		if graph, ok := value["@graph"]; ok {
+			return findRecipe(graph)
+		}
+		if entity, ok := value["mainEntity"]; ok {
+			return findRecipe(entity)
+		}
+	}
+
+	return nil
+}
+
+func hasType(node map[string]any, name string) bool {
+	for _, t := range stringList(node["@type"]) {
+		if t == name || strings.HasSuffix(t, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+func recipeFromNode(node map[string]any, pageURL *url.URL) *Recipe {
+	recipe := &Recipe{
+		Name:            text(node["name"]),
+		Description:     text(node["description"]),
+		LongDescription: instructions(node["recipeInstructions"]),
+		ImageUrl:        image(node["image"], pageURL),
+	}
+
+	for _, category := range stringList(node["recipeCategory"]) {
+		for _, name := range strings.Split(category, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				recipe.Categories = append(recipe.Categories, name)
+			}
+		}
+	}
+
+	lines := stringList(node["recipeIngredient"])
+	if len(lines) == 0 {
+		// The property was called ingredients before schema.org renamed it.
+		lines = stringList(node["ingredients"])
+	}
+	for _, line := range lines {
+		if line = html.UnescapeString(strings.TrimSpace(line)); line != "" {
+			recipe.Ingredients = append(recipe.Ingredients, SplitIngredient(line))
+		}
+	}
+
+	return recipe
+}
+
+// instructions flattens recipeInstructions, a text or a list of texts,
+// HowToSteps and HowToSections, into one step per line.
+func instructions(value any) string {
+	var steps []string
+
+	var walk func(any)
+	walk = func(value any) {
+		switch v := value.(type) {
+		case string:
+			if step := text(v); step != "" {
+				steps = append(steps, step)
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			if items, ok := v["itemListElement"]; ok {
+				walk(items)
+			} else {
+				walk(v["text"])
+			}
+		}
+	}
+	walk(value)
+
+	return strings.Join(steps, "\n")
+}
+
+// image returns the URL of the first image, which may be given as a URL,
+// an ImageObject or a list of either, resolved against the page URL.
+func image(value any, pageURL *url.URL) string {
+	switch v := value.(type) {
+	case string:
+		ref, err := url.Parse(strings.TrimSpace(v))
+		if err != nil || v == "" {
+			return ""
+		}
+		return pageURL.ResolveReference(ref).String()
+	case []any:
+		for _, item := range v {
+			if u := image(item, pageURL); u != "" {
+				return u
+			}
+		}
+	case map[string]any:
+		return image(v["url"], pageURL)
+	}
+	return ""
+}
+
+// text returns a JSON-LD text value with HTML tags and entities removed and
+// the whitespace collapsed.
+func text(value any) string {
+	s, _ := value.(string)
+	if s == "" {
+		return ""
+	}
+
+	var out strings.Builder
+	tokens := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch tokens.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(out.String()), " ")
+		case html.TextToken:
+			out.Write(tokens.Text())
+		default:
+			out.WriteByte(' ')
+		}
+	}
+}
+
+// stringList reads a JSON-LD value that may be a single string or a list of
+// them.
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+// Package jsonschema validates JSON values against the subset of JSON Schema
+// custom fields need: type, enum, const, string length and pattern, number
+// bounds, array items and length, and object properties. Schemas using any
+// other keyword are refused rather than silently not enforced.
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema is a compiled schema.
+type Schema struct {
+	types            []string
+	enum             []any
+	minLength        *int
+	maxLength        *int
+	pattern          *regexp.Regexp
+	minimum          *float64
+	maximum          *float64
+	exclusiveMinimum *float64
+	exclusiveMaximum *float64
+	items            *Schema
+	minItems         *int
+	maxItems         *int
+	properties       map[string]*Schema
+	required         []string
+	// additional validates the properties not in properties. noAdditional
+	// refuses them.
+	additional   *Schema
+	noAdditional bool
+}
+
+var types = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true, "array": true, "object": true, "null": true,
+}
+
+// annotations are keywords that don't constrain values.
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "default": true, "examples": true,
+}
+
+// Compile reads a schema, and returns an error naming the keyword when it
+// is malformed or not supported.
+func Compile(data []byte) (*Schema, error) {
+	return compile(data, "")
+}
+
+func compile(data []byte, path string) (*Schema, error) {
+	var keywords map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &keywords); err != nil || keywords == nil {
+		return nil, fmt.Errorf("%smust be a JSON object", at(path))
+	}
+
+	s := &Schema{}
+
+	// Keywords are read in order, so the error reported for a schema with
+	// several problems is always the same.
+	names := make([]string, 0, len(keywords))
+	for name := range keywords {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		raw := keywords[name]
+		var err error
+
+		switch name {
+		case "type":
+			err = s.compileType(raw)
+		case "enum":
+			err = json.Unmarshal(raw, &s.enum)
+			if err == nil && len(s.enum) == 0 {
+				err = errors.New("must not be empty")
+			}
+		case "const":
+			var value any
+			err = json.Unmarshal(raw, &value)
+			s.enum = []any{value}
+		case "minLength":
+			s.minLength, err = count(raw)
+		case "maxLength":
+			s.maxLength, err = count(raw)
+		case "pattern":
+			var pattern string
+			if err = json.Unmarshal(raw, &pattern); err == nil {
+				s.pattern, err = regexp.Compile(pattern)
+			}
+		case "minimum":
+			s.minimum, err = number(raw)
+		case "maximum":
+			s.maximum, err = number(raw)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum, err = number(raw)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum, err = number(raw)
+		case "items":
+			s.items, err = compile(raw, path+"/items")
+		case "minItems":
+			s.minItems, err = count(raw)
+		case "maxItems":
+			s.maxItems, err = count(raw)
+		case "properties":
+			err = s.compileProperties(raw, path)
+		case "required":
+			err = json.Unmarshal(raw, &s.required)
+		case "additionalProperties":
+			var allowed bool
+			if json.Unmarshal(raw, &allowed) == nil {
+				s.noAdditional = !allowed
+			} else {
+				s.additional, err = compile(raw, path+"/additionalProperties")
+			}
+		default:
+			if !annotations[name] {
+				return nil, fmt.Errorf("%skeyword %q is not supported", at(path), name)
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s%s: %w", at(path), name, err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Schema) compileType(raw json.RawMessage) error {
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		s.types = []string{one}
+	} else if err := json.Unmarshal(raw, &s.types); err != nil {
+		return errors.New("must be a type name or a list of them")
+	}
+
+	for _, t := range s.types {
+		if !types[t] {
+			return fmt.Errorf("unknown type %q", t)
+		}
+	}
+	return nil
+}
+
+func (s *Schema) compileProperties(raw json.RawMessage, path string) error {
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &properties); err != nil {
+		return errors.New("must be an object of schemas")
+	}
+
+	s.properties = make(map[string]*Schema, len(properties))
+	for name, property := range properties {
+		compiled, err := compile(property, path+"/properties/"+name)
+		if err != nil {
+			return err
+		}
+		s.properties[name] = compiled
+	}
+	return nil
+}
+
+func count(raw json.RawMessage) (*int, error) {
+	var n int
+	if err := json.Unmarshal(raw, &n); err != nil || n < 0 {
+		return nil, errors.New("must be a non-negative integer")
+	}
+	return &n, nil
+}
+
+func number(raw json.RawMessage) (*float64, error) {
+	var n float64
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return nil, errors.New("must be a number")
+	}
+	return &n, nil
+}
+
+func at(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + ": "
+}
+
+// Validate returns an error describing the first way data breaks the
+// schema, or nil when it doesn't.
+func (s *Schema) Validate(data []byte) error {
+	var value any
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.New("is not valid JSON")
+	}
+
+	return s.validate(value, "")
+}
+
+func (s *Schema) validate(value any, path string) error {
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%s"+format, append([]any{at(strings.TrimPrefix(path, "/"))}, args...)...)
+	}
+
+	if len(s.types) > 0 && !hasAnyType(s.types, value) {
+		return fail("must be %s", strings.Join(s.types, " or "))
+	}
+
+	if s.enum != nil {
+		found := false
+		for _, allowed := range s.enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fail("must be one of the allowed values")
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+		if s.minLength != nil && length < *s.minLength {
+			return fail("must be at least %d characters", *s.minLength)
+		}
+		if s.maxLength != nil && length > *s.maxLength {
+			return fail("must be at most %d characters", *s.maxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return fail("must match %s", s.pattern)
+		}
+
+	case float64:
+		if s.minimum != nil && v < *s.minimum {
+			return fail("must be at least %v", *s.minimum)
+		}
+		if s.maximum != nil && v > *s.maximum {
+			return fail("must be at most %v", *s.maximum)
+		}
+		if s.exclusiveMinimum != nil && v <= *s.exclusiveMinimum {
+			return fail("must be above %v", *s.exclusiveMinimum)
+		}
+		if s.exclusiveMaximum != nil && v >= *s.exclusiveMaximum {
+			return fail("must be below %v", *s.exclusiveMaximum)
+		}
+
+	case []any:
+		if s.minItems != nil && len(v) < *s.minItems {
+			return fail("must have at least %d items", *s.minItems)
+		}
+		if s.maxItems != nil && len(v) > *s.maxItems {
+			return fail("must have at most %d items", *s.maxItems)
+		}
+		if s.items != nil {
+			for i, item := range v {
+				if err := s.items.validate(item, fmt.Sprintf("%s/%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case map[string]any:
+		for _, name := range s.required {
+			if _, ok := v[name]; !ok {
+				return fail("must have %q", name)
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, known := s.properties[name]
+			switch {
+			case known:
+			case s.noAdditional:
+				return fail("must not have %q", name)
+			case s.additional != nil:
+				property = s.additional
+			default:
+				continue
+			}
+			if err := property.validate(v[name], path+"/"+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasAnyType(types []string, value any) bool {
+	for _, t := range types {
+		if hasType(value, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasType(value any, t string) bool {
+	switch v := value.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case float64:
+		return t == "number" || (t == "integer" && v == math.Trunc(v))
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	}
+	return false
+}
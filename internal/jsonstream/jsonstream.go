@@ -0,0 +1,61 @@
+package jsonstream
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// flushEvery is how many elements are written between flushes, so clients
+// start receiving data early without a syscall per element.
+const flushEvery = 100
+
+// ArrayWriter writes a JSON array one element at a time, so large results
+// can be sent without building them in memory first.
+type ArrayWriter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	flusher http.Flusher
+	count   int
+}
+
+func NewArrayWriter(w io.Writer) *ArrayWriter {
+	flusher, _ := w.(http.Flusher)
+	return &ArrayWriter{w: w, enc: json.NewEncoder(w), flusher: flusher}
+}
+
+// Started reports whether anything has been written yet. Until then the
+// caller is still free to send an error response instead.
+func (a *ArrayWriter) Started() bool {
+	return a.count > 0
+}
+
+// Write appends v to the array.
+func (a *ArrayWriter) Write(v any) error {
+	sep := ","
+	if a.count == 0 {
+		sep = "["
+	}
+	if _, err := io.WriteString(a.w, sep); err != nil {
+		return err
+	}
+	if err := a.enc.Encode(v); err != nil {
+		return err
+	}
+
+	a.count++
+	if a.flusher != nil && a.count%flushEvery == 0 {
+		a.flusher.Flush()
+	}
+	return nil
+}
+
+// Close terminates the array. An array without elements is written as [].
+func (a *ArrayWriter) Close() error {
+	end := "]\n"
+	if a.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
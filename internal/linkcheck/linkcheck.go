@@ -0,0 +1,138 @@
+// Package linkcheck periodically checks that the external image URLs stored
+// on recipes and ingredients still load, and flags the ones that don't.
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/metrics"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	batchSize      = 100
+	requestTimeout = 10 * time.Second
+	userAgent      = "GastroGalaxyLinkChecker/1.0"
+)
+
+// Checker checks the stored image links every interval.
+type Checker struct {
+	db       database.Service
+	client   *http.Client
+	interval time.Duration
+}
+
+// New creates a Checker that checks the links stored in db every interval.
+func New(db database.Service, interval time.Duration) *Checker {
+	return &Checker{
+		db:       db,
+		client:   &http.Client{Timeout: requestTimeout},
+		interval: interval,
+	}
+}
+
+// Run checks the links right away and then every interval, until ctx is
+// cancelled.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.CheckAll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("linkcheck: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll checks every stored link once and records the results.
+func (c *Checker) CheckAll(ctx context.Context) error {
+	start := time.Now()
+	checked, broken := 0, 0
+
+	for {
+		links, err := c.db.GetImageLinksToCheck(ctx, start, batchSize)
+
+		if err != nil {
+			return err
+		}
+
+		if len(links) == 0 {
+			break
+		}
+
+		for _, link := range links {
+			checkErr := ""
+
+			if err := c.Check(ctx, link.Url); err != nil {
+				checkErr = err.Error()
+				broken++
+			}
+
+			if err := c.db.SetImageLinkStatus(ctx, link, checkErr, time.Now()); err != nil {
+				return err
+			}
+			checked++
+		}
+	}
+
+	metrics.BrokenImageLinks.Set(float64(broken))
+
+	if checked > 0 {
+		log.Printf("linkcheck: checked %d links, %d broken", checked, broken)
+	}
+
+	return nil
+}
+
+// Check returns an error when url can't be loaded. It sends a HEAD request
+// and falls back to GET for servers that don't support HEAD.
+func (c *Checker) Check(ctx context.Context, url string) error {
+	status, err := c.request(ctx, http.MethodHead, url)
+
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = c.request(ctx, http.MethodGet, url)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if status >= http.StatusBadRequest {
+		return fmt.Errorf("%d %s", status, http.StatusText(status))
+	}
+
+	return nil
+}
+
+func (c *Checker) request(ctx context.Context, method string, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Read a little of the body so the connection can be reused, without
+	// downloading whole images.
+	io.CopyN(io.Discard, resp.Body, 4<<10)
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,129 @@
+// Package metrics exposes Prometheus metrics for the HTTP API and for
+// business events, such as recipes being created or searches being run.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "gastro_galaxy"
+
+// HTTP metrics.
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to answer HTTP requests by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// Business events.
+var (
+	// RecipesCreated counts recipes created through the API.
+	RecipesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recipes_created_total",
+		Help:      "Recipes created.",
+	})
+
+	// IngredientsCreated counts ingredients created through the API.
+	IngredientsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ingredients_created_total",
+		Help:      "Ingredients created.",
+	})
+
+	// Searches counts recipe searches by kind: "fulltext" for
+	// /recipes/search, "bot" for chat bot searches.
+	Searches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "searches_total",
+		Help:      "Recipe searches run, by kind.",
+	}, []string{"kind"})
+
+	// ImportsCompleted counts finished imports by source.
+	ImportsCompleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "imports_completed_total",
+		Help:      "Imports completed, by source.",
+	}, []string{"source"})
+
+	// ShoppingListsGenerated counts shopping lists built from recipes.
+	ShoppingListsGenerated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "shopping_lists_generated_total",
+		Help:      "Shopping lists generated.",
+	})
+
+	// ImagesUploaded counts images uploaded through the API.
+	ImagesUploaded = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "images_uploaded_total",
+		Help:      "Images uploaded.",
+	})
+
+	// BrokenImageLinks is the number of stored image URLs that failed the
+	// latest link check.
+	BrokenImageLinks = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "broken_image_links",
+		Help:      "Stored image URLs that failed their latest check.",
+	})
+)
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records the count and duration of requests. Requests are
+// labelled with their chi route pattern rather than their path, so ids don't
+// turn into separate series.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		route := "unmatched"
+		if ctx := chi.RouteContext(r.Context()); ctx != nil {
+			if pattern := ctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+
+		httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(recorder.status)).Inc()
+		httpDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Flush() {
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
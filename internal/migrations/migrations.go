@@ -0,0 +1,311 @@
+// Package migrations keeps the database schema up to date. Migrations are
+// the SQL files embedded from sql/, named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, and applied in version order.
+//
+// An up file may declare the oldest version whose code still runs against
+// the schema it produces with a line such as
+//
+//	-- compatible-from: 9
+//
+// which lets builds stopping at version 9 keep serving while a newer build
+// migrates the database during a rolling deploy. Migrations without it are
+// only compatible with builds that know them.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// lockKey is the advisory lock held while migrating, so several instances
+// starting at once don't apply the same migration twice.
+const lockKey = 7_263_581_114
+
+// ErrIncompatibleSchema is returned by Check when this build can't run
+// against the database's schema.
+var ErrIncompatibleSchema = errors.New("incompatible database schema")
+
+var compatibleFrom = regexp.MustCompile(`(?m)^--\s*compatible-from:\s*(\d+)\s*$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// CompatibleFrom is the oldest version whose code runs against the
+	// schema after this migration.
+	CompatibleFrom int
+}
+
+// DB is the part of a pgx pool or connection the migrations need.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// All returns the embedded migrations ordered by version.
+func All() ([]Migration, error) {
+	names, err := fs.Glob(files, "sql/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+
+	for _, name := range names {
+		base := path.Base(name)
+
+		stem, direction, ok := strings.Cut(strings.TrimSuffix(base, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s: name must end in .up.sql or .down.sql", base)
+		}
+
+		number, label, _ := strings.Cut(stem, "_")
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: name must start with a version number", base)
+		}
+
+		body, err := files.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		}
+
+		if direction == "up" {
+			m.Up = string(body)
+			m.CompatibleFrom = version
+
+			if match := compatibleFrom.FindStringSubmatch(m.Up); match != nil {
+				m.CompatibleFrom, _ = strconv.Atoi(match[1])
+				if m.CompatibleFrom > version {
+					return nil, fmt.Errorf("migration %s: compatible-from must not be newer than the migration", base)
+				}
+			}
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func Latest() (int, error) {
+	migrations, err := All()
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Up applies every migration newer than the database's version, each in its
+// own transaction.
+func Up(ctx context.Context, db DB) error {
+	migrations, err := All()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		err := inLockedTx(ctx, db, func(tx pgx.Tx) error {
+			version, err := currentVersion(ctx, tx)
+			if err != nil || version >= m.Version {
+				return err
+			}
+
+			if _, err := tx.Exec(ctx, m.Up); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, compatible_from) VALUES ($1, $2)`, m.Version, m.CompatibleFrom); err != nil {
+				return err
+			}
+
+			log.Printf("applied migration %04d_%s", m.Version, m.Name)
+			return nil
+		})
+
+		if err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverts the newest steps migrations applied to the database.
+func Down(ctx context.Context, db DB, steps int) error {
+	migrations, err := All()
+	if err != nil {
+		return err
+	}
+
+	byVersion := map[int]Migration{}
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	for i := 0; i < steps; i++ {
+		done := false
+
+		err := inLockedTx(ctx, db, func(tx pgx.Tx) error {
+			version, err := currentVersion(ctx, tx)
+			if err != nil {
+				return err
+			}
+
+			if version == 0 {
+				done = true
+				return nil
+			}
+
+			m, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("database is at version %d, which this build doesn't know", version)
+			}
+
+			if _, err := tx.Exec(ctx, m.Down); err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+				return err
+			}
+
+			log.Printf("reverted migration %04d_%s", m.Version, m.Name)
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if done {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Version returns the newest migration applied to the database, or 0 when
+// none has been.
+func Version(ctx context.Context, db DB) (int, error) {
+	var version int
+
+	err := inLockedTx(ctx, db, func(tx pgx.Tx) error {
+		var err error
+		version, err = currentVersion(ctx, tx)
+		return err
+	})
+
+	return version, err
+}
+
+// Check returns ErrIncompatibleSchema unless this build can run against
+// the database: the schema must have every embedded migration, and any
+// newer migration must declare itself compatible with this build.
+func Check(ctx context.Context, db DB) error {
+	latest, err := Latest()
+	if err != nil {
+		return err
+	}
+
+	var version, from int
+
+	err = inLockedTx(ctx, db, func(tx pgx.Tx) error {
+		// Every migration newer than this build must be compatible with it,
+		// so the strictest one decides.
+		return tx.QueryRow(ctx, `
+			SELECT COALESCE(MAX(version), 0),
+				COALESCE(MAX(COALESCE(compatible_from, version)) FILTER (WHERE version > $1), 0)
+			FROM schema_migrations
+		`, latest).Scan(&version, &from)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return Compatible(latest, version, from)
+}
+
+// Compatible reports whether a build whose newest migration is latest can
+// run against a schema at version, whose migrations newer than latest
+// support builds from compatibleFrom on.
+func Compatible(latest, version, compatibleFrom int) error {
+	if version < latest {
+		return fmt.Errorf("%w: database is at version %d but this build needs version %d, run the migrations", ErrIncompatibleSchema, version, latest)
+	}
+
+	if compatibleFrom > latest {
+		return fmt.Errorf("%w: database is at version %d, which needs a build with migration %d or newer, but this build stops at %d", ErrIncompatibleSchema, version, compatibleFrom, latest)
+	}
+
+	return nil
+}
+
+func inLockedTx(ctx context.Context, db DB, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, lockKey); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx, `ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS compatible_from INTEGER`); err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func currentVersion(ctx context.Context, tx pgx.Tx) (int, error) {
+	var version int
+	err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
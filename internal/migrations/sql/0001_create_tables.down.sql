@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS banned_word;
+DROP TABLE IF EXISTS ingredient_recipe;
+DROP TABLE IF EXISTS ingredient;
+DROP TABLE IF EXISTS recipe;
+DROP TABLE IF EXISTS category;
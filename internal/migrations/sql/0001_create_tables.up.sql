@@ -0,0 +1,55 @@
+-- The original schema. IF NOT EXISTS lets databases created before
+-- migrations existed adopt this version; the columns and tables init.sql
+-- gained later are added to those that predate them.
+CREATE TABLE IF NOT EXISTS category (
+  id SERIAL PRIMARY KEY,
+  name TEXT
+);
+
+INSERT INTO category (id, name) VALUES
+  (1, 'Pizzas'),
+  (2, 'Hamburgers'),
+  (3, 'Massas'),
+  (4, 'Bolos'),
+  (5, 'Brasileira')
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval('category_id_seq', (SELECT MAX(id) FROM category));
+
+CREATE TABLE IF NOT EXISTS recipe (
+  id SERIAL PRIMARY KEY,
+  name TEXT,
+  description TEXT,
+  imageUrl TEXT,
+  category_id INTEGER,
+  CONSTRAINT fk_category
+    FOREIGN KEY(category_id)
+      REFERENCES category(id)
+);
+
+ALTER TABLE recipe ADD COLUMN IF NOT EXISTS long_description TEXT;
+
+CREATE TABLE IF NOT EXISTS ingredient (
+  id SERIAL PRIMARY KEY,
+  name TEXT,
+  amount TEXT,
+  imageUrl TEXT,
+  isAvailable BOOLEAN
+);
+
+CREATE TABLE IF NOT EXISTS ingredient_recipe (
+  id SERIAL PRIMARY KEY,
+  ingredient_id INTEGER,
+  recipe_id INTEGER,
+  CONSTRAINT fk_ingredient
+    FOREIGN KEY (ingredient_id)
+      REFERENCES ingredient(id),
+  CONSTRAINT fk_recipe
+    FOREIGN KEY (recipe_id)
+      REFERENCES recipe(id)
+);
+
+CREATE TABLE IF NOT EXISTS banned_word (
+  id SERIAL PRIMARY KEY,
+  word TEXT NOT NULL UNIQUE
+);
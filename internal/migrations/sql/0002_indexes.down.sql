@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_ingredient_recipe_ingredient_recipe;
+DROP INDEX IF EXISTS idx_ingredient_recipe_recipe_ingredient;
+DROP INDEX IF EXISTS idx_recipe_search;
+DROP INDEX IF EXISTS idx_recipe_name_trgm;
+DROP INDEX IF EXISTS idx_recipe_category_id;
@@ -0,0 +1,18 @@
+-- Indexes backing the main read paths.
+CREATE EXTENSION IF NOT EXISTS pg_trgm SCHEMA public;
+
+CREATE INDEX IF NOT EXISTS idx_recipe_category_id ON recipe (category_id);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_name_trgm ON recipe USING GIN (name gin_trgm_ops);
+
+-- Full-text search over name, description and long_description. The
+-- expression must match recipeSearchVector in internal/database/queries.go.
+CREATE INDEX IF NOT EXISTS idx_recipe_search ON recipe USING GIN ((
+  setweight(to_tsvector('portuguese', coalesce(name, '')), 'A') ||
+  setweight(to_tsvector('portuguese', coalesce(description, '')), 'B') ||
+  setweight(to_tsvector('portuguese', coalesce(long_description, '')), 'C')
+));
+
+CREATE INDEX IF NOT EXISTS idx_ingredient_recipe_recipe_ingredient ON ingredient_recipe (recipe_id, ingredient_id);
+
+CREATE INDEX IF NOT EXISTS idx_ingredient_recipe_ingredient_recipe ON ingredient_recipe (ingredient_id, recipe_id);
@@ -0,0 +1,5 @@
+ALTER TABLE category DROP COLUMN IF EXISTS uuid;
+ALTER TABLE ingredient DROP COLUMN IF EXISTS uuid;
+ALTER TABLE recipe DROP COLUMN IF EXISTS uuid;
+
+DROP FUNCTION IF EXISTS uuid_generate_v7();
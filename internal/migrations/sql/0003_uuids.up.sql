@@ -0,0 +1,33 @@
+-- Public UUIDv7 ids alongside the serial primary keys, so URLs don't have
+-- to expose sequential integers. The application generates the ids of new
+-- rows; the column default covers rows inserted by plain SQL. Safe to run
+-- more than once.
+
+CREATE OR REPLACE FUNCTION uuid_generate_v7() RETURNS uuid AS $$
+  -- A random (v4) uuid with its first 48 bits replaced by the Unix time in
+  -- milliseconds and the version nibble switched from 4 to 7.
+  SELECT encode(
+    set_bit(
+      set_bit(
+        overlay(uuid_send(gen_random_uuid())
+          PLACING substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint) FROM 3)
+          FROM 1 FOR 6),
+        52, 1),
+      53, 1),
+    'hex')::uuid
+$$ LANGUAGE sql VOLATILE;
+
+ALTER TABLE recipe ADD COLUMN IF NOT EXISTS uuid UUID;
+UPDATE recipe SET uuid = uuid_generate_v7() WHERE uuid IS NULL;
+ALTER TABLE recipe ALTER COLUMN uuid SET DEFAULT uuid_generate_v7(), ALTER COLUMN uuid SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipe_uuid ON recipe (uuid);
+
+ALTER TABLE ingredient ADD COLUMN IF NOT EXISTS uuid UUID;
+UPDATE ingredient SET uuid = uuid_generate_v7() WHERE uuid IS NULL;
+ALTER TABLE ingredient ALTER COLUMN uuid SET DEFAULT uuid_generate_v7(), ALTER COLUMN uuid SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingredient_uuid ON ingredient (uuid);
+
+ALTER TABLE category ADD COLUMN IF NOT EXISTS uuid UUID;
+UPDATE category SET uuid = uuid_generate_v7() WHERE uuid IS NULL;
+ALTER TABLE category ALTER COLUMN uuid SET DEFAULT uuid_generate_v7(), ALTER COLUMN uuid SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_category_uuid ON category (uuid);
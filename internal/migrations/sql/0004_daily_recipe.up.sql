@@ -0,0 +1,7 @@
+-- The recipe featured on each day. Rows are written the first time a day
+-- is requested, or ahead of time when an admin pins a recipe.
+CREATE TABLE daily_recipe (
+  day DATE PRIMARY KEY,
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  pinned BOOLEAN NOT NULL DEFAULT false
+);
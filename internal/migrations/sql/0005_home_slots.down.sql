@@ -0,0 +1 @@
+DROP TABLE home_slot;
@@ -0,0 +1,7 @@
+-- Recipes curated for the landing page, in display order per slot.
+CREATE TABLE home_slot (
+  slot TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  PRIMARY KEY (slot, position)
+);
@@ -0,0 +1,11 @@
+ALTER TABLE ingredient
+  DROP COLUMN image_error,
+  DROP COLUMN image_broken_since,
+  DROP COLUMN image_checked_at,
+  DROP COLUMN image_checked_url;
+
+ALTER TABLE recipe
+  DROP COLUMN image_error,
+  DROP COLUMN image_broken_since,
+  DROP COLUMN image_checked_at,
+  DROP COLUMN image_checked_url;
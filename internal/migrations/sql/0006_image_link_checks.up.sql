@@ -0,0 +1,14 @@
+-- Results of the link checker. image_checked_url is the URL the result is
+-- for, so a result goes stale as soon as imageurl changes.
+-- image_broken_since is NULL while the link works.
+ALTER TABLE recipe
+  ADD COLUMN image_checked_url TEXT,
+  ADD COLUMN image_checked_at TIMESTAMPTZ,
+  ADD COLUMN image_broken_since TIMESTAMPTZ,
+  ADD COLUMN image_error TEXT;
+
+ALTER TABLE ingredient
+  ADD COLUMN image_checked_url TEXT,
+  ADD COLUMN image_checked_at TIMESTAMPTZ,
+  ADD COLUMN image_broken_since TIMESTAMPTZ,
+  ADD COLUMN image_error TEXT;
@@ -0,0 +1 @@
+DROP TABLE analytics_event;
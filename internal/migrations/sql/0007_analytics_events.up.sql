@@ -0,0 +1,10 @@
+-- Anonymous usage events. Nothing identifying is stored: no user, address,
+-- user agent or request id, only what was used and when.
+CREATE TABLE analytics_event (
+  id BIGSERIAL PRIMARY KEY,
+  occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  kind TEXT NOT NULL,
+  name TEXT NOT NULL
+);
+
+CREATE INDEX idx_analytics_event_kind_occurred_at ON analytics_event (kind, occurred_at);
@@ -0,0 +1,13 @@
+-- Recipe ideas and catalog error reports sent by anonymous visitors,
+-- waiting for an admin to review them.
+CREATE TABLE suggestion (
+  id SERIAL PRIMARY KEY,
+  kind TEXT NOT NULL,
+  message TEXT NOT NULL,
+  recipe_id INTEGER REFERENCES recipe(id) ON DELETE SET NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  reviewed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_suggestion_status_created_at ON suggestion (status, created_at);
@@ -0,0 +1,15 @@
+-- Food safety log of the batches prepared from a recipe in kitchen mode.
+-- temperatures holds the readings as [{"stage": "cooking", "celsius": 75}].
+CREATE TABLE prep_log (
+  id SERIAL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  prepared_by TEXT NOT NULL,
+  prepared_at TIMESTAMPTZ NOT NULL,
+  batch_size NUMERIC NOT NULL,
+  batch_unit TEXT NOT NULL DEFAULT '',
+  temperatures JSONB NOT NULL DEFAULT '[]',
+  notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_prep_log_recipe_prepared_at ON prep_log (recipe_id, prepared_at);
+CREATE INDEX idx_prep_log_prepared_at ON prep_log (prepared_at);
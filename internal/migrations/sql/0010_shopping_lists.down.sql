@@ -0,0 +1,2 @@
+DROP TABLE shopping_list_item;
+DROP TABLE shopping_list;
@@ -0,0 +1,22 @@
+-- Shopping lists generated from recipes. Every ingredient the recipes need
+-- and the kitchen doesn't have becomes one item, however many recipes use
+-- it. Items keep a copy of the ingredient name and amount, so the list
+-- doesn't change under the shopper when the catalog does.
+CREATE TABLE shopping_list (
+  id SERIAL PRIMARY KEY,
+  uuid UUID NOT NULL DEFAULT uuid_generate_v7() UNIQUE,
+  recipe_ids INTEGER[] NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE shopping_list_item (
+  id SERIAL PRIMARY KEY,
+  shopping_list_id INTEGER NOT NULL REFERENCES shopping_list(id) ON DELETE CASCADE,
+  ingredient_id INTEGER REFERENCES ingredient(id) ON DELETE SET NULL,
+  name TEXT NOT NULL,
+  amount TEXT,
+  recipe_ids INTEGER[] NOT NULL,
+  checked BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_shopping_list_item_shopping_list_id ON shopping_list_item (shopping_list_id);
@@ -0,0 +1 @@
+DROP TABLE meal_plan;
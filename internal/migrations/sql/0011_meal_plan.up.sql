@@ -0,0 +1,11 @@
+-- compatible-from: 10
+-- The kitchen's meal plan: one recipe per meal of a day. There are no user
+-- accounts, so the whole kitchen shares a single plan.
+CREATE TABLE meal_plan (
+  date DATE NOT NULL,
+  meal_slot TEXT NOT NULL CHECK (meal_slot IN ('breakfast', 'lunch', 'dinner')),
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  PRIMARY KEY (date, meal_slot)
+);
+
+CREATE INDEX idx_meal_plan_recipe_id ON meal_plan (recipe_id);
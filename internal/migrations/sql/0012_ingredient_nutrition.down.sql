@@ -0,0 +1,8 @@
+ALTER TABLE ingredient
+  DROP CONSTRAINT ingredient_nutrition_complete,
+  DROP COLUMN quantity,
+  DROP COLUMN unit,
+  DROP COLUMN calories,
+  DROP COLUMN protein,
+  DROP COLUMN fat,
+  DROP COLUMN carbs;
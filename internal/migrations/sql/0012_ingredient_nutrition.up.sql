@@ -0,0 +1,16 @@
+-- compatible-from: 11
+-- Structured amounts and nutrition facts for ingredients. quantity and unit
+-- sit next to the free text amount, and the nutrient values are given per
+-- one unit: calories in kcal, protein, fat and carbs in grams.
+ALTER TABLE ingredient
+  ADD COLUMN quantity DOUBLE PRECISION CHECK (quantity >= 0),
+  ADD COLUMN unit TEXT,
+  ADD COLUMN calories DOUBLE PRECISION CHECK (calories >= 0),
+  ADD COLUMN protein DOUBLE PRECISION CHECK (protein >= 0),
+  ADD COLUMN fat DOUBLE PRECISION CHECK (fat >= 0),
+  ADD COLUMN carbs DOUBLE PRECISION CHECK (carbs >= 0),
+  ADD CONSTRAINT ingredient_nutrition_complete CHECK (
+    (calories IS NULL) = (protein IS NULL) AND
+    (calories IS NULL) = (fat IS NULL) AND
+    (calories IS NULL) = (carbs IS NULL)
+  );
@@ -0,0 +1,13 @@
+-- compatible-from: 12
+-- Ingredient taxonomy: a variant (cherry tomato, canned tomato) points to
+-- its base ingredient (tomato). Bases have no base themselves, so the
+-- hierarchy is one level deep. Ingredients sharing a base substitute for
+-- each other when matching recipes.
+ALTER TABLE ingredient
+  ADD COLUMN base_id INTEGER REFERENCES ingredient(id) ON DELETE SET NULL,
+  ADD CONSTRAINT ingredient_base_not_self CHECK (base_id <> id);
+
+CREATE INDEX idx_ingredient_base_id ON ingredient (base_id);
+
+-- The family of an ingredient is its base, or itself for bases.
+CREATE INDEX idx_ingredient_family ON ingredient ((COALESCE(base_id, id)));
@@ -0,0 +1,7 @@
+ALTER TABLE shopping_list_item
+  DROP COLUMN quantity,
+  DROP COLUMN unit;
+
+ALTER TABLE ingredient
+  DROP CONSTRAINT ingredient_unit_known,
+  DROP CONSTRAINT ingredient_quantity_unit;
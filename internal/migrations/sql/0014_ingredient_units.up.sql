@@ -0,0 +1,21 @@
+-- Ingredient units become a fixed set, so amounts can be scaled and added
+-- up, and a quantity always comes with its unit. Units outside the set are
+-- cleared with their quantity: the free text amount still holds them, and
+-- the server converts amounts it can read after migrating. Shopping list
+-- items copy the structured amount along with the text.
+UPDATE ingredient SET unit = lower(trim(unit)) WHERE unit IS NOT NULL;
+
+UPDATE ingredient SET quantity = NULL, unit = NULL
+WHERE unit NOT IN ('g', 'kg', 'mg', 'ml', 'l', 'lb', 'oz', 'cup', 'tbsp', 'tsp', 'pinch', 'clove', 'can', 'bunch', 'slice', 'piece');
+
+UPDATE ingredient SET unit = 'piece' WHERE quantity IS NOT NULL AND unit IS NULL;
+
+UPDATE ingredient SET unit = NULL WHERE quantity IS NULL;
+
+ALTER TABLE ingredient
+  ADD CONSTRAINT ingredient_unit_known CHECK (unit IN ('g', 'kg', 'mg', 'ml', 'l', 'lb', 'oz', 'cup', 'tbsp', 'tsp', 'pinch', 'clove', 'can', 'bunch', 'slice', 'piece')),
+  ADD CONSTRAINT ingredient_quantity_unit CHECK ((quantity IS NULL) = (unit IS NULL));
+
+ALTER TABLE shopping_list_item
+  ADD COLUMN quantity DOUBLE PRECISION,
+  ADD COLUMN unit TEXT;
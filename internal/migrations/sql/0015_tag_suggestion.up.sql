@@ -0,0 +1,15 @@
+-- compatible-from: 14
+-- Tags the classifier proposes for recipes, waiting for an admin to review
+-- them. A tag is proposed once per recipe, so rejected ones don't come back.
+CREATE TABLE tag_suggestion (
+  id SERIAL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  tag TEXT NOT NULL,
+  reason TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  reviewed_at TIMESTAMPTZ,
+  UNIQUE (recipe_id, tag)
+);
+
+CREATE INDEX idx_tag_suggestion_status_created_at ON tag_suggestion (status, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE recipe DROP COLUMN custom_fields;
+
+DROP TABLE custom_field;
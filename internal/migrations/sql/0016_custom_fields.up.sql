@@ -0,0 +1,12 @@
+-- compatible-from: 15
+-- Custom fields let the kitchen attach its own structured data to recipes,
+-- e.g. plating notes, a station or a SKU. Each field is defined by a JSON
+-- Schema its values are validated against when written.
+CREATE TABLE custom_field (
+  name TEXT PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]{0,62}$'),
+  schema JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE recipe ADD COLUMN custom_fields JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,11 @@
+-- compatible-from: 16
+-- Bulk photo imports: a ZIP of images attached to recipes by a background
+-- job, and the report of what happened to each file.
+CREATE TABLE image_import (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+  status TEXT NOT NULL DEFAULT 'queued',
+  files INTEGER NOT NULL,
+  results JSONB NOT NULL DEFAULT '[]',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  finished_at TIMESTAMPTZ
+);
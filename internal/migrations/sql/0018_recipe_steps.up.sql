@@ -0,0 +1,13 @@
+-- compatible-from: 17
+-- The procedure of a recipe as ordered steps, in place of one long
+-- description. Positions run from 1 without gaps; the constraint is
+-- deferrable so a reorder can shift them in one statement.
+CREATE TABLE recipe_step (
+  id SERIAL PRIMARY KEY,
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  text TEXT NOT NULL,
+  image_url TEXT,
+  duration_minutes INTEGER,
+  UNIQUE (recipe_id, position) DEFERRABLE
+);
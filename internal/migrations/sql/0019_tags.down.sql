@@ -0,0 +1,2 @@
+DROP TABLE recipe_tag;
+DROP TABLE tag;
@@ -0,0 +1,15 @@
+-- compatible-from: 18
+-- Labels recipes share across categories, such as "vegan" or "quick". Tag
+-- names are slugs. A recipe has any number of tags.
+CREATE TABLE tag (
+  id SERIAL PRIMARY KEY,
+  name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE recipe_tag (
+  recipe_id INTEGER NOT NULL REFERENCES recipe(id) ON DELETE CASCADE,
+  tag_id INTEGER NOT NULL REFERENCES tag(id) ON DELETE CASCADE,
+  PRIMARY KEY (recipe_id, tag_id)
+);
+
+CREATE INDEX idx_recipe_tag_tag_recipe ON recipe_tag (tag_id, recipe_id);
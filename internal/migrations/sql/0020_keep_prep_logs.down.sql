@@ -0,0 +1,8 @@
+-- The logs of deleted recipes can't reference them again and go.
+DELETE FROM prep_log WHERE recipe_id IS NULL;
+
+ALTER TABLE prep_log
+  ALTER COLUMN recipe_id SET NOT NULL,
+  DROP CONSTRAINT prep_log_recipe_id_fkey,
+  ADD CONSTRAINT prep_log_recipe_id_fkey FOREIGN KEY (recipe_id) REFERENCES recipe(id) ON DELETE CASCADE,
+  DROP COLUMN recipe_name;
@@ -0,0 +1,11 @@
+-- Prep logs are food safety records and outlive the recipes they were made
+-- from: deleting a recipe clears their recipe_id, and recipe_name keeps the
+-- name the recipe had when the batch was logged.
+ALTER TABLE prep_log ADD COLUMN recipe_name TEXT NOT NULL DEFAULT '';
+
+UPDATE prep_log p SET recipe_name = COALESCE(r.name, '') FROM recipe r WHERE r.id = p.recipe_id;
+
+ALTER TABLE prep_log
+  ALTER COLUMN recipe_id DROP NOT NULL,
+  DROP CONSTRAINT prep_log_recipe_id_fkey,
+  ADD CONSTRAINT prep_log_recipe_id_fkey FOREIGN KEY (recipe_id) REFERENCES recipe(id) ON DELETE SET NULL;
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Kinds of analytics events.
+const (
+	// AnalyticsKindAPI is a call to an API route, named by its pattern.
+	AnalyticsKindAPI = "api"
+	// AnalyticsKindPage is a page of the frontend being viewed.
+	AnalyticsKindPage = "page"
+	// AnalyticsKindSearch is a recipe search, named by its scrubbed terms.
+	AnalyticsKindSearch = "search"
+)
+
+// AnalyticsEvent is one anonymous usage event.
+type AnalyticsEvent struct {
+	OccurredAt time.Time
+	Kind       string
+	Name       string
+}
+
+// AnalyticsCount is how often an event happened in a period.
+type AnalyticsCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type PageViewInputDto struct {
+	Path string `json:"path"`
+}
@@ -0,0 +1,236 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CatalogVersion is the version of the export format. Imports refuse
+// documents of other versions.
+const CatalogVersion = 1
+
+// Import modes, for rows whose uuid already exists.
+const (
+	ImportModeSkip      = "skip"
+	ImportModeOverwrite = "overwrite"
+)
+
+// CatalogRecipe is a recipe as exported: its category and ingredients are
+// given by uuid, so the export can be loaded into another database.
+type CatalogRecipe struct {
+	Recipe          Recipe
+	CategoryUuid    *string
+	IngredientUuids []string
+}
+
+// Catalog holds the rows of an import. Ids are ignored, rows are matched
+// by uuid.
+type Catalog struct {
+	Categories  []Category
+	Ingredients []Ingedient
+	Recipes     []CatalogRecipe
+}
+
+// CatalogVisitor receives the rows of an export, every category first, then
+// every ingredient, then every recipe.
+type CatalogVisitor struct {
+	Category   func(Category) error
+	Ingredient func(Ingedient) error
+	Recipe     func(CatalogRecipe) error
+}
+
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped"`
+}
+
+type ImportSummary struct {
+	Categories  ImportCounts `json:"categories"`
+	Ingredients ImportCounts `json:"ingredients"`
+	Recipes     ImportCounts `json:"recipes"`
+}
+
+// CatalogDto is the export document, and the input of imports.
+type CatalogDto struct {
+	Version     int                    `json:"version"`
+	ExportedAt  time.Time              `json:"exported_at"`
+	Categories  []CatalogCategoryDto   `json:"categories"`
+	Ingredients []CatalogIngredientDto `json:"ingredients"`
+	Recipes     []CatalogRecipeDto     `json:"recipes"`
+}
+
+type CatalogCategoryDto struct {
+	Uuid string `json:"uuid"`
+	Name string `json:"name"`
+}
+
+type CatalogIngredientDto struct {
+	Uuid        string        `json:"uuid"`
+	Name        string        `json:"name"`
+	Amount      *string       `json:"amount,omitempty"`
+	Url         *string       `json:"image_url,omitempty"`
+	IsAvailable bool          `json:"is_available"`
+	Quantity    *float64      `json:"quantity,omitempty"`
+	Unit        *string       `json:"unit,omitempty"`
+	Nutrition   *NutritionDto `json:"nutrition,omitempty"`
+}
+
+type CatalogRecipeDto struct {
+	Uuid            string   `json:"uuid"`
+	Name            string   `json:"name"`
+	Category        *string  `json:"category,omitempty"`
+	Description     string   `json:"description"`
+	LongDescription *string  `json:"long_description,omitempty"`
+	Url             *string  `json:"image_url,omitempty"`
+	Ingredients     []string `json:"ingredients"`
+}
+
+func NewCatalogCategoryDto(category Category) CatalogCategoryDto {
+	return CatalogCategoryDto{Uuid: category.Uuid, Name: category.Name}
+}
+
+func NewCatalogIngredientDto(ingredient Ingedient) CatalogIngredientDto {
+	dto := CatalogIngredientDto{
+		Uuid:        ingredient.Uuid,
+		Name:        ingredient.Name,
+		Amount:      ingredient.Amount,
+		Url:         ingredient.Url,
+		IsAvailable: ingredient.IsAvailable,
+		Quantity:    ingredient.Quantity,
+		Unit:        ingredient.Unit,
+	}
+
+	if ingredient.Nutrition != nil {
+		nutrition := NewNutritionDto(*ingredient.Nutrition)
+		dto.Nutrition = &nutrition
+	}
+
+	return dto
+}
+
+func NewCatalogRecipeDto(recipe CatalogRecipe) CatalogRecipeDto {
+	ingredients := recipe.IngredientUuids
+	if ingredients == nil {
+		ingredients = []string{}
+	}
+
+	return CatalogRecipeDto{
+		Uuid:            recipe.Recipe.Uuid,
+		Name:            recipe.Recipe.Name,
+		Category:        recipe.CategoryUuid,
+		Description:     recipe.Recipe.Description,
+		LongDescription: recipe.Recipe.LongDescription,
+		Url:             recipe.Recipe.Url,
+		Ingredients:     ingredients,
+	}
+}
+
+// Validate checks the document before anything is imported: the version,
+// a valid and unique uuid and a name for every row, known units and well
+// formed references. Whether references exist is checked by the import.
+func (d CatalogDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if d.Version != CatalogVersion {
+		errs["version"] = fmt.Sprintf("must be %d", CatalogVersion)
+	}
+
+	check := func(field string, id string, name string, seen map[string]bool) {
+		if _, err := uuid.Parse(id); err != nil {
+			errs[field+".uuid"] = "must be a uuid"
+		} else if seen[strings.ToLower(id)] {
+			errs[field+".uuid"] = "must be unique"
+		}
+		seen[strings.ToLower(id)] = true
+
+		if strings.TrimSpace(name) == "" {
+			errs[field+".name"] = "is required"
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, category := range d.Categories {
+		check(fmt.Sprintf("categories[%d]", i), category.Uuid, category.Name, seen)
+	}
+
+	seen = map[string]bool{}
+	for i, ingredient := range d.Ingredients {
+		field := fmt.Sprintf("ingredients[%d]", i)
+		check(field, ingredient.Uuid, ingredient.Name, seen)
+
+		unit := ""
+		if ingredient.Unit != nil {
+			unit = *ingredient.Unit
+		}
+		if msg := checkUnit(ingredient.Quantity, unit); msg != "" {
+			errs[field+".unit"] = msg
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, recipe := range d.Recipes {
+		field := fmt.Sprintf("recipes[%d]", i)
+		check(field, recipe.Uuid, recipe.Name, seen)
+
+		if recipe.Category != nil {
+			if _, err := uuid.Parse(*recipe.Category); err != nil {
+				errs[field+".category"] = "must be a uuid"
+			}
+		}
+
+		for _, id := range recipe.Ingredients {
+			if _, err := uuid.Parse(id); err != nil {
+				errs[field+".ingredients"] = "must only contain uuids"
+				break
+			}
+		}
+	}
+
+	return errs.err()
+}
+
+// Catalog returns the rows of the document.
+func (d CatalogDto) Catalog() Catalog {
+	catalog := Catalog{
+		Categories:  make([]Category, len(d.Categories)),
+		Ingredients: make([]Ingedient, len(d.Ingredients)),
+		Recipes:     make([]CatalogRecipe, len(d.Recipes)),
+	}
+
+	for i, category := range d.Categories {
+		catalog.Categories[i] = Category{Uuid: category.Uuid, Name: category.Name}
+	}
+
+	for i, ingredient := range d.Ingredients {
+		catalog.Ingredients[i] = Ingedient{
+			Uuid:        ingredient.Uuid,
+			Name:        ingredient.Name,
+			Amount:      ingredient.Amount,
+			Url:         ingredient.Url,
+			IsAvailable: ingredient.IsAvailable,
+			Quantity:    ingredient.Quantity,
+			Unit:        ingredient.Unit,
+			Nutrition:   ingredient.Nutrition.Nutrition(),
+		}
+	}
+
+	for i, recipe := range d.Recipes {
+		catalog.Recipes[i] = CatalogRecipe{
+			Recipe: Recipe{
+				Uuid:            recipe.Uuid,
+				Name:            recipe.Name,
+				Description:     recipe.Description,
+				LongDescription: recipe.LongDescription,
+				Url:             recipe.Url,
+			},
+			CategoryUuid:    recipe.Category,
+			IngredientUuids: recipe.Ingredients,
+		}
+	}
+
+	return catalog
+}
@@ -0,0 +1,45 @@
+package models
+
+import "strings"
+
+type Category struct {
+	Id   int
+	Uuid string
+	Name string
+}
+
+type CategoryInputDto struct {
+	Name string `json:"name"`
+}
+
+func (d CategoryInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(d.Name) == "" {
+		errs["name"] = "is required"
+	}
+
+	return errs.err()
+}
+
+type CategoryDto struct {
+	Id   int    `json:"id"`
+	Uuid string `json:"uuid,omitempty"`
+	Name string `json:"name"`
+}
+
+func NewCategoryDto(category Category) CategoryDto {
+	return CategoryDto{
+		Id:   category.Id,
+		Uuid: category.Uuid,
+		Name: category.Name,
+	}
+}
+
+func NewCategoryDtos(categories []Category) []CategoryDto {
+	dtos := make([]CategoryDto, len(categories))
+	for i, category := range categories {
+		dtos[i] = NewCategoryDto(category)
+	}
+	return dtos
+}
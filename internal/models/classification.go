@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// Tags the classifier proposes.
+const (
+	TagSpicy      = "spicy"
+	TagVegetarian = "vegetarian"
+	TagDessert    = "dessert"
+)
+
+// ClassifiableRecipe is what the classifier reads of a recipe: its texts,
+// the name of its category and the names of its ingredients.
+type ClassifiableRecipe struct {
+	Id              int
+	Name            string
+	Description     string
+	LongDescription string
+	Category        string
+	Ingredients     []string
+}
+
+// TagProposal is a tag the classifier finds fits a recipe, and why.
+type TagProposal struct {
+	Tag    string
+	Reason string
+}
+
+// TagSuggestion is a proposed tag in the review queue. It uses the same
+// moderation states as visitor suggestions.
+type TagSuggestion struct {
+	Id         int
+	RecipeId   int
+	RecipeName string
+	Tag        string
+	Reason     string
+	Status     string
+	CreatedAt  time.Time
+	ReviewedAt *time.Time
+}
+
+type TagSuggestionDto struct {
+	Id         int        `json:"id"`
+	RecipeId   int        `json:"recipe_id"`
+	RecipeName string     `json:"recipe_name"`
+	Tag        string     `json:"tag"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+func NewTagSuggestionDtos(suggestions []TagSuggestion) []TagSuggestionDto {
+	dtos := make([]TagSuggestionDto, len(suggestions))
+	for i, suggestion := range suggestions {
+		dtos[i] = TagSuggestionDto{
+			Id:         suggestion.Id,
+			RecipeId:   suggestion.RecipeId,
+			RecipeName: suggestion.RecipeName,
+			Tag:        suggestion.Tag,
+			Reason:     suggestion.Reason,
+			Status:     suggestion.Status,
+			CreatedAt:  suggestion.CreatedAt,
+			ReviewedAt: suggestion.ReviewedAt,
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,9 @@
+package models
+
+// RuntimeConfigDto is the configuration a running instance loaded: the
+// effective value of every setting, by environment variable, with secrets
+// redacted, and whether each optional feature is on.
+type RuntimeConfigDto struct {
+	Settings map[string]string `json:"settings"`
+	Features map[string]bool   `json:"features"`
+}
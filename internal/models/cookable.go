@@ -0,0 +1,38 @@
+package models
+
+// CookableRecipe is a recipe along with the ingredients it needs that are
+// not on hand.
+type CookableRecipe struct {
+	Recipe
+	MissingIngredientIds []int
+}
+
+// CookableInputDto lists the ingredients on hand, instead of the ones
+// marked as available.
+type CookableInputDto struct {
+	IngredientIds IDs `json:"ingredient_ids"`
+	// Missing is how many ingredients a recipe may lack, 0 by default.
+	Missing int `json:"missing"`
+}
+
+type CookableRecipeDto struct {
+	RecipeDto
+	MissingCount         int   `json:"missing_count"`
+	MissingIngredientIds []int `json:"missing_ingredient_ids"`
+}
+
+func NewCookableRecipeDtos(recipes []CookableRecipe) []CookableRecipeDto {
+	dtos := make([]CookableRecipeDto, len(recipes))
+	for i, recipe := range recipes {
+		missing := recipe.MissingIngredientIds
+		if missing == nil {
+			missing = []int{}
+		}
+		dtos[i] = CookableRecipeDto{
+			RecipeDto:            NewRecipeDto(recipe.Recipe),
+			MissingCount:         len(recipe.MissingIngredientIds),
+			MissingIngredientIds: missing,
+		}
+	}
+	return dtos
+}
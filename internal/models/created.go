@@ -0,0 +1,6 @@
+package models
+
+// CreatedDto answers a create request with the id of the new row.
+type CreatedDto struct {
+	Id int `json:"id"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"time"
+)
+
+// customFieldName matches the names custom fields may have, as the
+// custom_field table checks them.
+var customFieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// ValidCustomFieldName reports whether name can name a custom field:
+// lower case letters, digits and underscores, starting with a letter.
+func ValidCustomFieldName(name string) bool {
+	return customFieldName.MatchString(name)
+}
+
+// CustomField is a field the kitchen added to recipes. Schema is the JSON
+// Schema its values must follow.
+type CustomField struct {
+	Name      string
+	Schema    json.RawMessage
+	UpdatedAt time.Time
+}
+
+type CustomFieldDto struct {
+	Name      string          `json:"name"`
+	Schema    json.RawMessage `json:"schema"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func NewCustomFieldDtos(fields []CustomField) []CustomFieldDto {
+	dtos := make([]CustomFieldDto, len(fields))
+	for i, field := range fields {
+		dtos[i] = CustomFieldDto{Name: field.Name, Schema: field.Schema, UpdatedAt: field.UpdatedAt}
+	}
+	return dtos
+}
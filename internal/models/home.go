@@ -0,0 +1,53 @@
+package models
+
+// Landing page slots an admin can fill with recipes.
+const (
+	HomeSlotHero          = "hero"
+	HomeSlotSeasonal      = "seasonal"
+	HomeSlotEditorsChoice = "editors_choice"
+)
+
+// HomeSlotSizes maps every slot to the most recipes it holds, 0 for no
+// limit.
+var HomeSlotSizes = map[string]int{
+	HomeSlotHero:          1,
+	HomeSlotSeasonal:      0,
+	HomeSlotEditorsChoice: 0,
+}
+
+// Home is the content of the landing page. Slots maps a slot name to its
+// recipes in display order.
+type Home struct {
+	Slots          map[string][]Recipe
+	RecipeOfTheDay *Recipe
+}
+
+type HomeSlotInputDto struct {
+	RecipeIds IDs `json:"recipe_ids"`
+}
+
+type HomeDto struct {
+	Hero           *RecipeDto  `json:"hero"`
+	Seasonal       []RecipeDto `json:"seasonal"`
+	EditorsChoice  []RecipeDto `json:"editors_choice"`
+	RecipeOfTheDay *RecipeDto  `json:"recipe_of_the_day"`
+}
+
+func NewHomeDto(home Home) HomeDto {
+	dto := HomeDto{
+		Seasonal:      NewRecipeDtos(home.Slots[HomeSlotSeasonal]),
+		EditorsChoice: NewRecipeDtos(home.Slots[HomeSlotEditorsChoice]),
+	}
+
+	if hero := home.Slots[HomeSlotHero]; len(hero) > 0 {
+		recipe := NewRecipeDto(hero[0])
+		dto.Hero = &recipe
+	}
+
+	if home.RecipeOfTheDay != nil {
+		recipe := NewRecipeDto(*home.RecipeOfTheDay)
+		dto.RecipeOfTheDay = &recipe
+	}
+
+	return dto
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrInvalidID is returned for ids that are not positive integers.
+var ErrInvalidID = errors.New("invalid id")
+
+// ParseID parses an id sent as text, such as a path segment.
+func ParseID(s string) (int, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 || int64(int(id)) != id {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidID, s)
+	}
+	return int(id), nil
+}
+
+// ID is an id in a request body. It accepts both a JSON number and a string,
+// so clients that keep ids as strings to avoid precision loss can send them
+// back unchanged.
+type ID int
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := strconv.Atoi(text)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidID, data)
+	}
+	*id = ID(parsed)
+	return nil
+}
+
+// IDs is a list of ids in a request body, accepting numbers and strings.
+type IDs []int
+
+func (ids *IDs) UnmarshalJSON(data []byte) error {
+	var raw []ID
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw == nil {
+		*ids = nil
+		return nil
+	}
+
+	*ids = make(IDs, len(raw))
+	for i, id := range raw {
+		(*ids)[i] = int(id)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Statuses of an image import.
+const (
+	ImageImportQueued  = "queued"
+	ImageImportRunning = "running"
+	ImageImportDone    = "done"
+	ImageImportFailed  = "failed"
+)
+
+// ImageImportResult is what happened to one file of an image import: the
+// recipe it was attached to and its URL, or why it wasn't.
+type ImageImportResult struct {
+	File     string `json:"file"`
+	RecipeId int    `json:"recipe_id,omitempty"`
+	Url      string `json:"url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ImageImport struct {
+	Id         string
+	Status     string
+	Files      int
+	Results    []ImageImportResult
+	CreatedAt  time.Time
+	FinishedAt *time.Time
+}
+
+type ImageImportDto struct {
+	Id         string              `json:"id"`
+	Status     string              `json:"status"`
+	Files      int                 `json:"files"`
+	Attached   int                 `json:"attached"`
+	Failed     int                 `json:"failed"`
+	Results    []ImageImportResult `json:"results"`
+	CreatedAt  time.Time           `json:"created_at"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
+
+func NewImageImportDto(imageImport ImageImport) ImageImportDto {
+	dto := ImageImportDto{
+		Id:         imageImport.Id,
+		Status:     imageImport.Status,
+		Files:      imageImport.Files,
+		Results:    imageImport.Results,
+		CreatedAt:  imageImport.CreatedAt,
+		FinishedAt: imageImport.FinishedAt,
+	}
+
+	if dto.Results == nil {
+		dto.Results = []ImageImportResult{}
+	}
+
+	for _, result := range dto.Results {
+		if result.Error != "" {
+			dto.Failed++
+		} else {
+			dto.Attached++
+		}
+	}
+
+	return dto
+}
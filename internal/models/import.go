@@ -0,0 +1,48 @@
+package models
+
+// ImportRecipeInputDto asks for the recipe published at Url to be added to
+// the catalog.
+type ImportRecipeInputDto struct {
+	Url string `json:"url"`
+	// CategoryId overrides the category named by the page.
+	CategoryId ID `json:"category_id"`
+}
+
+func (d ImportRecipeInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if d.Url == "" {
+		errs["url"] = "is required"
+	} else if !isWebURL(d.Url) {
+		errs["url"] = "must be an absolute http or https URL"
+	}
+
+	if d.CategoryId < 0 {
+		errs["category_id"] = "must not be negative"
+	}
+
+	return errs.err()
+}
+
+// ImportedRecipe is a recipe read from another site, with its category and
+// ingredients given by name.
+type ImportedRecipe struct {
+	Name            string
+	Description     string
+	LongDescription string
+	Url             string
+	// CategoryId is the category of the recipe. When 0, the first of
+	// Categories the catalog knows is used, or the first of them is created.
+	CategoryId  int
+	Categories  []string
+	Ingredients []ImportedIngredient
+}
+
+// ImportedIngredient is an ingredient line of the page. Quantity and Unit
+// are read from Amount when it can be, and are nil and "" otherwise.
+type ImportedIngredient struct {
+	Name     string
+	Amount   string
+	Quantity *float64
+	Unit     string
+}
@@ -1,9 +1,141 @@
 package models
 
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/quantity"
+	"net/url"
+	"strings"
+)
+
+// Ingedient mirrors an ingredient row. Nullable columns are pointers.
 type Ingedient struct {
 	Id          int
+	Uuid        string
 	Name        string
-	Amount      string
-	Url         string
+	Amount      *string
+	Url         *string
 	IsAvailable bool
+	// Quantity and Unit are the amount in structured form, e.g. 200 and
+	// "g". Nutrition is given per one Unit.
+	Quantity  *float64
+	Unit      *string
+	Nutrition *Nutrition
+	// BaseId is the base ingredient of a variant, nil for bases.
+	BaseId *int
+}
+
+type IngredientInputDto struct {
+	Name        string        `json:"name"`
+	Amount      string        `json:"amount"`
+	Url         string        `json:"image_url"`
+	IsAvailable bool          `json:"is_available"`
+	Quantity    *float64      `json:"quantity"`
+	Unit        string        `json:"unit"`
+	Nutrition   *NutritionDto `json:"nutrition"`
+}
+
+// UnmarshalJSON also accepts the Go field names the API used before it
+// moved to snake_case. When both spellings are sent the snake_case one wins.
+func (d *IngredientInputDto) UnmarshalJSON(data []byte) error {
+	type ingredientInput IngredientInputDto
+
+	var legacy struct {
+		Url         string `json:"Url"`
+		IsAvailable bool   `json:"IsAvailable"`
+	}
+
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	d.Url = legacy.Url
+	d.IsAvailable = legacy.IsAvailable
+
+	return json.Unmarshal(data, (*ingredientInput)(d))
+}
+
+// ParseAmount fills the quantity and unit from the free text amount when
+// neither was sent and the amount can be read, e.g. "2 xícaras" as 2 cup.
+func (d *IngredientInputDto) ParseAmount() {
+	d.Unit = strings.ToLower(strings.TrimSpace(d.Unit))
+
+	if d.Quantity != nil || d.Unit != "" {
+		return
+	}
+
+	if amount, ok := quantity.Parse(d.Amount); ok {
+		d.Quantity = &amount.Quantity
+		d.Unit = string(amount.Unit)
+	}
+}
+
+// IngredientBaseInputDto makes an ingredient a variant of base_id, or a
+// base ingredient again when base_id is null.
+type IngredientBaseInputDto struct {
+	BaseId *ID `json:"base_id"`
+}
+
+// IngredientAvailabilityInputDto sets the availability of one or more
+// ingredients. Without is_available each ingredient's availability is
+// flipped.
+type IngredientAvailabilityInputDto struct {
+	IngredientIds IDs   `json:"ingredient_ids"`
+	IsAvailable   *bool `json:"is_available"`
+}
+
+// IngredientDto is the API representation of an ingredient. Optional fields
+// are left out when they have no value.
+type IngredientDto struct {
+	Id          int           `json:"id"`
+	Uuid        string        `json:"uuid,omitempty"`
+	Name        string        `json:"name"`
+	Amount      *string       `json:"amount,omitempty"`
+	Url         *string       `json:"image_url,omitempty"`
+	IsAvailable bool          `json:"is_available"`
+	Quantity    *float64      `json:"quantity,omitempty"`
+	Unit        *string       `json:"unit,omitempty"`
+	Nutrition   *NutritionDto `json:"nutrition,omitempty"`
+	BaseId      *int          `json:"base_id,omitempty"`
+}
+
+// NewIngredientDto converts ingredient to its API representation. An
+// ingredient without a photo gets the URL of its generated placeholder.
+func NewIngredientDto(ingredient Ingedient) IngredientDto {
+	if ingredient.Url == nil || *ingredient.Url == "" {
+		placeholder := PlaceholderImagePath(ingredient.Name)
+		ingredient.Url = &placeholder
+	}
+
+	dto := IngredientDto{
+		Id:          ingredient.Id,
+		Uuid:        ingredient.Uuid,
+		Name:        ingredient.Name,
+		Amount:      ingredient.Amount,
+		Url:         ingredient.Url,
+		IsAvailable: ingredient.IsAvailable,
+		Quantity:    ingredient.Quantity,
+		Unit:        ingredient.Unit,
+		BaseId:      ingredient.BaseId,
+	}
+
+	if ingredient.Nutrition != nil {
+		nutrition := NewNutritionDto(*ingredient.Nutrition)
+		dto.Nutrition = &nutrition
+	}
+
+	return dto
+}
+
+func NewIngredientDtos(ingredients []Ingedient) []IngredientDto {
+	dtos := make([]IngredientDto, len(ingredients))
+	for i, ingredient := range ingredients {
+		dtos[i] = NewIngredientDto(ingredient)
+	}
+	return dtos
+}
+
+// PlaceholderImagePath is the path of the generated placeholder image for an
+// ingredient called name.
+func PlaceholderImagePath(name string) string {
+	return "/images/placeholder/" + url.PathEscape(name) + ".png"
 }
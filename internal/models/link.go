@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Kinds of rows that store an image URL.
+const (
+	LinkKindRecipe     = "recipe"
+	LinkKindIngredient = "ingredient"
+)
+
+// ImageLink is an external image URL stored on a recipe or ingredient.
+type ImageLink struct {
+	Kind string
+	Id   int
+	Url  string
+}
+
+// BrokenLink is an image URL the link checker could not load.
+type BrokenLink struct {
+	ImageLink
+	Name        string
+	Error       string
+	BrokenSince time.Time
+	CheckedAt   time.Time
+}
+
+type BrokenLinkDto struct {
+	Kind        string    `json:"kind"`
+	Id          int       `json:"id"`
+	Name        string    `json:"name"`
+	Url         string    `json:"image_url"`
+	Error       string    `json:"error"`
+	BrokenSince time.Time `json:"broken_since"`
+	CheckedAt   time.Time `json:"checked_at"`
+}
+
+func NewBrokenLinkDtos(links []BrokenLink) []BrokenLinkDto {
+	dtos := make([]BrokenLinkDto, len(links))
+	for i, link := range links {
+		dtos[i] = BrokenLinkDto{
+			Kind:        link.Kind,
+			Id:          link.Id,
+			Name:        link.Name,
+			Url:         link.Url,
+			Error:       link.Error,
+			BrokenSince: link.BrokenSince,
+			CheckedAt:   link.CheckedAt,
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Meal slots of a day, in order.
+const (
+	MealSlotBreakfast = "breakfast"
+	MealSlotLunch     = "lunch"
+	MealSlotDinner    = "dinner"
+)
+
+var MealSlots = []string{MealSlotBreakfast, MealSlotLunch, MealSlotDinner}
+
+// Meal is the recipe planned for a slot of a day.
+type Meal struct {
+	Date   time.Time
+	Slot   string
+	Recipe Recipe
+}
+
+// ISOWeek is a week as numbered by ISO 8601, starting on a Monday.
+type ISOWeek struct {
+	Year int
+	Week int
+}
+
+// WeekOf returns the ISO week t falls in.
+func WeekOf(t time.Time) ISOWeek {
+	year, week := t.ISOWeek()
+	return ISOWeek{Year: year, Week: week}
+}
+
+// ParseISOWeek reads a week written as 2024-W30.
+func ParseISOWeek(s string) (ISOWeek, error) {
+	var w ISOWeek
+
+	if _, err := fmt.Sscanf(s, "%4d-W%2d", &w.Year, &w.Week); err != nil || len(s) != len("2024-W30") {
+		return w, fmt.Errorf("week must be formatted as YYYY-Www, e.g. 2024-W30")
+	}
+
+	if w.Week < 1 || WeekOf(w.Monday()) != w {
+		return w, fmt.Errorf("%d has no week %d", w.Year, w.Week)
+	}
+
+	return w, nil
+}
+
+// Monday returns the first day of the week, at midnight UTC.
+func (w ISOWeek) Monday() time.Time {
+	// January 4th is always in the first week.
+	jan4 := time.Date(w.Year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	return jan4.AddDate(0, 0, 7*(w.Week-1)-offset)
+}
+
+func (w ISOWeek) String() string {
+	return fmt.Sprintf("%04d-W%02d", w.Year, w.Week)
+}
+
+type MealInputDto struct {
+	RecipeId ID `json:"recipe_id"`
+}
+
+type MealPlanWeekDto struct {
+	Week string           `json:"week"`
+	Days []MealPlanDayDto `json:"days"`
+}
+
+// MealPlanDayDto holds the recipes planned for a day, null for empty slots.
+type MealPlanDayDto struct {
+	Date      string     `json:"date"`
+	Breakfast *RecipeDto `json:"breakfast"`
+	Lunch     *RecipeDto `json:"lunch"`
+	Dinner    *RecipeDto `json:"dinner"`
+}
+
+// NewMealPlanWeekDto lays the meals out over the seven days of week.
+func NewMealPlanWeekDto(week ISOWeek, meals []Meal) MealPlanWeekDto {
+	monday := week.Monday()
+	days := make([]MealPlanDayDto, 7)
+
+	for i := range days {
+		days[i].Date = monday.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+
+	for _, meal := range meals {
+		i := int(meal.Date.Sub(monday).Hours() / 24)
+		if i < 0 || i >= len(days) {
+			continue
+		}
+
+		recipe := NewRecipeDto(meal.Recipe)
+
+		switch meal.Slot {
+		case MealSlotBreakfast:
+			days[i].Breakfast = &recipe
+		case MealSlotLunch:
+			days[i].Lunch = &recipe
+		case MealSlotDinner:
+			days[i].Dinner = &recipe
+		}
+	}
+
+	return MealPlanWeekDto{Week: week.String(), Days: days}
+}
@@ -0,0 +1,81 @@
+package models
+
+// Nutrition holds the nutrient values of an amount of food: calories in
+// kcal, protein, fat and carbs in grams.
+type Nutrition struct {
+	Calories float64
+	Protein  float64
+	Fat      float64
+	Carbs    float64
+}
+
+// RecipeNutrition is the nutrition of a recipe, added up over its
+// ingredients.
+type RecipeNutrition struct {
+	Total Nutrition
+	// Missing are the ingredients left out of Total because they have no
+	// quantity or no nutrition facts.
+	Missing []Ingedient
+}
+
+// NutritionOf adds up the nutrition of ingredients: each one's quantity
+// times its nutrient values per unit.
+func NutritionOf(ingredients []Ingedient) RecipeNutrition {
+	nutrition := RecipeNutrition{Missing: []Ingedient{}}
+
+	for _, ingredient := range ingredients {
+		if ingredient.Quantity == nil || ingredient.Nutrition == nil {
+			nutrition.Missing = append(nutrition.Missing, ingredient)
+			continue
+		}
+
+		quantity := *ingredient.Quantity
+		nutrition.Total.Calories += quantity * ingredient.Nutrition.Calories
+		nutrition.Total.Protein += quantity * ingredient.Nutrition.Protein
+		nutrition.Total.Fat += quantity * ingredient.Nutrition.Fat
+		nutrition.Total.Carbs += quantity * ingredient.Nutrition.Carbs
+	}
+
+	return nutrition
+}
+
+type NutritionDto struct {
+	Calories float64 `json:"calories"`
+	Protein  float64 `json:"protein"`
+	Fat      float64 `json:"fat"`
+	Carbs    float64 `json:"carbs"`
+}
+
+// RecipeNutritionDto is the nutrition of a recipe. Complete is false when
+// some ingredients, listed in missing, could not be counted.
+type RecipeNutritionDto struct {
+	Total    NutritionDto    `json:"total"`
+	Complete bool            `json:"complete"`
+	Missing  []IngredientDto `json:"missing"`
+}
+
+func NewNutritionDto(nutrition Nutrition) NutritionDto {
+	return NutritionDto{
+		Calories: nutrition.Calories,
+		Protein:  nutrition.Protein,
+		Fat:      nutrition.Fat,
+		Carbs:    nutrition.Carbs,
+	}
+}
+
+func NewRecipeNutritionDto(nutrition RecipeNutrition) RecipeNutritionDto {
+	return RecipeNutritionDto{
+		Total:    NewNutritionDto(nutrition.Total),
+		Complete: len(nutrition.Missing) == 0,
+		Missing:  NewIngredientDtos(nutrition.Missing),
+	}
+}
+
+// Nutrition returns the nutrition of the input, or nil when none was sent.
+func (d *NutritionDto) Nutrition() *Nutrition {
+	if d == nil {
+		return nil
+	}
+
+	return &Nutrition{Calories: d.Calories, Protein: d.Protein, Fat: d.Fat, Carbs: d.Carbs}
+}
@@ -0,0 +1,24 @@
+package models
+
+// PageDto is the envelope of a paginated list response.
+type PageDto[T any] struct {
+	Items      []T `json:"items"`
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+func NewPageDto[T any](items []T, page int, pageSize int, total int) PageDto[T] {
+	if items == nil {
+		items = []T{}
+	}
+
+	return PageDto[T]{
+		Items:      items,
+		Page:       page,
+		PageSize:   pageSize,
+		Total:      total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+}
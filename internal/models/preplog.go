@@ -0,0 +1,103 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// PrepLog records a batch prepared from a recipe, for food safety audits.
+// Logs outlive their recipe: RecipeId is nil once it is deleted, and
+// RecipeName is the name it had when the batch was logged.
+type PrepLog struct {
+	Id           int
+	RecipeId     *int
+	RecipeName   string
+	PreparedBy   string
+	PreparedAt   time.Time
+	BatchSize    float64
+	BatchUnit    string
+	Temperatures []TemperatureReading
+	Notes        string
+}
+
+// TemperatureReading is a temperature taken at a stage of the preparation,
+// such as "cooking" or "cooling".
+type TemperatureReading struct {
+	Stage   string  `json:"stage"`
+	Celsius float64 `json:"celsius"`
+}
+
+type PrepLogInputDto struct {
+	PreparedBy string `json:"prepared_by"`
+	// PreparedAt defaults to the time the log is received.
+	PreparedAt   *time.Time           `json:"prepared_at"`
+	BatchSize    float64              `json:"batch_size"`
+	BatchUnit    string               `json:"batch_unit"`
+	Temperatures []TemperatureReading `json:"temperatures"`
+	Notes        string               `json:"notes"`
+}
+
+// Validate checks the log before it is stored. Temperatures outside what a
+// kitchen can reach are taken for typos.
+func (d PrepLogInputDto) Validate(now time.Time) error {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(d.PreparedBy) == "" {
+		errs["prepared_by"] = "is required"
+	}
+
+	if d.PreparedAt != nil && d.PreparedAt.After(now.Add(5*time.Minute)) {
+		errs["prepared_at"] = "must not be in the future"
+	}
+
+	if d.BatchSize <= 0 {
+		errs["batch_size"] = "must be positive"
+	}
+
+	for _, reading := range d.Temperatures {
+		if strings.TrimSpace(reading.Stage) == "" {
+			errs["temperatures"] = "every reading needs a stage"
+			break
+		}
+		if reading.Celsius < -50 || reading.Celsius > 300 {
+			errs["temperatures"] = "must be between -50 and 300 °C"
+			break
+		}
+	}
+
+	return errs.err()
+}
+
+type PrepLogDto struct {
+	Id           int                  `json:"id"`
+	RecipeId     *int                 `json:"recipe_id"`
+	RecipeName   string               `json:"recipe_name"`
+	PreparedBy   string               `json:"prepared_by"`
+	PreparedAt   time.Time            `json:"prepared_at"`
+	BatchSize    float64              `json:"batch_size"`
+	BatchUnit    string               `json:"batch_unit,omitempty"`
+	Temperatures []TemperatureReading `json:"temperatures"`
+	Notes        string               `json:"notes,omitempty"`
+}
+
+func NewPrepLogDtos(logs []PrepLog) []PrepLogDto {
+	dtos := make([]PrepLogDto, len(logs))
+	for i, log := range logs {
+		temperatures := log.Temperatures
+		if temperatures == nil {
+			temperatures = []TemperatureReading{}
+		}
+		dtos[i] = PrepLogDto{
+			Id:           log.Id,
+			RecipeId:     log.RecipeId,
+			RecipeName:   log.RecipeName,
+			PreparedBy:   log.PreparedBy,
+			PreparedAt:   log.PreparedAt,
+			BatchSize:    log.BatchSize,
+			BatchUnit:    log.BatchUnit,
+			Temperatures: temperatures,
+			Notes:        log.Notes,
+		}
+	}
+	return dtos
+}
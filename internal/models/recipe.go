@@ -1,24 +1,193 @@
 package models
 
+import (
+	"encoding/json"
+	"html"
+	"strings"
+)
+
+// Recipe mirrors a recipe row. Nullable columns are pointers.
 type Recipe struct {
 	Id              int
-	CategoryId      int
+	Uuid            string
+	CategoryId      *int
 	Name            string
-	Url             string
+	Url             *string
 	Description     string
-	LongDescription string
+	LongDescription *string
+	// CustomFields holds the values of the kitchen's custom fields, by
+	// field name. Only single recipe reads fill it.
+	CustomFields map[string]json.RawMessage
+}
+
+// RecipeWithIngredients is a recipe with its ingredients, the nutrition
+// they add up to, which GET /recipe/{id}/nutrition serves, its steps in
+// order and its tags.
+type RecipeWithIngredients struct {
+	Recipe      Recipe
+	Ingredients []Ingedient
+	Nutrition   RecipeNutrition
+	Steps       []RecipeStep
+	Tags        []string
 }
 
+// RecipeSearchResult is a recipe matching a search. NameHighlight and
+// DescriptionSnippet mark the matched terms with HighlightStart and
+// HighlightStop.
+type RecipeSearchResult struct {
+	Recipe             Recipe
+	Rank               float32
+	NameHighlight      string
+	DescriptionSnippet string
+}
+
+// Markers placed around matched terms by the database. They are private use
+// characters, so they never clash with recipe text and can be swapped for
+// HTML after the text is escaped.
+const (
+	HighlightStart = "\uE000"
+	HighlightStop  = "\uE001"
+)
+
 type RecipeInputDto struct {
-	CategoryId      int    `json:categoryId`
-	Name            string `json:name`
-	Url             string `json:url`
-	Description     string `json:description`
-	LongDescription string `json:longDescription`
-	IngedientIds    []int  `json:ingedientIds`
+	CategoryId      ID     `json:"category_id"`
+	Name            string `json:"name"`
+	Url             string `json:"image_url"`
+	Description     string `json:"description"`
+	LongDescription string `json:"long_description"`
+	IngedientIds    IDs    `json:"ingredient_ids"`
+}
+
+// UnmarshalJSON also accepts the camelCase keys used before the API moved
+// to snake_case. When both spellings are sent the snake_case one wins.
+func (d *RecipeInputDto) UnmarshalJSON(data []byte) error {
+	type recipeInput RecipeInputDto
+
+	var legacy struct {
+		CategoryId      ID     `json:"categoryId"`
+		Url             string `json:"url"`
+		LongDescription string `json:"longDescription"`
+		IngedientIds    IDs    `json:"ingedientIds"`
+	}
+
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	d.CategoryId = legacy.CategoryId
+	d.Url = legacy.Url
+	d.LongDescription = legacy.LongDescription
+	d.IngedientIds = legacy.IngedientIds
+
+	return json.Unmarshal(data, (*recipeInput)(d))
+}
+
+type RecipeIngredientsInputDto struct {
+	IngredientIds IDs `json:"ingredient_ids"`
+}
+
+type PinDailyRecipeInputDto struct {
+	RecipeId ID `json:"recipe_id"`
+	// Date is the day to pin, as YYYY-MM-DD. It defaults to today.
+	Date string `json:"date"`
+}
+
+// RecipeDto is the API representation of a recipe. Optional fields are left
+// out when they have no value.
+type RecipeDto struct {
+	Id              int                        `json:"id"`
+	Uuid            string                     `json:"uuid,omitempty"`
+	CategoryId      *int                       `json:"category_id,omitempty"`
+	Name            string                     `json:"name"`
+	Url             *string                    `json:"image_url,omitempty"`
+	Description     string                     `json:"description"`
+	LongDescription *string                    `json:"long_description,omitempty"`
+	CustomFields    map[string]json.RawMessage `json:"custom_fields,omitempty"`
+}
+
+func NewRecipeDto(recipe Recipe) RecipeDto {
+	return RecipeDto{
+		Id:              recipe.Id,
+		Uuid:            recipe.Uuid,
+		CategoryId:      recipe.CategoryId,
+		Name:            recipe.Name,
+		Url:             recipe.Url,
+		Description:     recipe.Description,
+		LongDescription: recipe.LongDescription,
+		CustomFields:    recipe.CustomFields,
+	}
+}
+
+func NewRecipeDtos(recipes []Recipe) []RecipeDto {
+	dtos := make([]RecipeDto, len(recipes))
+	for i, recipe := range recipes {
+		dtos[i] = NewRecipeDto(recipe)
+	}
+	return dtos
 }
 
 type RecipeWithIngredientsDto struct {
-	Recipe      Recipe
-	Ingredients []Ingedient
+	Recipe      RecipeDto       `json:"recipe"`
+	Ingredients []IngredientDto `json:"ingredients"`
+	Steps       []RecipeStepDto `json:"steps"`
+	Tags        []string        `json:"tags"`
+}
+
+func NewRecipeWithIngredientsDto(recipe RecipeWithIngredients) RecipeWithIngredientsDto {
+	return RecipeWithIngredientsDto{
+		Recipe:      NewRecipeDto(recipe.Recipe),
+		Ingredients: NewIngredientDtos(recipe.Ingredients),
+		Steps:       NewRecipeStepDtos(recipe.Steps),
+		Tags:        append([]string{}, recipe.Tags...),
+	}
+}
+
+// ScaledRecipeDto is a recipe with its ingredient quantities multiplied by
+// Factor, and the nutrition of the scaled recipe.
+type ScaledRecipeDto struct {
+	RecipeWithIngredientsDto
+	Factor    float64            `json:"factor"`
+	Nutrition RecipeNutritionDto `json:"nutrition"`
+}
+
+func NewScaledRecipeDto(recipe RecipeWithIngredients, factor float64) ScaledRecipeDto {
+	return ScaledRecipeDto{
+		RecipeWithIngredientsDto: NewRecipeWithIngredientsDto(recipe),
+		Factor:                   factor,
+		Nutrition:                NewRecipeNutritionDto(recipe.Nutrition),
+	}
+}
+
+type RecipeSearchResultDto struct {
+	RecipeDto
+	Rank      float32            `json:"rank"`
+	Highlight RecipeHighlightDto `json:"highlight"`
+}
+
+// RecipeHighlightDto holds HTML escaped text with the matched terms wrapped
+// in <mark> tags.
+type RecipeHighlightDto struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func NewRecipeSearchResultDtos(results []RecipeSearchResult) []RecipeSearchResultDto {
+	dtos := make([]RecipeSearchResultDto, len(results))
+	for i, result := range results {
+		dtos[i] = RecipeSearchResultDto{
+			RecipeDto: NewRecipeDto(result.Recipe),
+			Rank:      result.Rank,
+			Highlight: RecipeHighlightDto{
+				Name:        highlightHTML(result.NameHighlight),
+				Description: highlightHTML(result.DescriptionSnippet),
+			},
+		}
+	}
+	return dtos
+}
+
+// highlightHTML escapes text and turns the highlight markers into <mark>
+// tags, so the frontend can render it as HTML.
+func highlightHTML(text string) string {
+	return strings.NewReplacer(HighlightStart, "<mark>", HighlightStop, "</mark>").Replace(html.EscapeString(text))
 }
@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+type ShoppingList struct {
+	Id        int
+	Uuid      string
+	RecipeIds []int
+	CreatedAt time.Time
+	Items     []ShoppingListItem
+}
+
+// ShoppingListItem is an ingredient to buy. RecipeIds are the recipes of
+// the list that need it.
+type ShoppingListItem struct {
+	Id           int
+	IngredientId *int
+	Name         string
+	Amount       *string
+	Quantity     *float64
+	Unit         *string
+	RecipeIds    []int
+	Checked      bool
+}
+
+type ShoppingListInputDto struct {
+	RecipeIds IDs `json:"recipe_ids"`
+}
+
+func (d ShoppingListInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if len(d.RecipeIds) == 0 {
+		errs["recipe_ids"] = "must not be empty"
+	}
+
+	for _, id := range d.RecipeIds {
+		if id <= 0 {
+			errs["recipe_ids"] = "must only contain positive ids"
+			break
+		}
+	}
+
+	return errs.err()
+}
+
+// ShoppingListItemInputDto checks an item off, or back on. Without checked
+// the item is flipped.
+type ShoppingListItemInputDto struct {
+	Checked *bool `json:"checked"`
+}
+
+// ShoppingListDto leaves out the serial id, lists are only addressed by
+// their uuid.
+type ShoppingListDto struct {
+	Uuid      string                `json:"uuid"`
+	RecipeIds []int                 `json:"recipe_ids"`
+	CreatedAt time.Time             `json:"created_at"`
+	Items     []ShoppingListItemDto `json:"items"`
+}
+
+type ShoppingListItemDto struct {
+	Id           int      `json:"id"`
+	IngredientId *int     `json:"ingredient_id"`
+	Name         string   `json:"name"`
+	Amount       *string  `json:"amount,omitempty"`
+	Quantity     *float64 `json:"quantity,omitempty"`
+	Unit         *string  `json:"unit,omitempty"`
+	RecipeIds    []int    `json:"recipe_ids"`
+	Checked      bool     `json:"checked"`
+}
+
+func NewShoppingListDto(list ShoppingList) ShoppingListDto {
+	items := make([]ShoppingListItemDto, len(list.Items))
+	for i, item := range list.Items {
+		items[i] = NewShoppingListItemDto(item)
+	}
+
+	return ShoppingListDto{
+		Uuid:      list.Uuid,
+		RecipeIds: list.RecipeIds,
+		CreatedAt: list.CreatedAt,
+		Items:     items,
+	}
+}
+
+func NewShoppingListItemDto(item ShoppingListItem) ShoppingListItemDto {
+	return ShoppingListItemDto{
+		Id:           item.Id,
+		IngredientId: item.IngredientId,
+		Name:         item.Name,
+		Amount:       item.Amount,
+		Quantity:     item.Quantity,
+		Unit:         item.Unit,
+		RecipeIds:    item.RecipeIds,
+		Checked:      item.Checked,
+	}
+}
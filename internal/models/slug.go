@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Slug turns a recipe name into the form used in file names and URLs:
+// lower case, without accents, with words joined by dashes. "Pão de Queijo"
+// becomes "pao-de-queijo".
+func Slug(name string) string {
+	var b strings.Builder
+
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// An accent split off its letter.
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,24 @@
+package models
+
+type PublicStats struct {
+	TotalRecipes     int
+	TotalCategories  int
+	TotalIngredients int
+	NewestRecipes    []Recipe
+}
+
+type PublicStatsDto struct {
+	TotalRecipes     int         `json:"total_recipes"`
+	TotalCategories  int         `json:"total_categories"`
+	TotalIngredients int         `json:"total_ingredients"`
+	NewestRecipes    []RecipeDto `json:"newest_recipes"`
+}
+
+func NewPublicStatsDto(stats PublicStats) PublicStatsDto {
+	return PublicStatsDto{
+		TotalRecipes:     stats.TotalRecipes,
+		TotalCategories:  stats.TotalCategories,
+		TotalIngredients: stats.TotalIngredients,
+		NewestRecipes:    NewRecipeDtos(stats.NewestRecipes),
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import "strings"
+
+// RecipeStep is one step of the procedure of a recipe. Positions start at 1.
+type RecipeStep struct {
+	Id              int
+	RecipeId        int
+	Position        int
+	Text            string
+	ImageUrl        *string
+	DurationMinutes *int
+}
+
+type RecipeStepInputDto struct {
+	Text            string `json:"text"`
+	ImageUrl        string `json:"image_url"`
+	DurationMinutes *int   `json:"duration_minutes"`
+}
+
+// Validate checks the step before it is written: a text, a valid image URL
+// if any and a positive duration if any.
+func (d RecipeStepInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(d.Text) == "" {
+		errs["text"] = "is required"
+	}
+
+	if d.ImageUrl != "" && !isWebURL(d.ImageUrl) {
+		errs["image_url"] = "must be an absolute http or https URL"
+	}
+
+	if d.DurationMinutes != nil && *d.DurationMinutes <= 0 {
+		errs["duration_minutes"] = "must be positive"
+	}
+
+	return errs.err()
+}
+
+// RecipeStepOrderInputDto lists every step of a recipe in its new order.
+type RecipeStepOrderInputDto struct {
+	StepIds IDs `json:"step_ids"`
+}
+
+type RecipeStepDto struct {
+	Id              int     `json:"id"`
+	Position        int     `json:"position"`
+	Text            string  `json:"text"`
+	ImageUrl        *string `json:"image_url,omitempty"`
+	DurationMinutes *int    `json:"duration_minutes,omitempty"`
+}
+
+func NewRecipeStepDto(step RecipeStep) RecipeStepDto {
+	return RecipeStepDto{
+		Id:              step.Id,
+		Position:        step.Position,
+		Text:            step.Text,
+		ImageUrl:        step.ImageUrl,
+		DurationMinutes: step.DurationMinutes,
+	}
+}
+
+func NewRecipeStepDtos(steps []RecipeStep) []RecipeStepDto {
+	dtos := make([]RecipeStepDto, len(steps))
+	for i, step := range steps {
+		dtos[i] = NewRecipeStepDto(step)
+	}
+	return dtos
+}
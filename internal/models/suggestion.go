@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Kinds of suggestions visitors can send.
+const (
+	SuggestionKindRecipeIdea   = "recipe_idea"
+	SuggestionKindCatalogError = "catalog_error"
+)
+
+// Moderation states of a suggestion.
+const (
+	SuggestionPending  = "pending"
+	SuggestionAccepted = "accepted"
+	SuggestionRejected = "rejected"
+)
+
+const (
+	minSuggestionLength = 10
+	maxSuggestionLength = 2000
+)
+
+type Suggestion struct {
+	Id         int
+	Kind       string
+	Message    string
+	RecipeId   *int
+	Status     string
+	CreatedAt  time.Time
+	ReviewedAt *time.Time
+}
+
+type SuggestionInputDto struct {
+	Kind     string `json:"kind"`
+	Message  string `json:"message"`
+	RecipeId *ID    `json:"recipe_id"`
+	// Website is a honeypot: the form hides it, so only bots fill it in.
+	Website string `json:"website"`
+}
+
+// Validate checks the suggestion before it is stored. Catalog errors must
+// say which recipe they are about.
+func (d SuggestionInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	switch d.Kind {
+	case SuggestionKindRecipeIdea:
+	case SuggestionKindCatalogError:
+		if d.RecipeId == nil {
+			errs["recipe_id"] = "is required for catalog errors"
+		}
+	default:
+		errs["kind"] = "must be recipe_idea or catalog_error"
+	}
+
+	if length := len([]rune(strings.TrimSpace(d.Message))); length < minSuggestionLength || length > maxSuggestionLength {
+		errs["message"] = "must be between 10 and 2000 characters"
+	}
+
+	return errs.err()
+}
+
+type SuggestionReviewInputDto struct {
+	Status string `json:"status"`
+}
+
+type SuggestionDto struct {
+	Id         int        `json:"id"`
+	Kind       string     `json:"kind"`
+	Message    string     `json:"message"`
+	RecipeId   *int       `json:"recipe_id,omitempty"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+func NewSuggestionDtos(suggestions []Suggestion) []SuggestionDto {
+	dtos := make([]SuggestionDto, len(suggestions))
+	for i, suggestion := range suggestions {
+		dtos[i] = SuggestionDto{
+			Id:         suggestion.Id,
+			Kind:       suggestion.Kind,
+			Message:    suggestion.Message,
+			RecipeId:   suggestion.RecipeId,
+			Status:     suggestion.Status,
+			CreatedAt:  suggestion.CreatedAt,
+			ReviewedAt: suggestion.ReviewedAt,
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,74 @@
+package models
+
+// Tag is a label recipes share across categories, such as "vegan" or
+// "quick", with the number of recipes carrying it.
+type Tag struct {
+	Name    string
+	Recipes int
+}
+
+// RecipeFilter narrows a recipe list. Empty fields don't filter.
+type RecipeFilter struct {
+	// Categories keeps the recipes in any of the named categories.
+	Categories []string
+	// Tags keeps the recipes carrying all of the tags, or any of them when
+	// AnyTag is set. They are listed as NormalizeTags returns them.
+	Tags   []string
+	AnyTag bool
+}
+
+type RecipeTagsInputDto struct {
+	Tags []string `json:"tags"`
+}
+
+// maxTagLength is the longest tag accepted, in bytes.
+const maxTagLength = 50
+
+// Normalize returns the tags of the input as they are stored: slugs, so
+// "Sem Glúten" is "sem-gluten", each listed once.
+func (d RecipeTagsInputDto) Normalize() ([]string, error) {
+	errs := ValidationErrors{}
+
+	if len(d.Tags) == 0 {
+		errs["tags"] = "must not be empty"
+	}
+
+	for _, tag := range d.Tags {
+		if slug := Slug(tag); slug == "" {
+			errs["tags"] = "must only contain tags with letters or digits"
+		} else if len(slug) > maxTagLength {
+			errs["tags"] = "must only contain tags of at most 50 characters"
+		}
+	}
+
+	return NormalizeTags(d.Tags), errs.err()
+}
+
+// NormalizeTags returns the slugs of tags, each once and in their first
+// order, leaving out the ones that have no letters or digits.
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		if tag = Slug(tag); tag != "" && !seen[tag] {
+			normalized = append(normalized, tag)
+			seen[tag] = true
+		}
+	}
+
+	return normalized
+}
+
+type TagDto struct {
+	Name    string `json:"name"`
+	Recipes int    `json:"recipes"`
+}
+
+func NewTagDtos(tags []Tag) []TagDto {
+	dtos := make([]TagDto, len(tags))
+	for i, tag := range tags {
+		dtos[i] = TagDto{Name: tag.Name, Recipes: tag.Recipes}
+	}
+	return dtos
+}
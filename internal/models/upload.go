@@ -0,0 +1,76 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type PresignUploadInputDto struct {
+	RecipeId    ID     `json:"recipe_id"`
+	ContentType string `json:"content_type"`
+	// ContentLength is the size in bytes of the file to upload.
+	ContentLength int64 `json:"content_length"`
+}
+
+// UnmarshalJSON also accepts the camelCase keys used before the API moved
+// to snake_case.
+func (d *PresignUploadInputDto) UnmarshalJSON(data []byte) error {
+	type presignUploadInput PresignUploadInputDto
+
+	var legacy struct {
+		RecipeId      ID     `json:"recipeId"`
+		ContentType   string `json:"contentType"`
+		ContentLength int64  `json:"contentLength"`
+	}
+
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	d.RecipeId = legacy.RecipeId
+	d.ContentType = legacy.ContentType
+	d.ContentLength = legacy.ContentLength
+
+	return json.Unmarshal(data, (*presignUploadInput)(d))
+}
+
+// PresignUploadDto tells the client where to upload. Headers must be sent
+// with the upload exactly as given, since they are part of the signature.
+type PresignUploadDto struct {
+	Url       string            `json:"url"`
+	Key       string            `json:"key"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// UploadDto describes a stored upload. Thumbnails maps a width in pixels to
+// the URL of the image scaled down to it.
+type UploadDto struct {
+	Url        string            `json:"url"`
+	Key        string            `json:"key"`
+	Thumbnails map[string]string `json:"thumbnails"`
+}
+
+type ConfirmUploadInputDto struct {
+	RecipeId ID     `json:"recipe_id"`
+	Key      string `json:"key"`
+}
+
+// UnmarshalJSON also accepts the camelCase keys used before the API moved
+// to snake_case.
+func (d *ConfirmUploadInputDto) UnmarshalJSON(data []byte) error {
+	type confirmUploadInput ConfirmUploadInputDto
+
+	var legacy struct {
+		RecipeId ID `json:"recipeId"`
+	}
+
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+
+	d.RecipeId = legacy.RecipeId
+
+	return json.Unmarshal(data, (*confirmUploadInput)(d))
+}
@@ -0,0 +1,119 @@
+package models
+
+import (
+	"gastro-galaxy-back/internal/quantity"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ValidationErrors maps the JSON name of every invalid field of an input to
+// what is wrong with it.
+type ValidationErrors map[string]string
+
+func (e ValidationErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field + " " + e[field]
+	}
+	return strings.Join(messages, "; ")
+}
+
+// err returns e, or nil when it holds no errors.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the recipe before it is written: a name, a non-negative
+// category, a valid image URL if any and at least one ingredient.
+func (d RecipeInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(d.Name) == "" {
+		errs["name"] = "is required"
+	}
+
+	if d.CategoryId < 0 {
+		errs["category_id"] = "must not be negative"
+	}
+
+	if d.Url != "" && !isWebURL(d.Url) {
+		errs["image_url"] = "must be an absolute http or https URL"
+	}
+
+	if len(d.IngedientIds) == 0 {
+		errs["ingredient_ids"] = "must not be empty"
+	}
+
+	for _, id := range d.IngedientIds {
+		if id <= 0 {
+			errs["ingredient_ids"] = "must only contain positive ids"
+			break
+		}
+	}
+
+	return errs.err()
+}
+
+// Validate checks the ingredient before it is written: a name, a valid
+// image URL if any and a known unit sent along with the quantity.
+func (d IngredientInputDto) Validate() error {
+	errs := ValidationErrors{}
+
+	if strings.TrimSpace(d.Name) == "" {
+		errs["name"] = "is required"
+	}
+
+	if d.Url != "" && !isWebURL(d.Url) {
+		errs["image_url"] = "must be an absolute http or https URL"
+	}
+
+	if d.Quantity != nil && *d.Quantity < 0 {
+		errs["quantity"] = "must not be negative"
+	}
+
+	if msg := checkUnit(d.Quantity, d.Unit); msg != "" {
+		errs["unit"] = msg
+	}
+
+	if d.Nutrition != nil && (d.Nutrition.Calories < 0 || d.Nutrition.Protein < 0 || d.Nutrition.Fat < 0 || d.Nutrition.Carbs < 0) {
+		errs["nutrition"] = "must not hold negative values"
+	}
+
+	return errs.err()
+}
+
+// checkUnit says what is wrong with a structured amount, or returns "".
+func checkUnit(q *float64, unit string) string {
+	if unit != "" && !quantity.Unit(unit).Valid() {
+		return "must be one of " + unitList()
+	}
+
+	if (q == nil) != (unit == "") {
+		return "must be sent along with quantity"
+	}
+
+	return ""
+}
+
+func unitList() string {
+	units := make([]string, len(quantity.Units))
+	for i, unit := range quantity.Units {
+		units[i] = string(unit)
+	}
+	return strings.Join(units, ", ")
+}
+
+func isWebURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,161 @@
+// Package quantity reads ingredient amounts written for people, such as
+// "2 xícaras" or "200g", into a number and one of a fixed set of units.
+package quantity
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unit is the unit of a structured amount.
+type Unit string
+
+const (
+	Gram       Unit = "g"
+	Kilogram   Unit = "kg"
+	Milligram  Unit = "mg"
+	Milliliter Unit = "ml"
+	Liter      Unit = "l"
+	Pound      Unit = "lb"
+	Ounce      Unit = "oz"
+	Cup        Unit = "cup"
+	Tablespoon Unit = "tbsp"
+	Teaspoon   Unit = "tsp"
+	Pinch      Unit = "pinch"
+	Clove      Unit = "clove"
+	Can        Unit = "can"
+	Bunch      Unit = "bunch"
+	Slice      Unit = "slice"
+	// Piece counts whole items, as in "3 ovos".
+	Piece Unit = "piece"
+)
+
+// Units are all the units, as stored in the ingredient.unit column.
+var Units = []Unit{Gram, Kilogram, Milligram, Milliliter, Liter, Pound, Ounce, Cup, Tablespoon, Teaspoon, Pinch, Clove, Can, Bunch, Slice, Piece}
+
+func (u Unit) Valid() bool {
+	for _, unit := range Units {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// Amount is a structured amount, e.g. 2 Cup.
+type Amount struct {
+	Quantity float64
+	Unit     Unit
+}
+
+// String writes the amount the way the API stores it, e.g. "200 g" or
+// "1.5 cup", rounded to two decimals. Pieces are written as just the
+// number.
+func (a Amount) String() string {
+	number := strconv.FormatFloat(math.Round(a.Quantity*100)/100, 'f', -1, 64)
+	if a.Unit == Piece {
+		return number
+	}
+	return number + " " + string(a.Unit)
+}
+
+// NumberPattern matches a quantity such as "2", "1,5", "1/2" or "½".
+const NumberPattern = `(?:\d+/\d+|\d+(?:[.,]\d+)?|[¼½¾⅓⅔⅛])`
+
+// UnitPattern matches the unit words UnitOf knows, ignoring case.
+const UnitPattern = `kg|g|mg|l|ml|dl|cl|lb|lbs|oz|cups?|xícaras?|xic|tbsp|tablespoons?|tsp|teaspoons?|colher(?:es)?(?:\s+de\s+(?:sopa|chá|sobremesa))?|pitadas?|pinch(?:es)?|dentes?|cloves?|latas?|cans?|maços?|bunch(?:es)?|fatias?|slices?|unidades?|un|pieces?|pedaços?`
+
+var amountPattern = regexp.MustCompile(`^(` + NumberPattern + `)(?:\s+(` + NumberPattern + `))?\s*(?:((?i:` + UnitPattern + `))\.?)?(?:\s+(?i:de|of))?$`)
+
+// unitWord is what a unit word stands for: factor times unit.
+type unitWord struct {
+	unit   Unit
+	factor float64
+}
+
+var unitWords = map[string]unitWord{
+	"g": {Gram, 1}, "kg": {Kilogram, 1}, "mg": {Milligram, 1},
+	"ml": {Milliliter, 1}, "l": {Liter, 1}, "dl": {Milliliter, 100}, "cl": {Milliliter, 10},
+	"lb": {Pound, 1}, "lbs": {Pound, 1}, "oz": {Ounce, 1},
+	"cup": {Cup, 1}, "cups": {Cup, 1}, "xícara": {Cup, 1}, "xícaras": {Cup, 1}, "xic": {Cup, 1},
+	"tbsp": {Tablespoon, 1}, "tablespoon": {Tablespoon, 1}, "tablespoons": {Tablespoon, 1},
+	"colher": {Tablespoon, 1}, "colheres": {Tablespoon, 1},
+	"colher de sopa": {Tablespoon, 1}, "colheres de sopa": {Tablespoon, 1},
+	"tsp": {Teaspoon, 1}, "teaspoon": {Teaspoon, 1}, "teaspoons": {Teaspoon, 1},
+	"colher de chá": {Teaspoon, 1}, "colheres de chá": {Teaspoon, 1},
+	"colher de sobremesa": {Teaspoon, 2}, "colheres de sobremesa": {Teaspoon, 2},
+	"pitada": {Pinch, 1}, "pitadas": {Pinch, 1}, "pinch": {Pinch, 1}, "pinches": {Pinch, 1},
+	"dente": {Clove, 1}, "dentes": {Clove, 1}, "clove": {Clove, 1}, "cloves": {Clove, 1},
+	"lata": {Can, 1}, "latas": {Can, 1}, "can": {Can, 1}, "cans": {Can, 1},
+	"maço": {Bunch, 1}, "maços": {Bunch, 1}, "bunch": {Bunch, 1}, "bunches": {Bunch, 1},
+	"fatia": {Slice, 1}, "fatias": {Slice, 1}, "slice": {Slice, 1}, "slices": {Slice, 1},
+	"unidade": {Piece, 1}, "unidades": {Piece, 1}, "un": {Piece, 1},
+	"piece": {Piece, 1}, "pieces": {Piece, 1}, "pedaço": {Piece, 1}, "pedaços": {Piece, 1},
+}
+
+var fractions = map[string]float64{"¼": 0.25, "½": 0.5, "¾": 0.75, "⅓": 1.0 / 3, "⅔": 2.0 / 3, "⅛": 0.125}
+
+// Parse reads an amount such as "2 cups", "1 ½ xícaras de", "200g" or "3".
+// Amounts without a unit count pieces. It returns false for text that is
+// not just an amount, such as ranges or "a gosto".
+func Parse(text string) (Amount, bool) {
+	text = strings.Join(strings.Fields(text), " ")
+
+	match := amountPattern.FindStringSubmatch(text)
+	if match == nil {
+		return Amount{}, false
+	}
+
+	quantity, ok := ParseNumber(match[1])
+	if !ok {
+		return Amount{}, false
+	}
+
+	if match[2] != "" {
+		// A whole number followed by a fraction, as in "1 ½".
+		fraction, ok := ParseNumber(match[2])
+		if !ok || fraction >= 1 || quantity != float64(int(quantity)) {
+			return Amount{}, false
+		}
+		quantity += fraction
+	}
+
+	if match[3] == "" {
+		return Amount{Quantity: quantity, Unit: Piece}, true
+	}
+
+	unit, factor, ok := UnitOf(match[3])
+	if !ok {
+		return Amount{}, false
+	}
+
+	return Amount{Quantity: quantity * factor, Unit: unit}, true
+}
+
+// ParseNumber reads a quantity matched by NumberPattern.
+func ParseNumber(s string) (float64, bool) {
+	if value, ok := fractions[s]; ok {
+		return value, true
+	}
+
+	if numerator, denominator, ok := strings.Cut(s, "/"); ok {
+		n, err1 := strconv.Atoi(numerator)
+		d, err2 := strconv.Atoi(denominator)
+		if err1 != nil || err2 != nil || d == 0 {
+			return 0, false
+		}
+		return float64(n) / float64(d), true
+	}
+
+	value, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return value, err == nil
+}
+
+// UnitOf returns the unit a word matched by UnitPattern stands for, and
+// the factor to multiply quantities by, e.g. 100 for "dl" in Milliliter.
+func UnitOf(word string) (Unit, float64, bool) {
+	w, ok := unitWords[strings.ToLower(strings.Join(strings.Fields(word), " "))]
+	return w.unit, w.factor, ok
+}
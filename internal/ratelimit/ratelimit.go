@@ -0,0 +1,60 @@
+// Package ratelimit limits how often a client may do something.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneAt is the number of tracked keys above which expired windows are
+// dropped, so the limiter's memory stays bounded.
+const pruneAt = 10000
+
+// Limiter allows up to n events per key in each fixed window.
+type Limiter struct {
+	mu     sync.Mutex
+	n      int
+	window time.Duration
+	hits   map[string]*window
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// New returns a Limiter allowing n events per key every window.
+func New(n int, every time.Duration) *Limiter {
+	return &Limiter{n: n, window: every, hits: make(map[string]*window)}
+}
+
+// Allow records an event for key and reports whether it is within the
+// limit. When it isn't, retryAfter is how long until the key may try again.
+func (l *Limiter) Allow(key string) (ok bool, retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if len(l.hits) > pruneAt {
+		for k, w := range l.hits {
+			if now.Sub(w.start) >= l.window {
+				delete(l.hits, k)
+			}
+		}
+	}
+
+	w, found := l.hits[key]
+
+	if !found || now.Sub(w.start) >= l.window {
+		w = &window{start: now}
+		l.hits[key] = w
+	}
+
+	if w.count >= l.n {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+
+	w.count++
+	return true, 0
+}
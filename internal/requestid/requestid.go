@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Header carries the request id on incoming requests, responses and
+// outgoing calls to other systems.
+const Header = "X-Request-Id"
+
+// FromContext returns the id of the request ctx belongs to, or "".
+func FromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
+
+// Middleware assigns every request an id, reusing the X-Request-Id sent by
+// the client or proxy when present, and echoes it in the response.
+func Middleware(next http.Handler) http.Handler {
+	return middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(Header, FromContext(r.Context()))
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// Transport adds the request id found in the outgoing request's context as
+// an X-Request-Id header.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if id := FromContext(req.Context()); id != "" && req.Header.Get(Header) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(Header, id)
+	}
+
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/analytics"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAnalyticsDays  = 30
+	defaultAnalyticsLimit = 20
+	maxAnalyticsLimit     = 100
+)
+
+// analyticsEnabled turns the anonymous usage analytics on, unless
+// ANALYTICS_ENABLED is false.
+var analyticsEnabled = os.Getenv("ANALYTICS_ENABLED") != "false"
+
+// analyticsRetention is how long usage events are kept.
+var analyticsRetention = time.Duration(envInt("ANALYTICS_RETENTION_DAYS", 90)) * 24 * time.Hour
+
+var analyticsKinds = map[string]bool{
+	models.AnalyticsKindAPI:    true,
+	models.AnalyticsKindPage:   true,
+	models.AnalyticsKindSearch: true,
+}
+
+// PostPageViewHandler records that a frontend page was viewed. Only the
+// path is kept.
+func (s *Server) PostPageViewHandler(w http.ResponseWriter, r *http.Request) {
+
+	var input models.PageViewInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if !strings.HasPrefix(input.Path, "/") {
+		writeError(w, r, models.ValidationErrors{"path": "must start with /"})
+		return
+	}
+
+	s.analytics.Record(r, models.AnalyticsKindPage, analytics.ScrubPath(input.Path))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAnalyticsHandler returns the most frequent events of a kind ("api",
+// "page" or "search") over the last ?days=, at most ?limit= of them.
+func (s *Server) GetAnalyticsHandler(w http.ResponseWriter, r *http.Request) {
+
+	kind := r.PathValue("kind")
+
+	if !analyticsKinds[kind] {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Unknown event kind"))
+		return
+	}
+
+	days, err := queryInt(r, "days", defaultAnalyticsDays)
+
+	if err != nil || days < 1 {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "days must be a positive integer"))
+		return
+	}
+
+	limit, err := queryInt(r, "limit", defaultAnalyticsLimit)
+
+	if err != nil || limit < 1 || limit > maxAnalyticsLimit {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "limit must be between 1 and "+strconv.Itoa(maxAnalyticsLimit)))
+		return
+	}
+
+	counts, err := s.db.GetAnalyticsCounts(r.Context(), kind, time.Now().AddDate(0, 0, -days), limit)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(counts)
+}
+
+// queryInt reads an integer query parameter, fallback when it is absent.
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	param := r.URL.Query().Get(name)
+
+	if param == "" {
+		return fallback, nil
+	}
+
+	return strconv.Atoi(param)
+}
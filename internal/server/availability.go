@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"io"
+	"net/http"
+)
+
+// PatchIngredientAvailabilityHandler sets whether an ingredient is on hand.
+// An empty body, or one without is_available, flips the current value.
+func (s *Server) PatchIngredientAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+
+	ingredientId, err := pathID(r, "ingredientId", s.db.IngredientIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.IngredientAvailabilityInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	ingredients, err := s.db.SetIngredientsAvailability(r.Context(), []int{ingredientId}, input.IsAvailable)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Ingredient not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/ingredients")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewIngredientDto(ingredients[0]))
+}
+
+// PatchIngredientsAvailabilityHandler sets, or flips when is_available is
+// left out, the availability of every ingredient in ingredient_ids at once.
+func (s *Server) PatchIngredientsAvailabilityHandler(w http.ResponseWriter, r *http.Request) {
+
+	var input models.IngredientAvailabilityInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if len(input.IngredientIds) == 0 {
+		writeError(w, r, models.ValidationErrors{"ingredient_ids": "must not be empty"})
+		return
+	}
+
+	ingredients, err := s.db.SetIngredientsAvailability(r.Context(), input.IngredientIds, input.IsAvailable)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown ingredient ids").WithCode("unknown_reference"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/ingredients")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewIngredientDtos(ingredients))
+}
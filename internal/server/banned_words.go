@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"gastro-galaxy-back/internal/httperr"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// bannedWordsMode decides what happens to text containing banned words:
+// "reject" (default) refuses the request, "mask" stars the words out.
+var bannedWordsMode = os.Getenv("BANNED_WORDS_MODE")
+
+// filterText checks user-provided text against the banned word list. In mask
+// mode the fields are rewritten in place; otherwise a 422 is written and false
+// is returned.
+func (s *Server) filterText(w http.ResponseWriter, r *http.Request, fields ...*string) bool {
+	if err := s.checkText(fields...); err != nil {
+		writeError(w, r, err)
+		return false
+	}
+	return true
+}
+
+// checkText is filterText for the services: it masks the banned words in
+// the fields, or returns the 422 refusing them.
+func (s *Server) checkText(fields ...*string) error {
+	if s.filter == nil {
+		return nil
+	}
+
+	if bannedWordsMode == "mask" {
+		for _, field := range fields {
+			*field = s.filter.Mask(*field)
+		}
+		return nil
+	}
+
+	for _, field := range fields {
+		if found := s.filter.Find(*field); len(found) > 0 {
+			return httperr.New(http.StatusUnprocessableEntity, "Text contains banned words: "+strings.Join(found, ", "))
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) reloadBannedWords(ctx context.Context) error {
+	words, err := s.db.GetBannedWords(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	s.filter.Set(words)
+	return nil
+}
+
+func (s *Server) GetBannedWordsHandler(w http.ResponseWriter, r *http.Request) {
+
+	words, err := s.db.GetBannedWords(r.Context())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if words == nil {
+		words = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(words)
+}
+
+func (s *Server) InsertBannedWordsHandler(w http.ResponseWriter, r *http.Request) {
+
+	var words []string
+
+	if err := json.NewDecoder(r.Body).Decode(&words); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+
+		if word == "" {
+			continue
+		}
+
+		if err := s.db.InsertBannedWord(r.Context(), word); err != nil {
+			writeError(w, r, err)
+			return
+		}
+	}
+
+	if err := s.reloadBannedWords(r.Context()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) DeleteBannedWordHandler(w http.ResponseWriter, r *http.Request) {
+
+	word := strings.ToLower(r.PathValue("word"))
+
+	if err := s.db.DeleteBannedWord(r.Context(), word); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := s.reloadBannedWords(r.Context()); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
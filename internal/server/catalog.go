@@ -0,0 +1,176 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/jsonstream"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// maxImportBytes caps the size of documents sent to POST /import/recipes.
+var maxImportBytes = envInt("IMPORT_MAX_BYTES", 50<<20)
+
+// ExportRecipesHandler streams the whole catalog as one JSON document, in
+// the format POST /import/recipes reads.
+func (s *Server) ExportRecipesHandler(w http.ResponseWriter, r *http.Request) {
+
+	export := &catalogWriter{w: w, exportedAt: time.Now().UTC()}
+
+	err := s.db.ExportCatalog(r.Context(), models.CatalogVisitor{
+		Category: func(category models.Category) error {
+			return export.write("categories", models.NewCatalogCategoryDto(category))
+		},
+		Ingredient: func(ingredient models.Ingedient) error {
+			return export.write("ingredients", models.NewCatalogIngredientDto(ingredient))
+		},
+		Recipe: func(recipe models.CatalogRecipe) error {
+			return export.write("recipes", models.NewCatalogRecipeDto(recipe))
+		},
+	})
+
+	if err == nil {
+		err = export.close()
+	}
+
+	if err != nil {
+		if !export.started {
+			writeError(w, r, err)
+			return
+		}
+		log.Printf("streaming export failed: %v", err)
+	}
+}
+
+// ImportRecipesHandler loads a document written by GET /export/recipes.
+// With ?mode=skip, the default, rows whose uuid already exists are left
+// alone; with ?mode=overwrite they are replaced.
+func (s *Server) ImportRecipesHandler(w http.ResponseWriter, r *http.Request) {
+
+	mode := r.URL.Query().Get("mode")
+
+	if mode == "" {
+		mode = models.ImportModeSkip
+	}
+
+	if mode != models.ImportModeSkip && mode != models.ImportModeOverwrite {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "mode must be skip or overwrite"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxImportBytes))
+
+	var catalog models.CatalogDto
+
+	if err := json.NewDecoder(r.Body).Decode(&catalog); err != nil {
+		writeError(w, r, uploadError(err))
+		return
+	}
+
+	if err := catalog.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	summary, err := s.db.ImportCatalog(r.Context(), catalog.Catalog(), mode == models.ImportModeOverwrite)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.ImportsCompleted.WithLabelValues("json").Inc()
+
+	s.purge(r.Context(), "/recipes")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(summary)
+}
+
+// catalogSections are the arrays of an export document, in order.
+var catalogSections = []string{"categories", "ingredients", "recipes"}
+
+// catalogWriter writes an export document as its rows arrive. Nothing is
+// sent before the first row, so a failure to start the export can still
+// be answered with an error status.
+type catalogWriter struct {
+	w          http.ResponseWriter
+	exportedAt time.Time
+	started    bool
+	section    int
+	array      *jsonstream.ArrayWriter
+}
+
+// write adds v to the named section, closing the sections before it.
+func (c *catalogWriter) write(section string, v any) error {
+	if err := c.start(); err != nil {
+		return err
+	}
+
+	for c.array == nil || catalogSections[c.section] != section {
+		if err := c.next(); err != nil {
+			return err
+		}
+	}
+
+	return c.array.Write(v)
+}
+
+// start sends the headers and the opening of the document, once.
+func (c *catalogWriter) start() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+
+	c.w.Header().Set("Content-Type", "application/json")
+	c.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="recipes-%s.json"`, c.exportedAt.Format("2006-01-02")))
+	c.w.WriteHeader(http.StatusOK)
+
+	exportedAt, _ := json.Marshal(c.exportedAt)
+	_, err := fmt.Fprintf(c.w, `{"version":%d,"exported_at":%s`, models.CatalogVersion, exportedAt)
+	return err
+}
+
+// next closes the current section and opens the following one.
+func (c *catalogWriter) next() error {
+	if c.array != nil {
+		if err := c.array.Close(); err != nil {
+			return err
+		}
+		c.section++
+	}
+
+	if _, err := fmt.Fprintf(c.w, `,%q:`, catalogSections[c.section]); err != nil {
+		return err
+	}
+
+	c.array = jsonstream.NewArrayWriter(c.w)
+	return nil
+}
+
+// close writes the sections no row was written to and ends the document.
+func (c *catalogWriter) close() error {
+	if err := c.start(); err != nil {
+		return err
+	}
+
+	for c.array == nil || c.section < len(catalogSections)-1 {
+		if err := c.next(); err != nil {
+			return err
+		}
+	}
+
+	if err := c.array.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(c.w, "}\n")
+	return err
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+)
+
+func (s *Server) InsertCategoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	var categoryDto models.CategoryInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&categoryDto); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	id, err := s.categories.Create(r.Context(), categoryDto)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/categories")
+
+	writeCreated(w, r, "Category", id)
+}
+
+func (s *Server) GetCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+
+	categories, err := s.categories.List(r.Context())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(categories)
+}
+
+func (s *Server) PutCategoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	categoryId, err := pathID(r, "categoryId", s.db.CategoryIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var categoryDto models.CategoryInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&categoryDto); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	category, err := s.categories.Rename(r.Context(), categoryId, categoryDto)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Category not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/categories")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(category)
+}
+
+func (s *Server) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	categoryId, err := pathID(r, "categoryId", s.db.CategoryIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	err = s.categories.Delete(r.Context(), categoryId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Category not found"))
+		return
+	}
+
+	if errors.Is(err, database.ErrInUse) {
+		writeError(w, r, httperr.New(http.StatusConflict, "Category still has recipes"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/categories")
+
+	w.WriteHeader(http.StatusNoContent)
+}
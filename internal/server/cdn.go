@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// cdnBaseURL is the public URL the CDN serves this API from.
+var cdnBaseURL = strings.TrimSuffix(os.Getenv("CDN_BASE_URL"), "/")
+
+// purge asks the CDN to drop its cached copies of paths, both at the root
+// and under /v1. Paths are purged as prefixes, so "/recipes" also drops
+// "/recipes?page=2&tags=vegan" and the other variants of the list. It runs
+// in the background so a slow CDN API never delays the response, but keeps
+// the values of ctx (such as the request id) for the outgoing call.
+func (s *Server) purge(ctx context.Context, paths ...string) {
+	if s.cdn == nil {
+		return
+	}
+
+	urls := make([]string, 0, 2*len(paths))
+	for _, path := range paths {
+		urls = append(urls, cdnBaseURL+path, cdnBaseURL+"/v1"+path)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		if err := s.cdn.Purge(ctx, urls); err != nil {
+			log.Printf("cdn purge of %v failed: %v", urls, err)
+		}
+	}()
+}
+
+// purgeRecipe purges a recipe under both its serial id and its uuid, along
+// with the recipe list.
+func (s *Server) purgeRecipe(ctx context.Context, recipeId int) {
+	recipeUUID, err := s.db.RecipeUUIDById(ctx, recipeId)
+
+	if err != nil {
+		log.Printf("cdn purge: looking up uuid of recipe %d failed: %v", recipeId, err)
+	}
+
+	s.purge(ctx, recipePaths(recipeId, recipeUUID)...)
+}
+
+// recipePaths are the cached paths that show a recipe. recipeUUID may be
+// empty when it isn't known.
+func recipePaths(recipeId int, recipeUUID string) []string {
+	paths := []string{fmt.Sprintf("/recipe/%d", recipeId), "/recipes"}
+
+	if recipeUUID != "" {
+		paths = append(paths, "/recipe/"+recipeUUID)
+	}
+	return paths
+}
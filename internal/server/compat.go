@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"os"
+)
+
+// compatMode keeps the unprefixed routes on the contract the current
+// frontend was built against: plain text create and update responses, and
+// GET /recipes reading its filter from the body. Routes under /v1 always
+// use the JSON contract. Set API_COMPAT_MODE=false once every client has
+// moved, so both behave the same.
+var compatMode = os.Getenv("API_COMPAT_MODE") != "false"
+
+type legacyContractKey struct{}
+
+func legacyContract(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), legacyContractKey{}, true)))
+	})
+}
+
+// isLegacy reports whether r is served with the pre-v1 contract.
+func isLegacy(r *http.Request) bool {
+	legacy, _ := r.Context().Value(legacyContractKey{}).(bool)
+	return legacy
+}
+
+// writeCreated answers a create request with the id of the new row, as
+// "<kind> id: <id>" for legacy clients and as JSON otherwise.
+func writeCreated(w http.ResponseWriter, r *http.Request, kind string, id int) {
+
+	if isLegacy(r) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, "%s id: %d", kind, id)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.CreatedDto{Id: id})
+}
+
+// writeUpdated answers an update request. Legacy clients get the status and
+// text they have always received, others an empty 204.
+func writeUpdated(w http.ResponseWriter, r *http.Request, legacyStatus int, legacyText string) {
+
+	if isLegacy(r) {
+		w.WriteHeader(legacyStatus)
+		fmt.Fprint(w, legacyText)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// redacted replaces the value of secret settings that are set.
+const redacted = "[redacted]"
+
+// secretSettings are shown as set or not, never with their value.
+var secretSettings = []string{
+	"DB_PASSWORD",
+	"ADMIN_TOKEN",
+	"S3_ACCESS_KEY",
+	"S3_SECRET_KEY",
+	"CLOUDFLARE_API_TOKEN",
+	"TELEGRAM_BOT_TOKEN",
+	"SLACK_SIGNING_SECRET",
+}
+
+// plainSettings are read by other packages and shown as they are set.
+var plainSettings = []string{
+	"DB_HOST",
+	"DB_PORT",
+	"DB_DATABASE",
+	"DB_USERNAME",
+	"DB_LOG_LEVEL",
+	"S3_BUCKET",
+	"S3_ENDPOINT",
+	"S3_REGION",
+	"S3_PUBLIC_URL",
+	"CDN_PROVIDER",
+	"CLOUDFLARE_ZONE_ID",
+	"CLOUDFRONT_DISTRIBUTION_ID",
+}
+
+// runtimeConfig returns the configuration s runs with. Settings parsed by
+// this package show their value after defaults and bounds are applied;
+// features reflect the integrations that actually started.
+func (s *Server) runtimeConfig() models.RuntimeConfigDto {
+	settings := map[string]string{
+		"PORT":                     strconv.Itoa(s.port),
+		"SHUTDOWN_TIMEOUT":         shutdownTimeout.String(),
+		"DB_DRIVER":                orDefault(os.Getenv("DB_DRIVER"), "postgres"),
+		"PUBLIC_BASE_URL":          publicBaseURL,
+		"MAX_LIST_ROWS":            strconv.Itoa(database.MaxListRows),
+		"MAX_PAGE_SIZE":            strconv.Itoa(maxPageSize),
+		"ID_FORMAT":                defaultIDFormat,
+		"HEALTH_CHECKS_DISABLED":   strings.Join(disabledHealthChecks, ","),
+		"UPLOAD_MAX_BYTES":         strconv.Itoa(maxUploadBytes),
+		"IMPORT_MAX_BYTES":         strconv.Itoa(maxImportBytes),
+		"IMAGE_IMPORT_MAX_BYTES":   strconv.Itoa(maxImageImportBytes),
+		"CDN_BASE_URL":             cdnBaseURL,
+		"LINK_CHECK_INTERVAL":      linkCheckInterval().String(),
+		"CLASSIFY_INTERVAL":        classifyInterval().String(),
+		"ANALYTICS_RETENTION_DAYS": strconv.Itoa(int(analyticsRetention.Hours() / 24)),
+		"PREP_LOG_RETENTION_DAYS":  strconv.Itoa(int(prepLogRetention.Hours() / 24)),
+		"SUGGESTIONS_PER_HOUR":     strconv.Itoa(envInt("SUGGESTIONS_PER_HOUR", 5)),
+		"BANNED_WORDS_MODE":        orDefault(bannedWordsMode, "reject"),
+		"SHADOW_URL":               redactURL(os.Getenv("SHADOW_URL")),
+		"SHADOW_PERCENT":           strconv.Itoa(envInt("SHADOW_PERCENT", 10)),
+	}
+
+	for _, name := range plainSettings {
+		settings[name] = os.Getenv(name)
+	}
+
+	for _, name := range secretSettings {
+		if os.Getenv(name) != "" {
+			settings[name] = redacted
+		} else {
+			settings[name] = ""
+		}
+	}
+
+	return models.RuntimeConfigDto{
+		Settings: settings,
+		Features: map[string]bool{
+			"api_compat_mode":  compatMode,
+			"migrate_on_start": os.Getenv("MIGRATE_ON_START") != "false",
+			"uploads":          s.storage != nil,
+			"cdn_purge":        s.cdn != nil,
+			"shadow":           s.shadow != nil,
+			"telegram":         os.Getenv("TELEGRAM_BOT_TOKEN") != "",
+			"slack":            s.slackSecret != "",
+			"analytics":        analyticsEnabled,
+			"link_checker":     linkCheckInterval() > 0,
+			"classifier":       classifyInterval() > 0,
+			"kitchen_mode":     kitchenMode,
+			"demo_mode":        demoMode,
+		},
+	}
+}
+
+// logConfig writes the configuration s runs with to the log as one JSON
+// line, so it can be found and parsed in the startup output.
+func (s *Server) logConfig() {
+	encoded, err := json.Marshal(s.runtimeConfig())
+
+	if err != nil {
+		log.Printf("cannot encode the configuration: %v", err)
+		return
+	}
+
+	log.Printf("config: %s", encoded)
+}
+
+// GetConfigHandler returns the configuration the instance runs with, for
+// operators to check what a deployment actually loaded.
+func (s *Server) GetConfigHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(s.runtimeConfig())
+}
+
+// redactURL hides the password of a URL carrying credentials.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+
+	if err != nil {
+		return redacted
+	}
+
+	return u.Redacted()
+}
+
+func orDefault(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
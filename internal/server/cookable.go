@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"strconv"
+)
+
+// GetCookableRecipesHandler lists the recipes that can be made with the
+// ingredients marked as available, allowing ?missing= absent ingredients.
+func (s *Server) GetCookableRecipesHandler(w http.ResponseWriter, r *http.Request) {
+
+	missing := 0
+
+	if param := r.URL.Query().Get("missing"); param != "" {
+		var err error
+		missing, err = strconv.Atoi(param)
+
+		if err != nil || missing < 0 {
+			writeError(w, r, httperr.New(http.StatusBadRequest, "missing must be a non-negative integer"))
+			return
+		}
+	}
+
+	s.writeCookableRecipes(w, r, nil, missing)
+}
+
+// PostCookableRecipesHandler lists the recipes that can be made with the
+// ingredients in the body, for clients that track the pantry themselves.
+func (s *Server) PostCookableRecipesHandler(w http.ResponseWriter, r *http.Request) {
+
+	var input models.CookableInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if input.Missing < 0 {
+		writeError(w, r, models.ValidationErrors{"missing": "must not be negative"})
+		return
+	}
+
+	ingredientIds := []int(input.IngredientIds)
+
+	if ingredientIds == nil {
+		ingredientIds = []int{}
+	}
+
+	s.writeCookableRecipes(w, r, ingredientIds, input.Missing)
+}
+
+func (s *Server) writeCookableRecipes(w http.ResponseWriter, r *http.Request, ingredientIds []int, missing int) {
+
+	recipes, err := s.db.GetCookableRecipes(r.Context(), ingredientIds, missing)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewCookableRecipeDtos(recipes))
+}
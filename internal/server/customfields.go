@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"net/http"
+)
+
+// GetCustomFieldsHandler lists the custom fields of recipes with their
+// schemas, so clients can build forms for them.
+func (s *Server) GetCustomFieldsHandler(w http.ResponseWriter, r *http.Request) {
+
+	fields, err := s.customFields.List(r.Context())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(fields)
+}
+
+// PutCustomFieldHandler defines a custom field, or replaces its schema. The
+// body is the JSON Schema of the field.
+func (s *Server) PutCustomFieldHandler(w http.ResponseWriter, r *http.Request) {
+
+	var schema json.RawMessage
+
+	if err := json.NewDecoder(r.Body).Decode(&schema); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	field, err := s.customFields.Define(r.Context(), r.PathValue("name"), schema)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(field)
+}
+
+// DeleteCustomFieldHandler deletes a custom field along with its values on
+// every recipe.
+func (s *Server) DeleteCustomFieldHandler(w http.ResponseWriter, r *http.Request) {
+
+	err := s.customFields.Delete(r.Context(), r.PathValue("name"))
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Custom field not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/recipes")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PutRecipeCustomFieldsHandler replaces the custom field values of a recipe
+// with the {"field": value} object in the body, and answers with them.
+func (s *Server) PutRecipeCustomFieldsHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var values map[string]json.RawMessage
+
+	if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	values, err = s.customFields.SetRecipeValues(r.Context(), recipeId, values)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(values)
+}
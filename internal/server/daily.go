@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"time"
+)
+
+// GetDailyRecipeHandler returns the recipe of the day. Days follow the
+// server's local time zone, set with TZ.
+func (s *Server) GetDailyRecipeHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipe, err := s.db.GetDailyRecipe(r.Context(), time.Now())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if recipe == nil {
+		writeError(w, r, httperr.New(http.StatusNotFound, "No recipes found"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewRecipeDto(*recipe))
+}
+
+// PinDailyRecipeHandler overrides the recipe of the day for a date, today
+// unless the body says otherwise.
+func (s *Server) PinDailyRecipeHandler(w http.ResponseWriter, r *http.Request) {
+
+	var input models.PinDailyRecipeInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	day := time.Now()
+
+	if input.Date != "" {
+		var err error
+		if day, err = time.Parse(time.DateOnly, input.Date); err != nil {
+			writeError(w, r, httperr.New(http.StatusBadRequest, "date must be formatted as YYYY-MM-DD"))
+			return
+		}
+	}
+
+	err := s.db.PinDailyRecipe(r.Context(), day, int(input.RecipeId))
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/recipes/daily", "/home")
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/fixtures"
+	"log"
+	"os"
+)
+
+// demoMode fills an empty catalog with the demo fixture on startup.
+var demoMode = os.Getenv("DEMO_MODE") == "true"
+
+// loadDemo loads the demo catalog unless the database already has one, so
+// restarting a demo doesn't duplicate it.
+func (s *Server) loadDemo(ctx context.Context) error {
+	stats, err := s.db.GetPublicStats(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	if stats.TotalRecipes > 0 || stats.TotalCategories > 0 || stats.TotalIngredients > 0 {
+		return nil
+	}
+
+	fixture, err := fixtures.Demo()
+	if err != nil {
+		return err
+	}
+
+	ids, err := fixtures.Load(ctx, s.db, fixture)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("demo mode: loaded %d recipes", len(ids.Recipes))
+	return nil
+}
@@ -0,0 +1,27 @@
+package server
+
+import (
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/requestid"
+	"log"
+	"net/http"
+)
+
+// writeError answers the request with err. Legacy clients get the message as
+// plain text, as http.Error used to write it; everyone else gets the JSON
+// envelope of httperr. Internal errors are logged, since their cause is not
+// sent to the client.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	e := httperr.From(err)
+
+	if e.Status >= http.StatusInternalServerError {
+		log.Printf("request %s failed: %v", requestid.FromContext(r.Context()), err)
+	}
+
+	if isLegacy(r) {
+		http.Error(w, e.Message, e.Status)
+		return
+	}
+
+	httperr.Write(w, e)
+}
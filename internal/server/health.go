@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout bounds each check of /health, so a hanging integration
+// can't hold the response.
+const healthCheckTimeout = 2 * time.Second
+
+// disabledHealthChecks are the integration checks HEALTH_CHECKS_DISABLED
+// turns off, e.g. "storage".
+var disabledHealthChecks = strings.Split(os.Getenv("HEALTH_CHECKS_DISABLED"), ",")
+
+// healthCheck checks that an integration answers.
+type healthCheck struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+type healthCheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// integrationChecks returns the checks of the integrations this instance is
+// configured with, less the disabled ones.
+func (s *Server) integrationChecks() []healthCheck {
+	var checks []healthCheck
+
+	if s.storage != nil {
+		checks = append(checks, healthCheck{"storage", s.storage.Ping})
+	}
+
+	return slices.DeleteFunc(checks, func(check healthCheck) bool {
+		return slices.Contains(disabledHealthChecks, check.name)
+	})
+}
+
+// runHealthChecks runs checks concurrently and returns their results by
+// name. A failing check doesn't affect the others.
+func runHealthChecks(ctx context.Context, checks []healthCheck) map[string]healthCheckResult {
+	results := make(map[string]healthCheckResult, len(checks))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.run(ctx)
+			result := healthCheckResult{Status: "up", LatencyMs: time.Since(start).Milliseconds()}
+
+			if err != nil {
+				result.Status = "down"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[check.name] = result
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
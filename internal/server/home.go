@@ -0,0 +1,76 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"time"
+)
+
+// GetHomeHandler returns everything the landing page shows: the curated
+// slots and the recipe of the day.
+func (s *Server) GetHomeHandler(w http.ResponseWriter, r *http.Request) {
+
+	slots, err := s.db.GetHomeSlots(r.Context())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	daily, err := s.db.GetDailyRecipe(r.Context(), time.Now())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewHomeDto(models.Home{Slots: slots, RecipeOfTheDay: daily}))
+}
+
+// PutHomeSlotHandler replaces the recipes shown in a landing page slot.
+func (s *Server) PutHomeSlotHandler(w http.ResponseWriter, r *http.Request) {
+
+	slot := r.PathValue("slot")
+
+	size, ok := models.HomeSlotSizes[slot]
+
+	if !ok {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Unknown slot"))
+		return
+	}
+
+	var input models.HomeSlotInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if size > 0 && len(input.RecipeIds) > size {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, fmt.Sprintf("The %s slot holds at most %d recipes", slot, size)))
+		return
+	}
+
+	err := s.db.SetHomeSlot(r.Context(), slot, input.RecipeIds)
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown recipe id"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/home")
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// pathID reads an id path parameter. It takes either the serial id or the
+// public uuid of the row, which byUUID resolves to the serial id.
+func pathID(r *http.Request, name string, byUUID func(context.Context, string) (int, error)) (int, error) {
+	value := r.PathValue(name)
+
+	id, err := models.ParseID(value)
+	if err == nil {
+		return id, nil
+	}
+
+	if parsed, uuidErr := uuid.Parse(value); uuidErr == nil {
+		return byUUID(r.Context(), parsed.String())
+	}
+
+	return 0, err
+}
+
+// uuidPathID reads an id path parameter that must be the public uuid of the
+// row, for rows only whoever was given the uuid may reach. Serial ids are
+// rejected since they can be guessed.
+func uuidPathID(r *http.Request, name string, byUUID func(context.Context, string) (int, error)) (int, error) {
+	value := r.PathValue(name)
+
+	parsed, err := uuid.Parse(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a uuid", models.ErrInvalidID, value)
+	}
+
+	return byUUID(r.Context(), parsed.String())
+}
+
+// pathIDError is the error to answer a failed pathID with. An unknown uuid
+// is a 404 through database.ErrNotFound.
+func pathIDError(err error) error {
+	if errors.Is(err, models.ErrInvalidID) {
+		return httperr.New(http.StatusBadRequest, err.Error()).WithCode("invalid_id")
+	}
+	return err
+}
+
+// defaultIDFormat is how ids are written when the request does not ask for
+// a format. "string" makes every response use string ids.
+var defaultIDFormat = os.Getenv("ID_FORMAT")
+
+// idFormat writes the ids in JSON responses as strings when the client asks
+// for ?id_format=string or ID_FORMAT=string is set. JavaScript clients lose
+// precision on integers above 2^53, so this keeps them safe if ids ever
+// grow past that. Input accepts both forms regardless.
+//
+// The response has to be rewritten as a whole, so string ids turn streamed
+// lists into buffered ones.
+func idFormat(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		format := r.URL.Query().Get("id_format")
+		if format == "" {
+			format = defaultIDFormat
+		}
+
+		if format != "string" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buf := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		body := buf.body.Bytes()
+		if strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			if rewritten, err := stringifyIDs(body); err == nil {
+				body = rewritten
+			}
+		}
+
+		w.Header().Del("Content-Length")
+		w.WriteHeader(buf.status)
+		w.Write(body)
+	})
+}
+
+type bufferedResponse struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// stringifyIDs turns the numeric values of "id", "*_id" and "*_ids" fields
+// into strings.
+func stringifyIDs(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	out, err := json.Marshal(stringifyValue(v))
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+func stringifyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if isIDKey(key) {
+				v[key] = stringifyID(value)
+			} else {
+				v[key] = stringifyValue(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = stringifyValue(value)
+		}
+	}
+	return v
+}
+
+func stringifyID(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String()
+	case []any:
+		for i, value := range v {
+			if n, ok := value.(json.Number); ok {
+				v[i] = n.String()
+			}
+		}
+	}
+	return v
+}
+
+func isIDKey(key string) bool {
+	return key == "id" || strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_ids")
+}
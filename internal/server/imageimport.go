@@ -0,0 +1,328 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxImageImportBytes caps the size of the archives sent to
+// POST /import/images.
+var maxImageImportBytes = envInt("IMAGE_IMPORT_MAX_BYTES", 200<<20)
+
+// imageImportQueueSize is how many archives can wait for the import job
+// before new ones are turned away.
+const imageImportQueueSize = 4
+
+// imageImport is an archive waiting to be imported.
+type imageImport struct {
+	id    string
+	files []*zip.File
+}
+
+// ImportImagesHandler queues a ZIP of recipe photos, sent as the "file" field
+// of a multipart form, for a background job to attach. Each file is named
+// after its recipe: its id, uuid or slug, such as "42.jpg" or
+// "pao-de-queijo.png". The answer is the report of the import, to poll at
+// GET /import/images/{importId}.
+func (s *Server) ImportImagesHandler(w http.ResponseWriter, r *http.Request) {
+
+	if s.storage == nil {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Object storage is not configured"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxImageImportBytes))
+
+	file, _, err := r.FormFile("file")
+
+	if err != nil {
+		writeError(w, r, uploadError(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+
+	if err != nil {
+		writeError(w, r, uploadError(err))
+		return
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Invalid ZIP archive: "+err.Error()))
+		return
+	}
+
+	var files []*zip.File
+
+	for _, f := range archive.File {
+		// Skip folders and the metadata macOS and others leave in archives.
+		if f.FileInfo().IsDir() || strings.HasPrefix(path.Base(f.Name), ".") || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "The archive has no files").WithCode("empty_archive"))
+		return
+	}
+
+	if len(s.imageImports) == cap(s.imageImports) {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Too many image imports are queued, try again later"))
+		return
+	}
+
+	id, err := s.db.InsertImageImport(r.Context(), len(files))
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	select {
+	case s.imageImports <- imageImport{id: id, files: files}:
+	default:
+		s.finishImageImport(r.Context(), id, models.ImageImportFailed, nil)
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Too many image imports are queued, try again later"))
+		return
+	}
+
+	imageImport, err := s.db.GetImageImport(r.Context(), id)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(models.NewImageImportDto(*imageImport))
+}
+
+// GetImageImportHandler returns the report of an image import, complete
+// once its status is done or failed.
+func (s *Server) GetImageImportHandler(w http.ResponseWriter, r *http.Request) {
+
+	id, err := uuid.Parse(r.PathValue("importId"))
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Image import not found"))
+		return
+	}
+
+	imageImport, err := s.db.GetImageImport(r.Context(), id.String())
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Image import not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewImageImportDto(*imageImport))
+}
+
+// runImageImports imports the queued archives one at a time until ctx is
+// cancelled. Imports cut short by a shutdown are marked failed.
+func (s *Server) runImageImports(ctx context.Context) {
+	for {
+		select {
+		case job := <-s.imageImports:
+			s.importImages(ctx, job)
+		case <-ctx.Done():
+			for {
+				select {
+				case job := <-s.imageImports:
+					s.finishImageImport(ctx, job.id, models.ImageImportFailed, nil)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *Server) importImages(ctx context.Context, job imageImport) {
+	results := make([]models.ImageImportResult, 0, len(job.files))
+
+	if err := s.db.UpdateImageImport(ctx, job.id, models.ImageImportRunning, results); err != nil {
+		log.Printf("image import %s: %v", job.id, err)
+	}
+
+	recipes := recipeResolver{db: s.db}
+
+	for _, f := range job.files {
+		if ctx.Err() != nil {
+			s.finishImageImport(ctx, job.id, models.ImageImportFailed, results)
+			return
+		}
+
+		result := models.ImageImportResult{File: f.Name}
+
+		if err := s.importImage(ctx, f, &recipes, &result); err != nil {
+			result.Error = err.Error()
+		}
+
+		results = append(results, result)
+
+		if err := s.db.UpdateImageImport(ctx, job.id, models.ImageImportRunning, results); err != nil {
+			log.Printf("image import %s: %v", job.id, err)
+		}
+	}
+
+	s.finishImageImport(ctx, job.id, models.ImageImportDone, results)
+}
+
+// importImage attaches the image in f to the recipe it is named after.
+func (s *Server) importImage(ctx context.Context, f *zip.File, recipes *recipeResolver, result *models.ImageImportResult) error {
+
+	name := path.Base(f.Name)
+	recipeId, err := recipes.resolve(ctx, strings.TrimSuffix(name, path.Ext(name)))
+
+	if err != nil {
+		return err
+	}
+
+	result.RecipeId = recipeId
+
+	if f.UncompressedSize64 > uint64(maxUploadBytes) {
+		return fmt.Errorf("file is larger than %d bytes", maxUploadBytes)
+	}
+
+	reader, err := f.Open()
+
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	// The declared size can lie, so the read is bounded too.
+	data, err := io.ReadAll(io.LimitReader(reader, int64(maxUploadBytes)+1))
+
+	if err != nil {
+		return err
+	}
+
+	if len(data) > maxUploadBytes {
+		return fmt.Errorf("file is larger than %d bytes", maxUploadBytes)
+	}
+
+	upload, err := s.storeImage(ctx, recipeImagePrefix(recipeId), data)
+
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.UpdateRecipeImage(ctx, recipeId, upload.Url); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return errors.New("recipe not found")
+		}
+		return err
+	}
+
+	result.Url = upload.Url
+
+	metrics.ImagesUploaded.Inc()
+
+	s.purgeRecipe(ctx, recipeId)
+
+	return nil
+}
+
+// finishImageImport saves the final status of an import. It still runs when
+// ctx is cancelled, so a shutdown doesn't leave imports queued forever.
+func (s *Server) finishImageImport(ctx context.Context, id string, status string, results []models.ImageImportResult) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := s.db.UpdateImageImport(ctx, id, status, results); err != nil {
+		log.Printf("image import %s: %v", id, err)
+	}
+}
+
+// recipeResolver finds the recipe a file is named after. The ids and slugs
+// of the recipes are loaded once, so files for unknown recipes are turned
+// down before anything is stored.
+type recipeResolver struct {
+	db    database.RecipeRepository
+	ids   map[int]bool
+	slugs map[string][]int
+}
+
+func (rr *recipeResolver) resolve(ctx context.Context, name string) (int, error) {
+
+	if parsed, err := uuid.Parse(name); err == nil {
+		id, err := rr.db.RecipeIdByUUID(ctx, parsed.String())
+		if errors.Is(err, database.ErrNotFound) {
+			return 0, errors.New("recipe not found")
+		}
+		return id, err
+	}
+
+	if err := rr.load(ctx); err != nil {
+		return 0, err
+	}
+
+	if id, err := models.ParseID(name); err == nil {
+		if !rr.ids[id] {
+			return 0, errors.New("recipe not found")
+		}
+		return id, nil
+	}
+
+	switch ids := rr.slugs[models.Slug(name)]; len(ids) {
+	case 0:
+		return 0, errors.New("recipe not found")
+	case 1:
+		return ids[0], nil
+	default:
+		return 0, fmt.Errorf("name matches %d recipes, use the id instead", len(ids))
+	}
+}
+
+func (rr *recipeResolver) load(ctx context.Context) error {
+
+	if rr.ids != nil {
+		return nil
+	}
+
+	ids, slugs := map[int]bool{}, map[string][]int{}
+
+	err := rr.db.StreamRecipes(ctx, models.RecipeFilter{}, func(recipe models.Recipe) error {
+		slug := models.Slug(recipe.Name)
+		ids[recipe.Id] = true
+		slugs[slug] = append(slugs[slug], recipe.Id)
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	rr.ids, rr.slugs = ids, slugs
+	return nil
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/importer"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/quantity"
+	"net/http"
+	"net/url"
+)
+
+// ImportRecipeHandler adds the recipe published at a URL to the catalog,
+// creating the ingredients and the category it doesn't have yet.
+func (s *Server) ImportRecipeHandler(w http.ResponseWriter, r *http.Request) {
+
+	if s.importer == nil {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Recipe import is not configured"))
+		return
+	}
+
+	var input models.ImportRecipeInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := s.importer.Import(r.Context(), input.Url)
+
+	if err != nil {
+		writeError(w, r, importError(err))
+		return
+	}
+
+	if page.Name == "" {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "The recipe in the page has no name").WithCode("no_recipe"))
+		return
+	}
+
+	if input.CategoryId == 0 && len(page.Categories) == 0 {
+		writeError(w, r, models.ValidationErrors{"category_id": "is required, the page names no category"})
+		return
+	}
+
+	recipe := models.ImportedRecipe{
+		Name:            page.Name,
+		Description:     page.Description,
+		LongDescription: page.LongDescription,
+		CategoryId:      int(input.CategoryId),
+		Categories:      page.Categories,
+	}
+
+	if u, err := url.Parse(page.ImageUrl); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		recipe.Url = page.ImageUrl
+	}
+
+	recipe.Ingredients = make([]models.ImportedIngredient, len(page.Ingredients))
+	texts := []*string{&recipe.Name, &recipe.Description, &recipe.LongDescription}
+
+	for i, ingredient := range page.Ingredients {
+		recipe.Ingredients[i] = models.ImportedIngredient{Name: ingredient.Name, Amount: ingredient.Amount}
+		if amount, ok := quantity.Parse(ingredient.Amount); ok {
+			recipe.Ingredients[i].Quantity = &amount.Quantity
+			recipe.Ingredients[i].Unit = string(amount.Unit)
+		}
+		texts = append(texts, &recipe.Ingredients[i].Name)
+	}
+
+	if !s.filterText(w, r, texts...) {
+		return
+	}
+
+	id, err := s.db.InsertImportedRecipe(r.Context(), recipe)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.RecipesCreated.Inc()
+	metrics.ImportsCompleted.WithLabelValues("url").Inc()
+
+	s.purge(r.Context(), "/recipes")
+
+	writeCreated(w, r, "Recipe", id)
+}
+
+// importError is the error to answer a failed import with: a 422 when the
+// page can't be imported, a 502 when it couldn't be fetched.
+func importError(err error) error {
+	switch {
+	case errors.Is(err, importer.ErrNoRecipe):
+		return httperr.New(http.StatusUnprocessableEntity, "No schema.org recipe found in the page").WithCode("no_recipe")
+	case errors.Is(err, importer.ErrForbiddenAddress):
+		return models.ValidationErrors{"url": "must point to a public address"}
+	default:
+		return httperr.New(http.StatusBadGateway, err.Error())
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// shutdownTimeout bounds how long in-flight requests and background jobs
+// get to finish once the server is asked to stop.
+var shutdownTimeout = envDuration("SHUTDOWN_TIMEOUT", 30*time.Second)
+
+// Run serves the API until ctx is cancelled, typically by SIGINT or SIGTERM.
+// It then stops accepting connections, lets in-flight requests and the
+// background jobs finish within SHUTDOWN_TIMEOUT and closes the database.
+func Run(ctx context.Context) error {
+	s, server := NewServer(ctx)
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		log.Printf("server running at port %s", server.Addr)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.db.Close()
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("shutting down, waiting up to %s for requests to finish", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("requests still running after %s, closing their connections", shutdownTimeout)
+		err = server.Close()
+	}
+
+	jobsDone := make(chan struct{})
+
+	go func() {
+		s.jobs.Wait()
+		close(jobsDone)
+	}()
+
+	select {
+	case <-jobsDone:
+	case <-shutdownCtx.Done():
+		log.Printf("background jobs still running after %s", shutdownTimeout)
+	}
+
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"os"
+	"time"
+)
+
+// linkCheckInterval is how often the stored image URLs are checked. "0"
+// disables the link checker.
+func linkCheckInterval() time.Duration {
+	value := os.Getenv("LINK_CHECK_INTERVAL")
+
+	if value == "" {
+		return 24 * time.Hour
+	}
+
+	interval, err := time.ParseDuration(value)
+
+	if err != nil || interval < 0 {
+		return 24 * time.Hour
+	}
+
+	return interval
+}
+
+// GetBrokenLinksHandler lists the recipe and ingredient image URLs that
+// failed their latest check, for an admin to fix or remove.
+func (s *Server) GetBrokenLinksHandler(w http.ResponseWriter, r *http.Request) {
+
+	links, err := s.db.GetBrokenLinks(r.Context())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewBrokenLinkDtos(links))
+}
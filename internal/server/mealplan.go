@@ -0,0 +1,169 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// GetMealPlanHandler returns the plan of the week given as ?week=2024-W30,
+// the current week by default, day by day.
+func (s *Server) GetMealPlanHandler(w http.ResponseWriter, r *http.Request) {
+
+	week, err := mealPlanWeek(r)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	meals, err := s.db.GetMealPlan(r.Context(), week.Monday(), week.Monday().AddDate(0, 0, 6))
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewMealPlanWeekDto(week, meals))
+}
+
+// PutMealHandler plans a recipe for a meal, replacing the one planned there.
+func (s *Server) PutMealHandler(w http.ResponseWriter, r *http.Request) {
+
+	day, slot, err := mealPath(r)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var input models.MealInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	err = s.db.SetMeal(r.Context(), day, slot, int(input.RecipeId))
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown recipe id").WithCode("unknown_reference"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) DeleteMealHandler(w http.ResponseWriter, r *http.Request) {
+
+	day, slot, err := mealPath(r)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = s.db.DeleteMeal(r.Context(), day, slot)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "No meal planned"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PostMealPlanShoppingListHandler makes a shopping list from the recipes
+// planned for ?week, like POST /shopping-list.
+func (s *Server) PostMealPlanShoppingListHandler(w http.ResponseWriter, r *http.Request) {
+
+	week, err := mealPlanWeek(r)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	meals, err := s.db.GetMealPlan(r.Context(), week.Monday(), week.Monday().AddDate(0, 0, 6))
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if len(meals) == 0 {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "No meals planned for "+week.String()))
+		return
+	}
+
+	recipeIds := make([]int, len(meals))
+	for i, meal := range meals {
+		recipeIds[i] = meal.Recipe.Id
+	}
+
+	id, err := s.db.CreateShoppingList(r.Context(), recipeIds)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.ShoppingListsGenerated.Inc()
+
+	s.writeCreatedShoppingList(w, r, id)
+}
+
+// mealPlanWeek reads ?week, defaulting to the current week in the server's
+// time zone.
+func mealPlanWeek(r *http.Request) (models.ISOWeek, error) {
+
+	param := r.URL.Query().Get("week")
+
+	if param == "" {
+		return models.WeekOf(time.Now()), nil
+	}
+
+	week, err := models.ParseISOWeek(param)
+
+	if err != nil {
+		return week, httperr.New(http.StatusBadRequest, err.Error())
+	}
+
+	return week, nil
+}
+
+// mealPath reads the {date} and {slot} path values.
+func mealPath(r *http.Request) (time.Time, string, error) {
+
+	day, err := time.Parse(time.DateOnly, r.PathValue("date"))
+
+	if err != nil {
+		return day, "", httperr.New(http.StatusBadRequest, "date must be formatted as YYYY-MM-DD")
+	}
+
+	slot := r.PathValue("slot")
+
+	if !slices.Contains(models.MealSlots, slot) {
+		return day, "", httperr.New(http.StatusNotFound, "Unknown meal slot")
+	}
+
+	return day, slot, nil
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"crypto/subtle"
+	"gastro-galaxy-back/internal/httperr"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var adminToken = os.Getenv("ADMIN_TOKEN")
+
+// adminOnly rejects requests that don't carry the ADMIN_TOKEN as a bearer token.
+// When ADMIN_TOKEN is not set every admin request is rejected.
+func (s *Server) adminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if !ok || adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			writeError(w, r, httperr.New(http.StatusUnauthorized, "Unauthorized"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"net/http"
+)
+
+// GetRecipeNutritionHandler returns the nutrition of a recipe, added up
+// over the ingredients that have a quantity and nutrition facts.
+func (s *Server) GetRecipeNutritionHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	nutrition, err := s.recipes.Nutrition(r.Context(), recipeId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(nutrition)
+}
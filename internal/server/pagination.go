@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+const (
+	defaultPageSize    = 20
+	defaultMaxPageSize = 100
+)
+
+// maxPageSize is configurable with MAX_PAGE_SIZE but never exceeds the
+// global row cap.
+var maxPageSize = min(envInt("MAX_PAGE_SIZE", defaultMaxPageSize), database.MaxListRows)
+
+type pageRequest struct {
+	Page     int
+	PageSize int
+}
+
+func (p pageRequest) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// parsePage reads ?page= and ?pageSize= (or ?page_size=). ok is false when
+// the client didn't ask for pagination at all.
+func parsePage(r *http.Request) (page pageRequest, ok bool, err error) {
+	query := r.URL.Query()
+
+	pageParam := query.Get("page")
+	sizeParam := query.Get("pageSize")
+	if sizeParam == "" {
+		sizeParam = query.Get("page_size")
+	}
+
+	if pageParam == "" && sizeParam == "" {
+		return pageRequest{}, false, nil
+	}
+
+	page = pageRequest{Page: 1, PageSize: defaultPageSize}
+
+	if pageParam != "" {
+		page.Page, err = strconv.Atoi(pageParam)
+		if err != nil || page.Page < 1 {
+			return page, true, fmt.Errorf("page must be a positive integer")
+		}
+	}
+
+	if sizeParam != "" {
+		page.PageSize, err = strconv.Atoi(sizeParam)
+		if err != nil || page.PageSize < 1 {
+			return page, true, fmt.Errorf("pageSize must be a positive integer")
+		}
+	}
+
+	if page.PageSize > maxPageSize {
+		return page, true, fmt.Errorf("pageSize must not exceed %d, request further pages with ?page=", maxPageSize)
+	}
+
+	return page, true, nil
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"fmt"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/imaging"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultPlaceholderSize = 256
+	minPlaceholderSize     = 32
+	maxPlaceholderSize     = 1024
+
+	// maxCachedPlaceholders bounds the memory the placeholder cache can use.
+	maxCachedPlaceholders = 1024
+)
+
+// placeholderCache keeps rendered placeholders so each one is only drawn
+// once. When it is full it is emptied and starts over.
+type placeholderCache struct {
+	mu     sync.Mutex
+	images map[string][]byte
+}
+
+func (c *placeholderCache) get(name string, size int) ([]byte, error) {
+	key := fmt.Sprintf("%d/%s", size, name)
+
+	c.mu.Lock()
+	png, ok := c.images[key]
+	c.mu.Unlock()
+
+	if ok {
+		return png, nil
+	}
+
+	png, err := imaging.Placeholder(name, size)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.images == nil || len(c.images) >= maxCachedPlaceholders {
+		c.images = make(map[string][]byte)
+	}
+	c.images[key] = png
+	c.mu.Unlock()
+
+	return png, nil
+}
+
+var placeholders placeholderCache
+
+// GetPlaceholderImageHandler serves the image shown for ingredients without a
+// photo: the first letter of the name on a color derived from it.
+func (s *Server) GetPlaceholderImageHandler(w http.ResponseWriter, r *http.Request) {
+
+	name := strings.TrimSuffix(chi.URLParam(r, "name"), ".png")
+
+	if strings.TrimSpace(name) == "" {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "name is required"))
+		return
+	}
+
+	size := defaultPlaceholderSize
+
+	if param := r.URL.Query().Get("size"); param != "" {
+		var err error
+		size, err = strconv.Atoi(param)
+
+		if err != nil || size < minPlaceholderSize || size > maxPlaceholderSize {
+			writeError(w, r, httperr.New(http.StatusBadRequest, fmt.Sprintf("size must be between %d and %d", minPlaceholderSize, maxPlaceholderSize)))
+			return
+		}
+	}
+
+	png, err := placeholders.get(name, size)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kitchenMode enables the features for professional kitchens, such as the
+// prep log.
+var kitchenMode = os.Getenv("KITCHEN_MODE") == "true"
+
+// prepLogRetention is how long prep logs are kept, two years by default.
+var prepLogRetention = time.Duration(envInt("PREP_LOG_RETENTION_DAYS", 730)) * 24 * time.Hour
+
+const defaultPrepLogDays = 30
+
+// PostPrepLogHandler logs a batch prepared from a recipe.
+func (s *Server) PostPrepLogHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.PrepLogInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	now := time.Now()
+
+	if err := input.Validate(now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	preparedAt := now
+
+	if input.PreparedAt != nil {
+		preparedAt = *input.PreparedAt
+	}
+
+	id, err := s.db.InsertPrepLog(r.Context(), models.PrepLog{
+		RecipeId:     &recipeId,
+		PreparedBy:   strings.TrimSpace(input.PreparedBy),
+		PreparedAt:   preparedAt,
+		BatchSize:    input.BatchSize,
+		BatchUnit:    strings.TrimSpace(input.BatchUnit),
+		Temperatures: input.Temperatures,
+		Notes:        input.Notes,
+	})
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeCreated(w, r, "Prep log", id)
+}
+
+// GetRecipePrepLogsHandler lists the batches of one recipe. See
+// writePrepLogs for the parameters.
+func (s *Server) GetRecipePrepLogsHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	s.writePrepLogs(w, r, &recipeId)
+}
+
+// GetPrepLogsHandler lists the batches of every recipe. See writePrepLogs
+// for the parameters.
+func (s *Server) GetPrepLogsHandler(w http.ResponseWriter, r *http.Request) {
+	s.writePrepLogs(w, r, nil)
+}
+
+// writePrepLogs answers with the batches made between ?from= and ?to=
+// (YYYY-MM-DD or RFC 3339, the last 30 days by default), as JSON or, with
+// ?format=csv, as a CSV download for auditors.
+func (s *Server) writePrepLogs(w http.ResponseWriter, r *http.Request, recipeId *int) {
+
+	to, err := timeParam(r, "to", time.Now())
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	from, err := timeParam(r, "from", to.AddDate(0, 0, -defaultPrepLogDays))
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	logs, err := s.db.GetPrepLogs(r.Context(), recipeId, from, to)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if r.URL.Query().Get("format") != "csv" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(models.NewPrepLogDtos(logs))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="prep-log-%s-%s.csv"`, from.Format(time.DateOnly), to.Format(time.DateOnly)))
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"id", "recipe_id", "recipe_name", "prepared_at", "prepared_by", "batch_size", "batch_unit", "temperatures", "notes"})
+
+	for _, entry := range logs {
+		readings := make([]string, len(entry.Temperatures))
+		for i, reading := range entry.Temperatures {
+			readings[i] = fmt.Sprintf("%s=%s°C", reading.Stage, strconv.FormatFloat(reading.Celsius, 'f', -1, 64))
+		}
+
+		recipeId := ""
+		if entry.RecipeId != nil {
+			recipeId = strconv.Itoa(*entry.RecipeId)
+		}
+
+		out.Write([]string{
+			strconv.Itoa(entry.Id),
+			recipeId,
+			entry.RecipeName,
+			entry.PreparedAt.Format(time.RFC3339),
+			entry.PreparedBy,
+			strconv.FormatFloat(entry.BatchSize, 'f', -1, 64),
+			entry.BatchUnit,
+			strings.Join(readings, "; "),
+			entry.Notes,
+		})
+	}
+
+	out.Flush()
+}
+
+// timeParam reads a YYYY-MM-DD or RFC 3339 time from the query, fallback
+// when it is absent.
+func timeParam(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	param := r.URL.Query().Get(name)
+
+	if param == "" {
+		return fallback, nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, param, time.Local); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, param)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be formatted as YYYY-MM-DD or RFC 3339", name)
+	}
+
+	return t, nil
+}
+
+// prunePrepLogs deletes the prep logs older than the retention period once a
+// day, until ctx is cancelled.
+func (s *Server) prunePrepLogs(ctx context.Context) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.db.DeletePrepLogsBefore(ctx, time.Now().Add(-prepLogRetention))
+
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot delete expired prep logs: %v", err)
+		} else if deleted > 0 {
+			log.Printf("deleted %d expired prep logs", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"fmt"
+	"gastro-galaxy-back/internal/httperr"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// publicBaseURL is the base URL of the public frontend the QR codes point to.
+var publicBaseURL = strings.TrimSuffix(os.Getenv("PUBLIC_BASE_URL"), "/")
+
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 1024
+)
+
+var qrRecoveryLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+func (s *Server) GetRecipeQRCodeHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	size := defaultQRSize
+
+	if param := r.URL.Query().Get("size"); param != "" {
+		size, err = strconv.Atoi(param)
+
+		if err != nil || size < minQRSize || size > maxQRSize {
+			writeError(w, r, httperr.New(http.StatusBadRequest, fmt.Sprintf("size must be between %d and %d", minQRSize, maxQRSize)))
+			return
+		}
+	}
+
+	level := qrcode.Medium
+
+	if param := r.URL.Query().Get("level"); param != "" {
+		var ok bool
+		level, ok = qrRecoveryLevels[strings.ToUpper(param)]
+
+		if !ok {
+			writeError(w, r, httperr.New(http.StatusBadRequest, "level must be one of L, M, Q, H"))
+			return
+		}
+	}
+
+	recipe, err := s.db.GetRecipeWithIngredients(r.Context(), recipeId)
+
+	if err != nil || recipe == nil {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	png, err := qrcode.Encode(fmt.Sprintf("%s/recipe/%s", publicBaseURL, recipe.Recipe.Uuid), level, size)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
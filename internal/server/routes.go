@@ -1,13 +1,21 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/jsonstream"
+	"gastro-galaxy-back/internal/metrics"
 	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/requestid"
 	"io"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -15,25 +23,203 @@ import (
 
 func (s *Server) RegisterRoutes() http.Handler {
 	r := chi.NewRouter()
+	r.Use(requestid.Middleware)
 	r.Use(middleware.Logger)
+	r.Use(metrics.Middleware)
+	r.Use(s.analytics.Middleware)
+	r.Use(s.shadow.Middleware)
+	r.Use(idFormat)
 
+	r.Handle("/metrics", metrics.Handler())
+
+	r.Get("/openapi.json", s.OpenAPIHandler)
+
+	r.Get("/docs", s.DocsHandler)
+
+	r.Group(func(r chi.Router) {
+		if compatMode {
+			r.Use(legacyContract)
+		}
+		s.registerAPI(r)
+	})
+
+	r.Route("/v1", s.registerAPI)
+
+	return r
+}
+
+// registerAPI adds the API endpoints to r. They are served both at the root
+// and under /v1.
+func (s *Server) registerAPI(r chi.Router) {
 	r.Get("/", s.HelloWorldHandler)
 
 	r.Get("/health", s.HealthHandler)
 
+	r.Get("/health/live", s.LivenessHandler)
+
+	r.Get("/health/ready", s.ReadinessHandler)
+
+	r.Get("/stats/public", s.GetPublicStatsHandler)
+
+	r.Get("/home", s.GetHomeHandler)
+
 	r.Get("/recipes", s.GetRecipesHandler)
 
+	r.Get("/recipes/search", s.SearchRecipesHandler)
+
+	r.Get("/recipes/daily", s.GetDailyRecipeHandler)
+
+	r.Get("/recipes/cookable", s.GetCookableRecipesHandler)
+
+	r.Post("/recipes/cookable", s.PostCookableRecipesHandler)
+
 	r.Get("/recipe/{recipeId}", s.GetRecipeWithIngredientsHandler)
 
 	r.Put("/recipe/{recipeId}", s.PutRecipeHandler)
 
+	r.Delete("/recipe/{recipeId}", s.DeleteRecipeHandler)
+
+	r.Put("/recipe/{recipeId}/ingredients", s.PutRecipeIngredientsHandler)
+
+	r.Post("/recipe/{recipeId}/steps", s.PostRecipeStepHandler)
+
+	r.Put("/recipe/{recipeId}/steps", s.PutRecipeStepsHandler)
+
+	r.Put("/recipe/{recipeId}/steps/{stepId}", s.PutRecipeStepHandler)
+
+	r.Delete("/recipe/{recipeId}/steps/{stepId}", s.DeleteRecipeStepHandler)
+
+	r.Post("/recipe/{recipeId}/tags", s.PostRecipeTagsHandler)
+
+	r.Delete("/recipe/{recipeId}/tags/{tag}", s.DeleteRecipeTagHandler)
+
+	r.Get("/tags", s.GetTagsHandler)
+
+	r.Get("/recipe/{recipeId}/qr.png", s.GetRecipeQRCodeHandler)
+
+	r.Get("/recipe/{recipeId}/nutrition", s.GetRecipeNutritionHandler)
+
+	r.Get("/recipe/{recipeId}/scale/{factor}", s.GetScaledRecipeHandler)
+
+	r.Put("/recipe/{recipeId}/custom-fields", s.PutRecipeCustomFieldsHandler)
+
+	r.Get("/custom-fields", s.GetCustomFieldsHandler)
+
 	r.Post("/recipe", s.InsertRecipeHandler)
 
+	r.Post("/recipe/import", s.ImportRecipeHandler)
+
 	r.Post("/ingredient", s.InsertIngredientHandler)
 
+	r.Put("/ingredient/{ingredientId}", s.PutIngredientHandler)
+
+	r.Delete("/ingredient/{ingredientId}", s.DeleteIngredientHandler)
+
+	r.Patch("/ingredient/{ingredientId}/availability", s.PatchIngredientAvailabilityHandler)
+
+	r.Put("/ingredient/{ingredientId}/base", s.PutIngredientBaseHandler)
+
+	r.Get("/ingredient/{ingredientId}/substitutes", s.GetIngredientSubstitutesHandler)
+
+	r.Get("/ingredient/{ingredientId}/recipes", s.GetIngredientRecipesHandler)
+
+	r.Patch("/ingredients/availability", s.PatchIngredientsAvailabilityHandler)
+
 	r.Get("/ingredients", s.GetIngredientsHandler)
 
-	return r
+	r.Post("/category", s.InsertCategoryHandler)
+
+	r.Get("/categories", s.GetCategoriesHandler)
+
+	r.Put("/category/{categoryId}", s.PutCategoryHandler)
+
+	r.Delete("/category/{categoryId}", s.DeleteCategoryHandler)
+
+	r.Get("/images/placeholder/{name}", s.GetPlaceholderImageHandler)
+
+	r.Post("/upload", s.UploadHandler)
+
+	r.Post("/uploads/presign", s.PresignUploadHandler)
+
+	r.Post("/uploads/confirm", s.ConfirmUploadHandler)
+
+	r.Post("/slack/commands", s.SlackCommandHandler)
+
+	r.Post("/analytics/pageview", s.PostPageViewHandler)
+
+	r.Post("/suggestions", s.PostSuggestionHandler)
+
+	r.Post("/shopping-list", s.PostShoppingListHandler)
+
+	r.Get("/shopping-list/{listId}", s.GetShoppingListHandler)
+
+	r.Delete("/shopping-list/{listId}", s.DeleteShoppingListHandler)
+
+	r.Patch("/shopping-list/{listId}/item/{itemId}", s.PatchShoppingListItemHandler)
+
+	r.Get("/meal-plan", s.GetMealPlanHandler)
+
+	r.Put("/meal-plan/{date}/{slot}", s.PutMealHandler)
+
+	r.Delete("/meal-plan/{date}/{slot}", s.DeleteMealHandler)
+
+	r.Post("/meal-plan/shopping-list", s.PostMealPlanShoppingListHandler)
+
+	r.Group(func(r chi.Router) {
+		r.Use(s.adminOnly)
+
+		r.Get("/export/recipes", s.ExportRecipesHandler)
+
+		r.Post("/import/recipes", s.ImportRecipesHandler)
+
+		r.Post("/import/images", s.ImportImagesHandler)
+
+		r.Get("/import/images/{importId}", s.GetImageImportHandler)
+	})
+
+	if kitchenMode {
+		r.Group(func(r chi.Router) {
+			r.Use(s.adminOnly)
+
+			r.Post("/recipe/{recipeId}/prep-logs", s.PostPrepLogHandler)
+
+			r.Get("/recipe/{recipeId}/prep-logs", s.GetRecipePrepLogsHandler)
+
+			r.Get("/prep-logs", s.GetPrepLogsHandler)
+		})
+	}
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(s.adminOnly)
+
+		r.Get("/config", s.GetConfigHandler)
+
+		r.Get("/banned-words", s.GetBannedWordsHandler)
+
+		r.Post("/banned-words", s.InsertBannedWordsHandler)
+
+		r.Delete("/banned-words/{word}", s.DeleteBannedWordHandler)
+
+		r.Put("/daily-recipe", s.PinDailyRecipeHandler)
+
+		r.Put("/home/{slot}", s.PutHomeSlotHandler)
+
+		r.Get("/broken-links", s.GetBrokenLinksHandler)
+
+		r.Get("/analytics/{kind}", s.GetAnalyticsHandler)
+
+		r.Get("/suggestions", s.GetSuggestionsHandler)
+
+		r.Patch("/suggestions/{suggestionId}", s.PatchSuggestionHandler)
+
+		r.Get("/tag-suggestions", s.GetTagSuggestionsHandler)
+
+		r.Patch("/tag-suggestions/{suggestionId}", s.PatchTagSuggestionHandler)
+
+		r.Put("/custom-fields/{name}", s.PutCustomFieldHandler)
+
+		r.Delete("/custom-fields/{name}", s.DeleteCustomFieldHandler)
+	})
 }
 
 func (s *Server) HelloWorldHandler(w http.ResponseWriter, r *http.Request) {
@@ -48,9 +234,67 @@ func (s *Server) HelloWorldHandler(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(jsonResp)
 }
 
+// HealthHandler reports the database pool and, under checks, the status and
+// latency of the database and of every integration configured. Only a
+// database down fails it; an integration down makes it degraded.
 func (s *Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
-	jsonResp, _ := json.Marshal(s.db.Health())
-	_, _ = w.Write(jsonResp)
+	var health map[string]string
+
+	dbCheck := healthCheck{"database", func(ctx context.Context) error {
+		health = s.db.Health(ctx)
+		if health["status"] == "down" {
+			return errors.New(health["error"])
+		}
+		return nil
+	}}
+
+	checks := runHealthChecks(r.Context(), append([]healthCheck{dbCheck}, s.integrationChecks()...))
+
+	body := map[string]any{"checks": checks}
+	for key, value := range health {
+		body[key] = value
+	}
+
+	status := http.StatusOK
+	if health["status"] == "down" {
+		status = http.StatusServiceUnavailable
+	} else {
+		for _, check := range checks {
+			if check.Status == "down" {
+				body["status"] = "degraded"
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// LivenessHandler tells the orchestrator the process is running and able to
+// answer. It doesn't look at the database: restarting the server would not
+// bring a failed database back.
+func (s *Server) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "up"})
+}
+
+// ReadinessHandler tells the orchestrator whether to send traffic to this
+// instance: only while the database is reachable.
+func (s *Server) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	health := s.db.Health(r.Context())
+
+	if health["status"] == "down" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"status": "down", "error": health["error"]})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": health["status"]})
 }
 
 func (s *Server) InsertRecipeHandler(w http.ResponseWriter, r *http.Request) {
@@ -58,81 +302,162 @@ func (s *Server) InsertRecipeHandler(w http.ResponseWriter, r *http.Request) {
 	var recipeDto models.RecipeInputDto
 
 	if err := json.NewDecoder(r.Body).Decode(&recipeDto); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
 		return
 	}
 
-	recipe := models.Recipe{
-		Name:            recipeDto.Name,
-		Url:             recipeDto.Url,
-		CategoryId:      recipeDto.CategoryId,
-		Description:     recipeDto.Description,
-		LongDescription: recipeDto.LongDescription,
-	}
-
-	id, err := s.db.InsertRecipe(recipe.Name, recipe.Description, recipe.LongDescription, recipe.Url, recipe.CategoryId, recipeDto.IngedientIds)
+	id, err := s.recipes.Create(r.Context(), recipeDto)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	fmt.Fprintf(w, "Recipe id: %d", id)
+	metrics.RecipesCreated.Inc()
+
+	s.purge(r.Context(), "/recipes")
+
+	writeCreated(w, r, "Recipe", id)
 }
 
 func (s *Server) GetRecipesHandler(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
+
+	filter, err := recipeFilter(r)
+
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
 		return
 	}
-	defer r.Body.Close()
 
-	var data map[string]interface{}
+	page, paginated, err := parsePage(r)
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
 
-	var category string
+	if paginated {
+		recipes, err := s.recipes.Page(r.Context(), filter, page.Page, page.PageSize)
 
-	if len(body) > 0 {
-		if err := json.Unmarshal(body, &data); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		category = data["category"].(string)
-	} else {
-		category = ""
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(recipes)
+		return
 	}
 
-	recipes, err := s.db.GetRecipes(category)
+	w.Header().Set("Content-Type", "application/json")
+
+	stream := jsonstream.NewArrayWriter(w)
+
+	err = s.recipes.Stream(r.Context(), filter, func(recipe models.RecipeDto) error {
+		return stream.Write(recipe)
+	})
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		streamError(w, r, stream, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(recipes)
+
+	stream.Close()
+}
+
+// recipeFilter reads the filter of a recipe list: the categories, see
+// recipeCategories, and the tags of ?tags=, which may be repeated or hold a
+// comma separated list. Recipes must carry all of the tags, or any of them
+// with ?tag_match=any.
+func recipeFilter(r *http.Request) (models.RecipeFilter, error) {
+	categories, err := recipeCategories(r)
+
+	if err != nil {
+		return models.RecipeFilter{}, err
+	}
+
+	filter := models.RecipeFilter{Categories: categories}
+
+	for _, param := range r.URL.Query()["tags"] {
+		filter.Tags = append(filter.Tags, strings.Split(param, ",")...)
+	}
+
+	filter.Tags = models.NormalizeTags(filter.Tags)
+
+	switch match := r.URL.Query().Get("tag_match"); match {
+	case "", "all":
+	case "any":
+		filter.AnyTag = true
+	default:
+		return models.RecipeFilter{}, fmt.Errorf("tag_match must be all or any, got %q", match)
+	}
+
+	return filter, nil
+}
+
+// recipeCategories reads the category filter from ?category=, which may be
+// repeated or hold a comma separated list. Legacy clients that still send
+// {"category": "..."} as a GET body keep working until they move to the
+// query parameter.
+func recipeCategories(r *http.Request) ([]string, error) {
+	var categories []string
+
+	for _, param := range r.URL.Query()["category"] {
+		for _, category := range strings.Split(param, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	if len(categories) > 0 || !isLegacy(r) {
+		return categories, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	var data struct {
+		Category string `json:"category"`
+	}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Category == "" {
+		return nil, nil
+	}
+
+	return []string{data.Category}, nil
 }
 
 func (s *Server) GetRecipeWithIngredientsHandler(w http.ResponseWriter, r *http.Request) {
 
-	recipeId, err := strconv.Atoi(r.PathValue("recipeId"))
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, pathIDError(err))
 		return
 	}
 
-	recipe, err := s.db.GetRecipeWithIngredients(recipeId)
+	recipe, err := s.recipes.Get(r.Context(), recipeId)
 
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
 		return
 	}
 
-	if recipe == nil {
-		http.Error(w, "Recipe not found", http.StatusNotFound)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
@@ -144,83 +469,285 @@ func (s *Server) GetRecipeWithIngredientsHandler(w http.ResponseWriter, r *http.
 
 func (s *Server) PutRecipeHandler(w http.ResponseWriter, r *http.Request) {
 
-	recipeId, err := strconv.Atoi(r.PathValue("recipeId"))
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var recipeDto models.RecipeInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&recipeDto); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
 		return
 	}
 
-	recipe, err := s.db.GetRecipeWithIngredients(recipeId)
+	err = s.recipes.Update(r.Context(), recipeId, recipeDto)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown ingredient id"))
+		return
+	}
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	if recipe == nil {
-		http.Error(w, "Recipe not found", http.StatusNotFound)
+	s.purgeRecipe(r.Context(), recipeId)
+
+	writeUpdated(w, r, http.StatusCreated, "Recipe UPDATED")
+}
+
+// PutRecipeIngredientsHandler replaces the ingredient list of a recipe and
+// answers with the updated recipe.
+func (s *Server) PutRecipeIngredientsHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
 		return
 	}
 
-	var recipeDto models.RecipeInputDto
+	var input models.RecipeIngredientsInputDto
 
-	if err := json.NewDecoder(r.Body).Decode(&recipeDto); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
 		return
 	}
 
-	updatedRecipe := models.Recipe{
-		Name:        recipeDto.Name,
-		Url:         recipeDto.Url,
-		Description: recipeDto.Description,
+	recipe, err := s.recipes.ReplaceIngredients(r.Context(), recipeId, input.IngredientIds)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown ingredient id"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	if err := s.db.UpdateRecipe(recipeId, updatedRecipe.Name, updatedRecipe.Description, updatedRecipe.Url); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	s.purgeRecipe(r.Context(), recipeId)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(recipe)
+}
+
+func (s *Server) DeleteRecipeHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
 		return
 	}
 
-	if err := s.db.InsertRecipeIngredient(recipeId, recipeDto.IngedientIds); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	// The uuid is gone with the recipe, so look it up for the purge first.
+	recipeUUID, _ := s.db.RecipeUUIDById(r.Context(), recipeId)
+
+	err = s.recipes.Delete(r.Context(), recipeId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
 		return
+	}
 
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	fmt.Fprintf(w, "Recipe UPDATED")
+	s.purge(r.Context(), recipePaths(recipeId, recipeUUID)...)
 
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (s *Server) InsertIngredientHandler(w http.ResponseWriter, r *http.Request) {
 
-	var ingredient models.Ingedient
+	var ingredient models.IngredientInputDto
 
 	err := json.NewDecoder(r.Body).Decode(&ingredient)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	id, err := s.ingredients.Create(r.Context(), ingredient)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.IngredientsCreated.Inc()
+
+	s.purge(r.Context(), "/ingredients")
+
+	writeCreated(w, r, "Ingredient", id)
+}
+
+func (s *Server) PutIngredientHandler(w http.ResponseWriter, r *http.Request) {
+
+	ingredientId, err := pathID(r, "ingredientId", s.db.IngredientIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var ingredient models.IngredientInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&ingredient); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	err = s.ingredients.Update(r.Context(), ingredientId, ingredient)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Ingredient not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/ingredients")
+
+	writeUpdated(w, r, http.StatusOK, "Ingredient UPDATED")
+}
+
+// DeleteIngredientHandler refuses to delete ingredients used by recipes
+// with 409, unless ?cascade=true asks to remove them from those recipes too.
+func (s *Server) DeleteIngredientHandler(w http.ResponseWriter, r *http.Request) {
+
+	ingredientId, err := pathID(r, "ingredientId", s.db.IngredientIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	cascade := r.URL.Query().Get("cascade") == "true"
+
+	err = s.ingredients.Delete(r.Context(), ingredientId, cascade)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Ingredient not found"))
+		return
 	}
 
-	id, err := s.db.InsertIngredient(ingredient.Name, ingredient.Amount, ingredient.Url, ingredient.IsAvailable)
+	if errors.Is(err, database.ErrInUse) {
+		writeError(w, r, httperr.New(http.StatusConflict, "Ingredient is used by recipes, retry with ?cascade=true to remove it from them"))
+		return
+	}
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	fmt.Fprintf(w, "Ingredient id: %d", id)
+	s.purge(r.Context(), "/ingredients")
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (s *Server) GetIngredientsHandler(w http.ResponseWriter, r *http.Request) {
 
-	ingredients, err := s.db.GetIngredients()
+	available, err := ingredientAvailability(r)
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	page, paginated, err := parsePage(r)
 
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if paginated {
+		ingredients, err := s.ingredients.Page(r.Context(), available, page.Page, page.PageSize)
+
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ingredients)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ingredients)
+
+	stream := jsonstream.NewArrayWriter(w)
+
+	err = s.ingredients.Stream(r.Context(), available, func(ingredient models.IngredientDto) error {
+		return stream.Write(ingredient)
+	})
+
+	if err != nil {
+		streamError(w, r, stream, err)
+		return
+	}
+
+	stream.Close()
+}
+
+// ingredientAvailability reads the optional ?available= filter. nil means
+// every ingredient.
+func ingredientAvailability(r *http.Request) (*bool, error) {
+	param := r.URL.Query().Get("available")
+
+	if param == "" {
+		return nil, nil
+	}
+
+	available, err := strconv.ParseBool(param)
+
+	if err != nil {
+		return nil, fmt.Errorf("available must be true or false")
+	}
+
+	return &available, nil
+}
+
+// streamError reports a failure of a streamed response. Once the first
+// element is out the status can't change anymore, so the response is left
+// truncated (invalid JSON) and the error is only logged.
+func streamError(w http.ResponseWriter, r *http.Request, stream *jsonstream.ArrayWriter, err error) {
+	var tooMany *database.TooManyRowsError
+
+	if errors.As(err, &tooMany) {
+		writeError(w, r, httperr.New(http.StatusBadRequest, fmt.Sprintf("The result has more than %d rows. Narrow it down with a filter (e.g. a category) or paginate with ?page=&pageSize=.", tooMany.Limit)))
+		return
+	}
+
+	if !stream.Started() {
+		writeError(w, r, err)
+		return
+	}
+
+	log.Printf("streaming response failed: %v", err)
 }
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/quantity"
+	"gastro-galaxy-back/internal/service"
+	"net/http"
+)
+
+// GetScaledRecipeHandler returns a recipe with its ingredient quantities
+// multiplied by the factor in the path, e.g. 2 or 0.5.
+func (s *Server) GetScaledRecipeHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	factor, ok := quantity.ParseNumber(r.PathValue("factor"))
+
+	if !ok {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "factor must be a number such as 2 or 0.5"))
+		return
+	}
+
+	recipe, err := s.recipes.Scale(r.Context(), recipeId, factor)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidFactor) {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(recipe)
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/analytics"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"strings"
+)
+
+func (s *Server) SearchRecipesHandler(w http.ResponseWriter, r *http.Request) {
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+
+	if query == "" {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "q is required"))
+		return
+	}
+
+	page, paginated, err := parsePage(r)
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if !paginated {
+		page = pageRequest{Page: 1, PageSize: defaultPageSize}
+	}
+
+	results, err := s.db.SearchRecipes(r.Context(), query, page.PageSize, page.Offset())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.Searches.WithLabelValues("fulltext").Inc()
+
+	if term, ok := analytics.ScrubSearchTerm(query); ok {
+		s.analytics.Record(r, models.AnalyticsKindSearch, term)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewRecipeSearchResultDtos(results))
+}
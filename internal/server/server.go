@@ -1,31 +1,180 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	_ "github.com/joho/godotenv/autoload"
 
+	"gastro-galaxy-back/internal/analytics"
+	"gastro-galaxy-back/internal/cdn"
+	"gastro-galaxy-back/internal/classifier"
 	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/memory"
+	"gastro-galaxy-back/internal/importer"
+	"gastro-galaxy-back/internal/linkcheck"
+	"gastro-galaxy-back/internal/service"
+	"gastro-galaxy-back/internal/shadow"
+	"gastro-galaxy-back/internal/storage"
+	"gastro-galaxy-back/internal/telegram"
+	"gastro-galaxy-back/internal/wordfilter"
 )
 
 type Server struct {
 	port int
 
 	db database.Service
+
+	recipes *service.RecipeService
+
+	ingredients *service.IngredientService
+
+	categories *service.CategoryService
+
+	customFields *service.CustomFieldService
+
+	filter *wordfilter.Filter
+
+	storage storage.Storage
+
+	cdn cdn.Purger
+
+	publicStats publicStatsCache
+
+	analytics *analytics.Sink
+
+	importer *importer.Importer
+
+	shadow *shadow.Mirror
+
+	// slackSecret is SLACK_SIGNING_SECRET, the Slack integration is off
+	// without it.
+	slackSecret string
+
+	// imageImports queues the archives sent to POST /import/images.
+	imageImports chan imageImport
+
+	// jobs tracks the background goroutines, which stop when the context
+	// given to NewServer is cancelled.
+	jobs sync.WaitGroup
+}
+
+// openDatabase returns the database.Service DB_DRIVER asks for: "postgres",
+// the default, or "memory" for local development and tests without
+// Postgres.
+func openDatabase() database.Service {
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "postgres":
+		return database.New()
+	case "memory":
+		log.Printf("using the in-memory database, nothing is kept across restarts")
+		return memory.New()
+	default:
+		log.Fatalf("unknown DB_DRIVER %q, want postgres or memory", driver)
+		return nil
+	}
 }
 
-func NewServer() *http.Server {
+// NewServer sets up the API server. Its background jobs run until ctx is
+// cancelled.
+func NewServer(ctx context.Context) (*Server, *http.Server) {
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
 	NewServer := &Server{
 		port: port,
 
-		db: database.New(),
+		db: openDatabase(),
+
+		filter: wordfilter.New(nil),
+
+		importer: importer.New(),
+
+		slackSecret: os.Getenv("SLACK_SIGNING_SECRET"),
+	}
+
+	NewServer.recipes = service.NewRecipeService(NewServer.db, NewServer.checkText)
+	NewServer.ingredients = service.NewIngredientService(NewServer.db, NewServer.checkText)
+	NewServer.categories = service.NewCategoryService(NewServer.db, NewServer.checkText)
+	NewServer.customFields = service.NewCustomFieldService(NewServer.db)
+
+	if os.Getenv("MIGRATE_ON_START") != "false" {
+		if err := NewServer.db.Migrate(ctx); err != nil {
+			log.Fatalf("cannot migrate database: %v", err)
+		}
+	}
+
+	// Refuse to serve a schema this build doesn't understand, e.g. when an
+	// old instance restarts after a newer one ran a breaking migration.
+	if err := NewServer.db.CheckSchema(ctx); err != nil {
+		log.Fatalf("refusing to start: %v", err)
 	}
 
+	if missing, err := NewServer.db.MissingIndexes(ctx); err != nil {
+		log.Printf("cannot check database indexes: %v", err)
+	} else if len(missing) > 0 {
+		log.Printf("warning: missing database indexes %v, run the migrations", missing)
+	}
+
+	if demoMode {
+		if err := NewServer.loadDemo(ctx); err != nil {
+			log.Printf("cannot load the demo catalog: %v", err)
+		}
+	}
+
+	if err := NewServer.reloadBannedWords(ctx); err != nil {
+		log.Printf("cannot load banned words: %v", err)
+	}
+
+	if store, err := storage.New(); err != nil {
+		log.Printf("uploads disabled: %v", err)
+	} else {
+		NewServer.storage = store
+		NewServer.imageImports = make(chan imageImport, imageImportQueueSize)
+		NewServer.background(NewServer.runImageImports, ctx)
+	}
+
+	if purger, err := cdn.New(ctx); err != nil {
+		log.Printf("cdn purging disabled: %v", err)
+	} else {
+		NewServer.cdn = purger
+	}
+
+	if target := os.Getenv("SHADOW_URL"); target != "" {
+		if mirror, err := shadow.New(target, envInt("SHADOW_PERCENT", 10)); err != nil {
+			log.Printf("traffic shadowing disabled: %v", err)
+		} else {
+			NewServer.shadow = mirror
+		}
+	}
+
+	if token := os.Getenv("TELEGRAM_BOT_TOKEN"); token != "" {
+		NewServer.background(telegram.New(token, publicBaseURL, NewServer.db).Run, ctx)
+	}
+
+	if analyticsEnabled {
+		NewServer.analytics = analytics.New(NewServer.db, analyticsRetention)
+		NewServer.background(NewServer.analytics.Run, ctx)
+	}
+
+	if kitchenMode {
+		NewServer.background(NewServer.prunePrepLogs, ctx)
+	}
+
+	if interval := linkCheckInterval(); interval > 0 {
+		NewServer.background(linkcheck.New(NewServer.db, interval).Run, ctx)
+	}
+
+	if interval := classifyInterval(); interval > 0 {
+		NewServer.background(classifier.New(NewServer.db, interval).Run, ctx)
+	}
+
+	NewServer.logConfig()
+
 	// Declare Server config
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", NewServer.port),
@@ -35,5 +184,14 @@ func NewServer() *http.Server {
 		WriteTimeout: 30 * time.Second,
 	}
 
-	return server
+	return NewServer, server
+}
+
+// background runs job in a goroutine tracked by s.jobs.
+func (s *Server) background(job func(context.Context), ctx context.Context) {
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		job(ctx)
+	}()
 }
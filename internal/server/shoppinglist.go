@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"io"
+	"net/http"
+)
+
+// PostShoppingListHandler makes a shopping list from recipe_ids: the
+// ingredients the recipes need, each listed once, minus the ones marked
+// available. Lists are not tied to an account, so the uuid in the answer is
+// what lets the caller, and whoever they share it with, reach the list.
+func (s *Server) PostShoppingListHandler(w http.ResponseWriter, r *http.Request) {
+
+	var input models.ShoppingListInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := s.db.CreateShoppingList(r.Context(), input.RecipeIds)
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown recipe ids").WithCode("unknown_reference"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.ShoppingListsGenerated.Inc()
+
+	s.writeCreatedShoppingList(w, r, id)
+}
+
+// writeCreatedShoppingList answers the creation of a list with the whole
+// list, uuid included, since the uuid is the only way to address it.
+func (s *Server) writeCreatedShoppingList(w http.ResponseWriter, r *http.Request, id int) {
+
+	list, err := s.db.GetShoppingList(r.Context(), id)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.NewShoppingListDto(*list))
+}
+
+func (s *Server) GetShoppingListHandler(w http.ResponseWriter, r *http.Request) {
+
+	listId, err := uuidPathID(r, "listId", s.db.ShoppingListIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	list, err := s.db.GetShoppingList(r.Context(), listId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Shopping list not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewShoppingListDto(*list))
+}
+
+// PatchShoppingListItemHandler checks an item off the list. An empty body,
+// or one without checked, flips the item.
+func (s *Server) PatchShoppingListItemHandler(w http.ResponseWriter, r *http.Request) {
+
+	listId, err := uuidPathID(r, "listId", s.db.ShoppingListIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	itemId, err := models.ParseID(r.PathValue("itemId"))
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.ShoppingListItemInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	item, err := s.db.CheckShoppingListItem(r.Context(), listId, itemId, input.Checked)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Shopping list item not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewShoppingListItemDto(item))
+}
+
+func (s *Server) DeleteShoppingListHandler(w http.ResponseWriter, r *http.Request) {
+
+	listId, err := uuidPathID(r, "listId", s.db.ShoppingListIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	err = s.db.DeleteShoppingList(r.Context(), listId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Shopping list not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
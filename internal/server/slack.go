@@ -0,0 +1,129 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// slackMaxSkew is how old a signed Slack request may be before it is
+// rejected as a possible replay.
+const slackMaxSkew = 5 * time.Minute
+
+// verifySlackSignature checks the X-Slack-Signature header against the
+// request body, signed with secret, as described in https://api.slack.com/authentication/verifying-requests-from-slack
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	timestamp, err := strconv.ParseInt(header.Get("X-Slack-Request-Timestamp"), 10, 64)
+
+	if err != nil || math.Abs(now.Sub(time.Unix(timestamp, 0)).Seconds()) > slackMaxSkew.Seconds() {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
+
+func (s *Server) SlackCommandHandler(w http.ResponseWriter, r *http.Request) {
+
+	if s.slackSecret == "" {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Slack integration is not configured"))
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+	defer r.Body.Close()
+
+	if !verifySlackSignature(s.slackSecret, r.Header, body, time.Now()) {
+		writeError(w, r, httperr.New(http.StatusUnauthorized, "Invalid signature"))
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// The text is a list of tags, e.g. "/lunch vegan, quick", that the
+	// recipe must all carry.
+	tags := models.NormalizeTags(strings.FieldsFunc(form.Get("text"), func(c rune) bool {
+		return c == ',' || unicode.IsSpace(c)
+	}))
+
+	recipe, err := s.recipes.Random(r.Context(), models.RecipeFilter{Tags: tags})
+
+	if err != nil {
+		// Slack shows non-200 responses as a generic failure, answer privately instead.
+		log.Printf("slack: random recipe failed: %v", err)
+		writeSlackMessage(w, map[string]any{
+			"response_type": "ephemeral",
+			"text":          "Something went wrong, please try again later.",
+		})
+		return
+	}
+
+	if recipe == nil {
+		text := "No recipes found."
+		if len(tags) > 0 {
+			text = fmt.Sprintf("No recipes tagged %s.", strings.Join(tags, ", "))
+		}
+
+		writeSlackMessage(w, map[string]any{
+			"response_type": "ephemeral",
+			"text":          text,
+		})
+		return
+	}
+
+	link := fmt.Sprintf("%s/recipe/%s", publicBaseURL, recipe.Uuid)
+
+	section := map[string]any{
+		"type": "section",
+		"text": map[string]string{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("*<%s|%s>*\n%s", link, recipe.Name, recipe.Description),
+		},
+	}
+
+	if recipe.Url != nil && *recipe.Url != "" {
+		section["accessory"] = map[string]string{
+			"type":      "image",
+			"image_url": *recipe.Url,
+			"alt_text":  recipe.Name,
+		}
+	}
+
+	writeSlackMessage(w, map[string]any{
+		"response_type": "in_channel",
+		"text":          "Lunch idea: " + recipe.Name,
+		"blocks":        []any{section},
+	})
+}
+
+func writeSlackMessage(w http.ResponseWriter, message map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(message)
+}
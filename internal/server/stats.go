@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	publicStatsTTL    = 5 * time.Minute
+	publicStatsNewest = 5
+)
+
+type publicStatsCache struct {
+	mu        sync.Mutex
+	stats     *models.PublicStatsDto
+	expiresAt time.Time
+}
+
+func (s *Server) GetPublicStatsHandler(w http.ResponseWriter, r *http.Request) {
+
+	s.publicStats.mu.Lock()
+	defer s.publicStats.mu.Unlock()
+
+	if s.publicStats.stats == nil || time.Now().After(s.publicStats.expiresAt) {
+		stats, err := s.db.GetPublicStats(r.Context(), publicStatsNewest)
+
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		dto := models.NewPublicStatsDto(*stats)
+		s.publicStats.stats = &dto
+		s.publicStats.expiresAt = time.Now().Add(publicStatsTTL)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(time.Until(s.publicStats.expiresAt).Seconds())))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(s.publicStats.stats)
+}
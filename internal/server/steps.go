@@ -0,0 +1,161 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+)
+
+// PostRecipeStepHandler appends a step to the procedure of a recipe.
+func (s *Server) PostRecipeStepHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.RecipeStepInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	id, err := s.recipes.AddStep(r.Context(), recipeId, input)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+
+	writeCreated(w, r, "Step", id)
+}
+
+// PutRecipeStepsHandler reorders the steps of a recipe. The body lists the
+// ids of all of them in their new order; the answer is the reordered steps.
+func (s *Server) PutRecipeStepsHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.RecipeStepOrderInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	steps, err := s.recipes.ReorderSteps(r.Context(), recipeId, input.StepIds)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if errors.Is(err, database.ErrStepOrder) {
+		writeError(w, r, models.ValidationErrors{"step_ids": err.Error()})
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(steps)
+}
+
+// PutRecipeStepHandler replaces the text, image and duration of a step.
+func (s *Server) PutRecipeStepHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, stepId, err := s.stepPath(r)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.RecipeStepInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	err = s.recipes.UpdateStep(r.Context(), recipeId, stepId, input)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Step not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteRecipeStepHandler deletes a step; the steps after it move up.
+func (s *Server) DeleteRecipeStepHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, stepId, err := s.stepPath(r)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	err = s.recipes.DeleteStep(r.Context(), recipeId, stepId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Step not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// stepPath returns the recipe and step ids of /recipe/{recipeId}/steps/{stepId}.
+func (s *Server) stepPath(r *http.Request) (int, int, error) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stepId, err := models.ParseID(r.PathValue("stepId"))
+
+	return recipeId, stepId, err
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/ratelimit"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSuggestionLinks is the most links a suggestion may contain before it
+// is treated as spam.
+const maxSuggestionLinks = 2
+
+// suggestionLimiter caps the suggestions a client can send, by address.
+var suggestionLimiter = ratelimit.New(envInt("SUGGESTIONS_PER_HOUR", 5), time.Hour)
+
+var suggestionStatuses = map[string]bool{
+	models.SuggestionPending:  true,
+	models.SuggestionAccepted: true,
+	models.SuggestionRejected: true,
+}
+
+// PostSuggestionHandler stores a recipe idea or catalog error report from an
+// anonymous visitor for an admin to review.
+func (s *Server) PostSuggestionHandler(w http.ResponseWriter, r *http.Request) {
+
+	if ok, retryAfter := suggestionLimiter.Allow(clientAddress(r)); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		writeError(w, r, httperr.New(http.StatusTooManyRequests, "Too many suggestions, try again later"))
+		return
+	}
+
+	var input models.SuggestionInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// Bots filling in the honeypot get the same answer as everyone else, so
+	// they can't tell they were caught.
+	if input.Website != "" {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if err := input.Validate(); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	input.Message = strings.TrimSpace(input.Message)
+
+	if strings.Count(input.Message, "http://")+strings.Count(input.Message, "https://") > maxSuggestionLinks {
+		writeError(w, r, models.ValidationErrors{"message": "must not contain more than 2 links"})
+		return
+	}
+
+	if !s.filterText(w, r, &input.Message) {
+		return
+	}
+
+	var recipeId *int
+
+	if input.RecipeId != nil {
+		id := int(*input.RecipeId)
+		recipeId = &id
+	}
+
+	_, err := s.db.InsertSuggestion(r.Context(), input.Kind, input.Message, recipeId)
+
+	if errors.Is(err, database.ErrUnknownReference) {
+		writeError(w, r, models.ValidationErrors{"recipe_id": "does not exist"})
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// GetSuggestionsHandler is the moderation queue: the suggestions with
+// ?status=, pending by default, oldest first.
+func (s *Server) GetSuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	status := r.URL.Query().Get("status")
+
+	if status == "" {
+		status = models.SuggestionPending
+	}
+
+	if !suggestionStatuses[status] {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "status must be pending, accepted or rejected"))
+		return
+	}
+
+	suggestions, err := s.db.GetSuggestions(r.Context(), status)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewSuggestionDtos(suggestions))
+}
+
+// PatchSuggestionHandler accepts or rejects a suggestion.
+func (s *Server) PatchSuggestionHandler(w http.ResponseWriter, r *http.Request) {
+
+	suggestionId, err := models.ParseID(r.PathValue("suggestionId"))
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.SuggestionReviewInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if !suggestionStatuses[input.Status] {
+		writeError(w, r, models.ValidationErrors{"status": "must be pending, accepted or rejected"})
+		return
+	}
+
+	err = s.db.ReviewSuggestion(r.Context(), suggestionId, input.Status)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Suggestion not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// clientAddress is the address requests from a client come from.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+)
+
+// GetTagsHandler lists the tags in use with how many recipes carry each,
+// for clients to offer as filters of GET /recipes.
+func (s *Server) GetTagsHandler(w http.ResponseWriter, r *http.Request) {
+
+	tags, err := s.db.GetTags(r.Context())
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewTagDtos(tags))
+}
+
+// PostRecipeTagsHandler adds tags to a recipe and answers with all of its
+// tags.
+func (s *Server) PostRecipeTagsHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.RecipeTagsInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	tags, err := s.recipes.AttachTags(r.Context(), recipeId, input)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+	s.purge(r.Context(), "/tags")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tags)
+}
+
+// DeleteRecipeTagHandler removes a tag from a recipe.
+func (s *Server) DeleteRecipeTagHandler(w http.ResponseWriter, r *http.Request) {
+
+	recipeId, err := pathID(r, "recipeId", s.db.RecipeIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	err = s.recipes.DetachTag(r.Context(), recipeId, r.PathValue("tag"))
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Tag not found on the recipe"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s.purgeRecipe(r.Context(), recipeId)
+	s.purge(r.Context(), "/tags")
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"os"
+	"time"
+)
+
+// classifyInterval is how often the classifier proposes tags for the
+// recipes, from CLASSIFY_INTERVAL. 0 disables it.
+func classifyInterval() time.Duration {
+	value := os.Getenv("CLASSIFY_INTERVAL")
+
+	if value == "" {
+		return 24 * time.Hour
+	}
+
+	interval, err := time.ParseDuration(value)
+
+	if err != nil || interval < 0 {
+		return 24 * time.Hour
+	}
+
+	return interval
+}
+
+// GetTagSuggestionsHandler is the review queue of the classifier: the tag
+// suggestions with ?status=, pending by default, oldest first.
+func (s *Server) GetTagSuggestionsHandler(w http.ResponseWriter, r *http.Request) {
+
+	status := r.URL.Query().Get("status")
+
+	if status == "" {
+		status = models.SuggestionPending
+	}
+
+	if !suggestionStatuses[status] {
+		writeError(w, r, httperr.New(http.StatusBadRequest, "status must be pending, accepted or rejected"))
+		return
+	}
+
+	suggestions, err := s.db.GetTagSuggestions(r.Context(), status)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewTagSuggestionDtos(suggestions))
+}
+
+// PatchTagSuggestionHandler accepts or rejects a tag suggestion.
+func (s *Server) PatchTagSuggestionHandler(w http.ResponseWriter, r *http.Request) {
+
+	suggestionId, err := models.ParseID(r.PathValue("suggestionId"))
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.SuggestionReviewInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if !suggestionStatuses[input.Status] {
+		writeError(w, r, models.ValidationErrors{"status": "must be pending, accepted or rejected"})
+		return
+	}
+
+	err = s.db.ReviewTagSuggestion(r.Context(), suggestionId, input.Status)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Tag suggestion not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+)
+
+// PutIngredientBaseHandler makes an ingredient a variant of base_id, or a
+// base ingredient again when base_id is null.
+func (s *Server) PutIngredientBaseHandler(w http.ResponseWriter, r *http.Request) {
+
+	ingredientId, err := pathID(r, "ingredientId", s.db.IngredientIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	var input models.IngredientBaseInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	var baseId *int
+
+	if input.BaseId != nil {
+		id := int(*input.BaseId)
+		baseId = &id
+	}
+
+	err = s.db.SetIngredientBase(r.Context(), ingredientId, baseId)
+
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		writeError(w, r, httperr.New(http.StatusNotFound, "Ingredient not found"))
+		return
+
+	case errors.Is(err, database.ErrUnknownReference):
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unknown base ingredient id").WithCode("unknown_reference"))
+		return
+
+	case errors.Is(err, database.ErrNestedVariant):
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "The base must be a base ingredient, and an ingredient with variants can't become a variant").WithCode("nested_variant"))
+		return
+
+	case err != nil:
+		writeError(w, r, err)
+		return
+	}
+
+	s.purge(r.Context(), "/ingredients")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetIngredientSubstitutesHandler lists the ingredients that can stand in
+// for an ingredient: its base and the other variants of that base.
+func (s *Server) GetIngredientSubstitutesHandler(w http.ResponseWriter, r *http.Request) {
+
+	ingredientId, err := pathID(r, "ingredientId", s.db.IngredientIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	ingredients, err := s.db.GetIngredientSubstitutes(r.Context(), ingredientId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Ingredient not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewIngredientDtos(ingredients))
+}
+
+// GetIngredientRecipesHandler lists the recipes using an ingredient or any
+// other ingredient of its family.
+func (s *Server) GetIngredientRecipesHandler(w http.ResponseWriter, r *http.Request) {
+
+	ingredientId, err := pathID(r, "ingredientId", s.db.IngredientIdByUUID)
+
+	if err != nil {
+		writeError(w, r, pathIDError(err))
+		return
+	}
+
+	recipes, err := s.db.GetRecipesUsingIngredient(r.Context(), ingredientId)
+
+	if errors.Is(err, database.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Ingredient not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.NewRecipeDtos(recipes))
+}
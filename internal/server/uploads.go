@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/imaging"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/storage"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const presignExpiry = 15 * time.Minute
+
+// maxUploadBytes caps the size of files sent to POST /upload.
+var maxUploadBytes = envInt("UPLOAD_MAX_BYTES", 10<<20)
+
+// thumbnailWidths are the widths, in pixels, of the thumbnails generated for
+// every upload. Narrower images are not scaled up.
+var thumbnailWidths = []int{320, 640}
+
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+func recipeImagePrefix(recipeId int) string {
+	return fmt.Sprintf("recipes/%d/", recipeId)
+}
+
+func (s *Server) PresignUploadHandler(w http.ResponseWriter, r *http.Request) {
+
+	if s.storage == nil {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Object storage is not configured"))
+		return
+	}
+
+	var input models.PresignUploadInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	ext, ok := imageExtensions[input.ContentType]
+
+	if !ok {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Unsupported content type"))
+		return
+	}
+
+	if input.ContentLength < 1 || input.ContentLength > int64(maxUploadBytes) {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, fmt.Sprintf("content_length must be between 1 and %d bytes", maxUploadBytes)))
+		return
+	}
+
+	recipe, err := s.db.GetRecipeWithIngredients(r.Context(), int(input.RecipeId))
+
+	if err != nil || recipe == nil {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Recipe not found"))
+		return
+	}
+
+	random := make([]byte, 16)
+
+	if _, err := rand.Read(random); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	key := recipeImagePrefix(int(input.RecipeId)) + hex.EncodeToString(random) + ext
+
+	url, err := s.storage.PresignPut(r.Context(), key, input.ContentType, input.ContentLength, presignExpiry)
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadGateway, err.Error()))
+		return
+	}
+
+	headers := map[string]string{
+		"Content-Type":   input.ContentType,
+		"Content-Length": strconv.FormatInt(input.ContentLength, 10),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.PresignUploadDto{
+		Url:       url,
+		Key:       key,
+		Method:    http.MethodPut,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(presignExpiry).UTC(),
+	})
+}
+
+func (s *Server) ConfirmUploadHandler(w http.ResponseWriter, r *http.Request) {
+
+	if s.storage == nil {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Object storage is not configured"))
+		return
+	}
+
+	var input models.ConfirmUploadInputDto
+
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, r, httperr.New(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if !strings.HasPrefix(input.Key, recipeImagePrefix(int(input.RecipeId))) || strings.Contains(input.Key, "..") {
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Key does not belong to this recipe"))
+		return
+	}
+
+	valid, err := s.checkUpload(r.Context(), input.Key)
+
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, r, httperr.New(http.StatusNotFound, "Upload not found"))
+		return
+	}
+
+	if err != nil {
+		writeError(w, r, httperr.New(http.StatusBadGateway, err.Error()))
+		return
+	}
+
+	if !valid {
+		if err := s.storage.Delete(r.Context(), input.Key); err != nil {
+			log.Printf("error deleting rejected upload %s: %v", input.Key, err)
+		}
+		writeError(w, r, httperr.New(http.StatusUnprocessableEntity, "Upload is not a supported image"))
+		return
+	}
+
+	url := s.storage.URL(input.Key)
+
+	if err := s.db.UpdateRecipeImage(r.Context(), int(input.RecipeId), url); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.ImagesUploaded.Inc()
+
+	s.purgeRecipe(r.Context(), int(input.RecipeId))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"url": url})
+}
+
+// checkUpload reports whether the object uploaded under key is an image
+// ConfirmUploadHandler may attach: no larger than maxUploadBytes, stored with
+// the content type its extension stands for, and starting with bytes of
+// that type.
+func (s *Server) checkUpload(ctx context.Context, key string) (bool, error) {
+	info, err := s.storage.Stat(ctx, key)
+
+	if err != nil {
+		return false, err
+	}
+
+	if info.Size < 1 || info.Size > int64(maxUploadBytes) || imageExtensions[info.ContentType] != path.Ext(key) {
+		return false, nil
+	}
+
+	// DetectContentType looks at no more than the first 512 bytes.
+	head, err := s.storage.Head(ctx, key, 512)
+
+	if err != nil {
+		return false, err
+	}
+
+	return http.DetectContentType(head) == info.ContentType, nil
+}
+
+// UploadHandler stores an image sent as the "file" field of a multipart form,
+// along with thumbnails of it, and answers with their URLs. The URL can then
+// be saved as the image_url of a recipe or ingredient.
+func (s *Server) UploadHandler(w http.ResponseWriter, r *http.Request) {
+
+	if s.storage == nil {
+		writeError(w, r, httperr.New(http.StatusServiceUnavailable, "Object storage is not configured"))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(maxUploadBytes))
+
+	file, _, err := r.FormFile("file")
+
+	if err != nil {
+		writeError(w, r, uploadError(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+
+	if err != nil {
+		writeError(w, r, uploadError(err))
+		return
+	}
+
+	upload, err := s.storeImage(r.Context(), "uploads/", data)
+
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	metrics.ImagesUploaded.Inc()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(upload)
+}
+
+// storeImage stores an image under prefix with a random name, along with
+// thumbnails of it. The image is stored re-encoded, never as sent, so
+// metadata such as EXIF location doesn't reach the public bucket. Images
+// that aren't JPEG, PNG or WebP, or don't decode, are refused with a 422 and
+// storage failures are a 502.
+func (s *Server) storeImage(ctx context.Context, prefix string, data []byte) (models.UploadDto, error) {
+
+	// Trust the bytes, not the content type the client claims.
+	if _, ok := imageExtensions[http.DetectContentType(data)]; !ok {
+		return models.UploadDto{}, httperr.New(http.StatusUnprocessableEntity, "Unsupported content type")
+	}
+
+	img, format, err := imaging.Decode(data)
+
+	if err != nil {
+		return models.UploadDto{}, httperr.New(http.StatusUnprocessableEntity, "Invalid image: "+err.Error())
+	}
+
+	clean, contentType, err := imaging.Encode(img, format)
+
+	if err != nil {
+		return models.UploadDto{}, err
+	}
+
+	random := make([]byte, 16)
+
+	if _, err := rand.Read(random); err != nil {
+		return models.UploadDto{}, err
+	}
+
+	name := prefix + hex.EncodeToString(random)
+	key := name + imageExtensions[contentType]
+
+	if err := s.storage.Put(ctx, key, clean, contentType); err != nil {
+		return models.UploadDto{}, httperr.New(http.StatusBadGateway, err.Error())
+	}
+
+	upload := models.UploadDto{Url: s.storage.URL(key), Key: key, Thumbnails: map[string]string{}}
+
+	for _, width := range thumbnailWidths {
+		if width >= img.Bounds().Dx() {
+			continue
+		}
+
+		thumb, thumbType, err := imaging.Encode(imaging.Thumbnail(img, width), format)
+
+		if err != nil {
+			return models.UploadDto{}, err
+		}
+
+		thumbKey := fmt.Sprintf("%s_%dw%s", name, width, imageExtensions[thumbType])
+
+		if err := s.storage.Put(ctx, thumbKey, thumb, thumbType); err != nil {
+			return models.UploadDto{}, httperr.New(http.StatusBadGateway, err.Error())
+		}
+
+		upload.Thumbnails[strconv.Itoa(width)] = s.storage.URL(thumbKey)
+	}
+
+	return upload, nil
+}
+
+// uploadError is the error to answer a failed read of the upload with: a
+// 413 when it is too large, a 400 otherwise.
+func uploadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+	return httperr.New(http.StatusBadRequest, err.Error())
+}
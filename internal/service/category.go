@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"strings"
+)
+
+type CategoryService struct {
+	store     database.CategoryRepository
+	checkText TextCheck
+}
+
+// NewCategoryService returns a CategoryService keeping categories in store
+// and checking their names with checkText, which may be nil.
+func NewCategoryService(store database.CategoryRepository, checkText TextCheck) *CategoryService {
+	return &CategoryService{store: store, checkText: orAccept(checkText)}
+}
+
+// Create stores a new category and returns its id.
+func (s *CategoryService) Create(ctx context.Context, input models.CategoryInputDto) (int, error) {
+	if err := s.prepare(&input); err != nil {
+		return -1, err
+	}
+
+	return s.store.InsertCategory(ctx, input.Name)
+}
+
+func (s *CategoryService) List(ctx context.Context) ([]models.CategoryDto, error) {
+	categories, err := s.store.GetCategories(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCategoryDtos(categories), nil
+}
+
+// Rename renames a category and returns it. It returns
+// database.ErrNotFound when the category doesn't exist.
+func (s *CategoryService) Rename(ctx context.Context, id int, input models.CategoryInputDto) (models.CategoryDto, error) {
+	if err := s.prepare(&input); err != nil {
+		return models.CategoryDto{}, err
+	}
+
+	if err := s.store.UpdateCategory(ctx, id, input.Name); err != nil {
+		return models.CategoryDto{}, err
+	}
+
+	return models.NewCategoryDto(models.Category{Id: id, Name: input.Name}), nil
+}
+
+// Delete deletes a category. It returns database.ErrInUse while recipes
+// are in it.
+func (s *CategoryService) Delete(ctx context.Context, id int) error {
+	return s.store.DeleteCategory(ctx, id)
+}
+
+func (s *CategoryService) prepare(input *models.CategoryInputDto) error {
+	input.Name = strings.TrimSpace(input.Name)
+
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.checkText(&input.Name)
+}
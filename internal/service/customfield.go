@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/jsonschema"
+	"gastro-galaxy-back/internal/models"
+)
+
+// CustomFieldStore is the part of database.Service custom fields are kept
+// in.
+type CustomFieldStore interface {
+	database.CustomFieldRepository
+	database.RecipeRepository
+}
+
+// CustomFieldService manages the fields the kitchen adds to recipes and
+// checks their values against the fields' JSON Schemas.
+type CustomFieldService struct {
+	store CustomFieldStore
+}
+
+func NewCustomFieldService(store CustomFieldStore) *CustomFieldService {
+	return &CustomFieldService{store: store}
+}
+
+func (s *CustomFieldService) List(ctx context.Context) ([]models.CustomFieldDto, error) {
+	fields, err := s.store.GetCustomFields(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewCustomFieldDtos(fields), nil
+}
+
+// Define adds a custom field, or replaces the schema of an existing one.
+// The schema must only use the keywords package jsonschema supports.
+func (s *CustomFieldService) Define(ctx context.Context, name string, schema json.RawMessage) (models.CustomFieldDto, error) {
+	errs := models.ValidationErrors{}
+
+	if !models.ValidCustomFieldName(name) {
+		errs["name"] = "must be lower case letters, digits and underscores, starting with a letter"
+	}
+
+	if _, err := jsonschema.Compile(schema); err != nil {
+		errs["schema"] = err.Error()
+	}
+
+	if len(errs) > 0 {
+		return models.CustomFieldDto{}, errs
+	}
+
+	field, err := s.store.SetCustomField(ctx, name, schema)
+
+	if err != nil {
+		return models.CustomFieldDto{}, err
+	}
+
+	return models.NewCustomFieldDtos([]models.CustomField{field})[0], nil
+}
+
+// Delete deletes a custom field and its values. It returns
+// database.ErrNotFound when there is no such field.
+func (s *CustomFieldService) Delete(ctx context.Context, name string) error {
+	return s.store.DeleteCustomField(ctx, name)
+}
+
+// SetRecipeValues replaces the custom field values of a recipe, after
+// checking each one against its field's schema. Null values are dropped.
+// It returns database.ErrNotFound when the recipe doesn't exist.
+func (s *CustomFieldService) SetRecipeValues(ctx context.Context, recipeId int, values map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	fields, err := s.store.GetCustomFields(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		schemas[field.Name] = field.Schema
+	}
+
+	errs := models.ValidationErrors{}
+	kept := make(map[string]json.RawMessage, len(values))
+
+	for name, value := range values {
+		schema, ok := schemas[name]
+
+		if !ok {
+			errs[name] = "is not a custom field"
+			continue
+		}
+
+		if string(value) == "null" {
+			continue
+		}
+
+		compiled, err := jsonschema.Compile(schema)
+
+		if err == nil {
+			err = compiled.Validate(value)
+		}
+
+		if err != nil {
+			errs[name] = err.Error()
+			continue
+		}
+
+		kept[name] = value
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err := s.store.SetRecipeCustomFields(ctx, recipeId, kept); err != nil {
+		return nil, err
+	}
+
+	return kept, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+)
+
+type IngredientService struct {
+	store     database.IngredientRepository
+	checkText TextCheck
+}
+
+// NewIngredientService returns an IngredientService keeping ingredients in
+// store and checking their names with checkText, which may be nil.
+func NewIngredientService(store database.IngredientRepository, checkText TextCheck) *IngredientService {
+	return &IngredientService{store: store, checkText: orAccept(checkText)}
+}
+
+// Create validates and stores a new ingredient, and returns its id. The
+// quantity and unit are read from the amount when neither is given.
+func (s *IngredientService) Create(ctx context.Context, input models.IngredientInputDto) (int, error) {
+	if err := s.prepare(&input); err != nil {
+		return -1, err
+	}
+
+	return s.store.InsertIngredient(ctx, input.Name, input.Amount, input.Url, input.IsAvailable, input.Quantity, input.Unit, input.Nutrition.Nutrition())
+}
+
+// Update validates the input and replaces the ingredient with it. It
+// returns database.ErrNotFound when the ingredient doesn't exist.
+func (s *IngredientService) Update(ctx context.Context, id int, input models.IngredientInputDto) error {
+	if err := s.prepare(&input); err != nil {
+		return err
+	}
+
+	return s.store.UpdateIngredient(ctx, id, input.Name, input.Amount, input.Url, input.IsAvailable, input.Quantity, input.Unit, input.Nutrition.Nutrition())
+}
+
+// Delete deletes an ingredient. Unless cascade is set, it returns
+// database.ErrInUse when recipes use the ingredient.
+func (s *IngredientService) Delete(ctx context.Context, id int, cascade bool) error {
+	return s.store.DeleteIngredient(ctx, id, cascade)
+}
+
+// Page returns the page-th page of pageSize ingredients, only the ones
+// with the given availability unless available is nil, with the total over
+// all pages.
+func (s *IngredientService) Page(ctx context.Context, available *bool, page int, pageSize int) (models.PageDto[models.IngredientDto], error) {
+	ingredients, total, err := s.store.GetIngredientsPage(ctx, available, pageSize, (page-1)*pageSize)
+
+	if err != nil {
+		return models.PageDto[models.IngredientDto]{}, err
+	}
+
+	return models.NewPageDto(models.NewIngredientDtos(ingredients), page, pageSize, total), nil
+}
+
+// Stream calls fn with every ingredient with the given availability, or
+// every ingredient when available is nil, as it is read.
+func (s *IngredientService) Stream(ctx context.Context, available *bool, fn func(models.IngredientDto) error) error {
+	return s.store.StreamIngredients(ctx, available, func(ingredient models.Ingedient) error {
+		return fn(models.NewIngredientDto(ingredient))
+	})
+}
+
+func (s *IngredientService) prepare(input *models.IngredientInputDto) error {
+	input.ParseAmount()
+
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	return s.checkText(&input.Name)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+)
+
+// RecipeStore is the part of database.Service recipes, with their steps and
+// tags, are kept in.
+type RecipeStore interface {
+	database.RecipeRepository
+	database.StepRepository
+	database.TagRepository
+}
+
+type RecipeService struct {
+	store     RecipeStore
+	checkText TextCheck
+}
+
+// NewRecipeService returns a RecipeService keeping recipes in store and
+// checking their texts with checkText, which may be nil.
+func NewRecipeService(store RecipeStore, checkText TextCheck) *RecipeService {
+	return &RecipeService{store: store, checkText: orAccept(checkText)}
+}
+
+// Create validates and stores a new recipe, and returns its id.
+func (s *RecipeService) Create(ctx context.Context, input models.RecipeInputDto) (int, error) {
+	if err := input.Validate(); err != nil {
+		return -1, err
+	}
+
+	if err := s.checkText(&input.Name, &input.Description, &input.LongDescription); err != nil {
+		return -1, err
+	}
+
+	return s.store.InsertRecipe(ctx, input.Name, input.Description, input.LongDescription, input.Url, int(input.CategoryId), input.IngedientIds)
+}
+
+// Get returns a recipe with its ingredients, or database.ErrNotFound.
+func (s *RecipeService) Get(ctx context.Context, id int) (models.RecipeWithIngredientsDto, error) {
+	recipe, err := s.load(ctx, id)
+
+	if err != nil {
+		return models.RecipeWithIngredientsDto{}, err
+	}
+
+	return models.NewRecipeWithIngredientsDto(*recipe), nil
+}
+
+// Update validates the input and updates the recipe, replacing its
+// ingredient list, in one write. It returns database.ErrNotFound when the recipe
+// doesn't exist and database.ErrUnknownReference when an ingredient
+// doesn't.
+func (s *RecipeService) Update(ctx context.Context, id int, input models.RecipeInputDto) error {
+	if _, err := s.load(ctx, id); err != nil {
+		return err
+	}
+
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.checkText(&input.Name, &input.Description); err != nil {
+		return err
+	}
+
+	return s.store.UpdateRecipe(ctx, id, input.Name, input.Description, input.Url, input.IngedientIds)
+}
+
+// ReplaceIngredients replaces the ingredient list of a recipe and returns
+// the updated recipe. It returns database.ErrUnknownReference when one of
+// the ingredients doesn't exist.
+func (s *RecipeService) ReplaceIngredients(ctx context.Context, id int, ingredientIds []int) (models.RecipeWithIngredientsDto, error) {
+	if err := s.store.ReplaceRecipeIngredients(ctx, id, ingredientIds); err != nil {
+		return models.RecipeWithIngredientsDto{}, err
+	}
+
+	return s.Get(ctx, id)
+}
+
+func (s *RecipeService) Delete(ctx context.Context, id int) error {
+	return s.store.DeleteRecipe(ctx, id)
+}
+
+// Page returns the page-th page of pageSize recipes matching filter, with
+// the total over all pages.
+func (s *RecipeService) Page(ctx context.Context, filter models.RecipeFilter, page int, pageSize int) (models.PageDto[models.RecipeDto], error) {
+	recipes, total, err := s.store.GetRecipesPage(ctx, filter, pageSize, (page-1)*pageSize)
+
+	if err != nil {
+		return models.PageDto[models.RecipeDto]{}, err
+	}
+
+	return models.NewPageDto(models.NewRecipeDtos(recipes), page, pageSize, total), nil
+}
+
+// Stream calls fn with every recipe matching filter, as it is read.
+func (s *RecipeService) Stream(ctx context.Context, filter models.RecipeFilter, fn func(models.RecipeDto) error) error {
+	return s.store.StreamRecipes(ctx, filter, func(recipe models.Recipe) error {
+		return fn(models.NewRecipeDto(recipe))
+	})
+}
+
+// Random returns a random recipe among those matching filter, or nil when
+// none does.
+func (s *RecipeService) Random(ctx context.Context, filter models.RecipeFilter) (*models.RecipeDto, error) {
+	recipe, err := s.store.GetRandomRecipeMatching(ctx, filter)
+
+	if err != nil || recipe == nil {
+		return nil, err
+	}
+
+	dto := models.NewRecipeDto(*recipe)
+	return &dto, nil
+}
+
+// Nutrition returns the nutrition of a recipe, added up over the
+// ingredients that have a quantity and nutrition facts.
+func (s *RecipeService) Nutrition(ctx context.Context, id int) (models.RecipeNutritionDto, error) {
+	recipe, err := s.load(ctx, id)
+
+	if err != nil {
+		return models.RecipeNutritionDto{}, err
+	}
+
+	return models.NewRecipeNutritionDto(recipe.Nutrition), nil
+}
+
+// Scale returns a recipe with its ingredient quantities multiplied by
+// factor. See ScaleRecipe.
+func (s *RecipeService) Scale(ctx context.Context, id int, factor float64) (models.ScaledRecipeDto, error) {
+	recipe, err := s.load(ctx, id)
+
+	if err != nil {
+		return models.ScaledRecipeDto{}, err
+	}
+
+	scaled, err := ScaleRecipe(*recipe, factor)
+
+	if err != nil {
+		return models.ScaledRecipeDto{}, err
+	}
+
+	return models.NewScaledRecipeDto(scaled, factor), nil
+}
+
+// AddStep validates a step and appends it to a recipe, and returns its id.
+// It returns database.ErrNotFound when the recipe doesn't exist.
+func (s *RecipeService) AddStep(ctx context.Context, recipeId int, input models.RecipeStepInputDto) (int, error) {
+	step, err := s.step(input)
+
+	if err != nil {
+		return -1, err
+	}
+
+	step.RecipeId = recipeId
+
+	return s.store.InsertRecipeStep(ctx, step)
+}
+
+// UpdateStep validates the input and updates a step of a recipe, keeping its
+// position. It returns database.ErrNotFound when the recipe has no such
+// step.
+func (s *RecipeService) UpdateStep(ctx context.Context, recipeId int, stepId int, input models.RecipeStepInputDto) error {
+	step, err := s.step(input)
+
+	if err != nil {
+		return err
+	}
+
+	step.Id = stepId
+	step.RecipeId = recipeId
+
+	return s.store.UpdateRecipeStep(ctx, step)
+}
+
+// DeleteStep deletes a step of a recipe; the steps after it move up.
+func (s *RecipeService) DeleteStep(ctx context.Context, recipeId int, stepId int) error {
+	return s.store.DeleteRecipeStep(ctx, recipeId, stepId)
+}
+
+// ReorderSteps puts the steps of a recipe in the order of stepIds and
+// returns them. It returns database.ErrStepOrder when stepIds doesn't list
+// each step of the recipe exactly once.
+func (s *RecipeService) ReorderSteps(ctx context.Context, recipeId int, stepIds []int) ([]models.RecipeStepDto, error) {
+	if err := s.store.ReorderRecipeSteps(ctx, recipeId, stepIds); err != nil {
+		return nil, err
+	}
+
+	steps, err := s.store.GetRecipeSteps(ctx, recipeId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewRecipeStepDtos(steps), nil
+}
+
+// step returns the step input describes, once it is valid and its text has
+// been checked.
+func (s *RecipeService) step(input models.RecipeStepInputDto) (models.RecipeStep, error) {
+	if err := input.Validate(); err != nil {
+		return models.RecipeStep{}, err
+	}
+
+	if err := s.checkText(&input.Text); err != nil {
+		return models.RecipeStep{}, err
+	}
+
+	step := models.RecipeStep{Text: input.Text, DurationMinutes: input.DurationMinutes}
+
+	if input.ImageUrl != "" {
+		step.ImageUrl = &input.ImageUrl
+	}
+
+	return step, nil
+}
+
+// AttachTags adds the tags of input to a recipe and returns all of its
+// tags. It returns database.ErrNotFound when the recipe doesn't exist.
+func (s *RecipeService) AttachTags(ctx context.Context, id int, input models.RecipeTagsInputDto) ([]string, error) {
+	tags, err := input.Normalize()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range tags {
+		if err := s.checkText(&tags[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.store.AttachRecipeTags(ctx, id, tags); err != nil {
+		return nil, err
+	}
+
+	return s.store.GetRecipeTags(ctx, id)
+}
+
+// DetachTag removes a tag from a recipe. It returns database.ErrNotFound
+// when the recipe doesn't carry it.
+func (s *RecipeService) DetachTag(ctx context.Context, id int, tag string) error {
+	return s.store.DetachRecipeTag(ctx, id, models.Slug(tag))
+}
+
+// load returns a recipe with its ingredients, or database.ErrNotFound.
+func (s *RecipeService) load(ctx context.Context, id int) (*models.RecipeWithIngredients, error) {
+	recipe, err := s.store.GetRecipeWithIngredients(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if recipe == nil {
+		return nil, database.ErrNotFound
+	}
+
+	return recipe, nil
+}
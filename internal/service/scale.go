@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/quantity"
+)
+
+// MaxScaleFactor is the largest factor a recipe can be scaled by.
+const MaxScaleFactor = 100
+
+// ErrInvalidFactor is returned when a scaling factor is not positive or
+// above MaxScaleFactor.
+var ErrInvalidFactor = errors.New("factor must be above 0 and at most 100")
+
+// ScaleRecipe returns recipe with the quantities of its ingredients
+// multiplied by factor, and the nutrition they add up to. The text amount
+// of a scaled ingredient is rewritten from its new quantity; ingredients
+// without a quantity, such as salt "a gosto", are left as they are.
+func ScaleRecipe(recipe models.RecipeWithIngredients, factor float64) (models.RecipeWithIngredients, error) {
+	if !(factor > 0 && factor <= MaxScaleFactor) {
+		return recipe, ErrInvalidFactor
+	}
+
+	ingredients := make([]models.Ingedient, len(recipe.Ingredients))
+
+	for i, ingredient := range recipe.Ingredients {
+		if ingredient.Quantity != nil && ingredient.Unit != nil {
+			scaled := quantity.Amount{Quantity: *ingredient.Quantity * factor, Unit: quantity.Unit(*ingredient.Unit)}
+			amount := scaled.String()
+			ingredient.Quantity = &scaled.Quantity
+			ingredient.Amount = &amount
+		}
+		ingredients[i] = ingredient
+	}
+
+	recipe.Ingredients = ingredients
+	recipe.Nutrition = models.NutritionOf(ingredients)
+
+	return recipe, nil
+}
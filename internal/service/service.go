@@ -0,0 +1,22 @@
+// Package service holds the business rules of the API, between the HTTP
+// handlers and the database: validating input, checking it for banned
+// words, calling the stores and mapping rows to DTOs. Services depend on the
+// repository interfaces of package database, so they can be tested without
+// HTTP or a database.
+package service
+
+// TextCheck checks user text before it is stored. It may rewrite the
+// fields, e.g. to mask banned words, or return an error to refuse them.
+type TextCheck func(fields ...*string) error
+
+// acceptText is the TextCheck of services created without one.
+func acceptText(fields ...*string) error {
+	return nil
+}
+
+func orAccept(check TextCheck) TextCheck {
+	if check == nil {
+		return acceptText
+	}
+	return check
+}
@@ -0,0 +1,132 @@
+// Package shadow mirrors a share of the production read traffic to another
+// deployment, such as a canary, to try a release under real load. Mirrored
+// requests are sent in the background and their responses are ignored, so
+// the secondary can never slow down or break a real request.
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// maxInFlight caps the mirrored requests waiting on the secondary.
+	// Requests beyond it are not mirrored.
+	maxInFlight = 64
+	timeout     = 5 * time.Second
+)
+
+// Header marks mirrored requests, so the secondary can tell them apart in
+// its logs and metrics.
+const Header = "X-Shadow-Request"
+
+// Headers that are not copied: hop-by-hop headers, and credentials that
+// should not leave the primary.
+var skippedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Connection":          true,
+	"Cookie":              true,
+	"Keep-Alive":          true,
+	"Proxy-Authorization": true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+// Mirror copies GET and HEAD requests to a secondary base URL. A nil
+// *Mirror mirrors nothing.
+type Mirror struct {
+	target   *url.URL
+	percent  int
+	client   *http.Client
+	inFlight chan struct{}
+}
+
+// New returns a Mirror sending percent percent of the read requests to
+// target.
+func New(target string, percent int) (*Mirror, error) {
+	u, err := url.Parse(strings.TrimSuffix(target, "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid shadow url %q", target)
+	}
+
+	if percent < 1 || percent > 100 {
+		return nil, fmt.Errorf("shadow percentage must be between 1 and 100, got %d", percent)
+	}
+
+	return &Mirror{
+		target:   u,
+		percent:  percent,
+		client:   &http.Client{Timeout: timeout},
+		inFlight: make(chan struct{}, maxInFlight),
+	}, nil
+}
+
+// Middleware mirrors the sampled read requests and serves every request
+// as usual.
+func (m *Mirror) Middleware(next http.Handler) http.Handler {
+	if m == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && r.Header.Get(Header) == "" && rand.IntN(100) < m.percent {
+			m.mirror(r)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// mirror sends a copy of r to the target in the background, unless too
+// many copies are already waiting.
+func (m *Mirror) mirror(r *http.Request) {
+	select {
+	case m.inFlight <- struct{}{}:
+	default:
+		return
+	}
+
+	u := *m.target
+	u.Path = m.target.Path + r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+
+	header := make(http.Header, len(r.Header)+1)
+	for key, values := range r.Header {
+		if !skippedHeaders[http.CanonicalHeaderKey(key)] {
+			header[key] = values
+		}
+	}
+	header.Set(Header, "1")
+
+	go func() {
+		defer func() { <-m.inFlight }()
+
+		// The copy must outlive the original request, so it doesn't use its
+		// context.
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, r.Method, u.String(), nil)
+		if err != nil {
+			return
+		}
+		req.Header = header
+
+		resp, err := m.client.Do(req)
+		if err != nil {
+			log.Printf("shadow request to %s failed: %v", u.Redacted(), err)
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gastro-galaxy-back/internal/requestid"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// ErrNotConfigured is returned by New when no bucket is configured.
+var ErrNotConfigured = errors.New("object storage is not configured")
+
+// ErrNotFound is returned when no object is stored under a key.
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	ContentType string
+	Size        int64
+}
+
+// Storage stores recipe images in an object storage bucket.
+type Storage interface {
+	// PresignPut returns a URL the client can PUT the object to directly.
+	// The content type and length are signed, so the PUT must send exactly
+	// those.
+	PresignPut(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (string, error)
+
+	// Put stores data under key.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+
+	// Stat describes the object stored under key, or returns ErrNotFound.
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+
+	// Head returns up to the first n bytes of the object stored under key.
+	Head(ctx context.Context, key string, n int64) ([]byte, error)
+
+	// Delete removes the object stored under key.
+	Delete(ctx context.Context, key string) error
+
+	// URL returns the public URL of the object stored under key.
+	URL(key string) string
+
+	// Ping checks that the bucket can be reached.
+	Ping(ctx context.Context) error
+}
+
+type s3Storage struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+var (
+	endpoint  = os.Getenv("S3_ENDPOINT")
+	region    = os.Getenv("S3_REGION")
+	bucket    = os.Getenv("S3_BUCKET")
+	accessKey = os.Getenv("S3_ACCESS_KEY")
+	secretKey = os.Getenv("S3_SECRET_KEY")
+	useSSL    = os.Getenv("S3_USE_SSL") != "false"
+	publicURL = os.Getenv("S3_PUBLIC_URL")
+)
+
+// New returns an S3-compatible Storage configured from the S3_* environment
+// variables, or ErrNotConfigured when S3_BUCKET is not set.
+func New() (Storage, error) {
+	if bucket == "" {
+		return nil, ErrNotConfigured
+	}
+
+	host := endpoint
+	if host == "" {
+		host = "s3.amazonaws.com"
+	}
+
+	transport, err := minio.DefaultTransport(useSSL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := minio.New(host, &minio.Options{
+		Creds:     credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure:    useSSL,
+		Region:    region,
+		Transport: &requestid.Transport{Base: transport},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	base := publicURL
+	if base == "" {
+		scheme := "https"
+		if !useSSL {
+			scheme = "http"
+		}
+		base = fmt.Sprintf("%s://%s/%s", scheme, host, bucket)
+	}
+
+	return &s3Storage{
+		client:    client,
+		bucket:    bucket,
+		publicURL: strings.TrimSuffix(base, "/"),
+	}, nil
+}
+
+func (s *s3Storage) PresignPut(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (string, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+
+	u, err := s.client.PresignHeader(ctx, http.MethodPut, s.bucket, key, expires, nil, headers)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *s3Storage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return ObjectInfo{}, ErrNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{ContentType: info.ContentType, Size: info.Size}, nil
+}
+
+func (s *s3Storage) Head(ctx context.Context, key string, n int64) ([]byte, error) {
+	opts := minio.GetObjectOptions{}
+	if err := opts.SetRange(0, n-1); err != nil {
+		return nil, err
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, key, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	return io.ReadAll(io.LimitReader(object, n))
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Storage) URL(key string) string {
+	return s.publicURL + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func (s *s3Storage) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", s.bucket)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/metrics"
+	"gastro-galaxy-back/internal/models"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	apiURL       = "https://api.telegram.org/bot"
+	pollTimeout  = 30
+	searchLimit  = 5
+	retryBackoff = 5 * time.Second
+)
+
+// Bot answers Telegram chat commands using the database service.
+type Bot struct {
+	token   string
+	baseURL string
+	db      database.Service
+	client  *http.Client
+}
+
+type update struct {
+	UpdateId int `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			Id int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// New creates a bot authenticated with token. Recipe links point to
+// publicBaseURL.
+func New(token string, publicBaseURL string, db database.Service) *Bot {
+	return &Bot{
+		token:   token,
+		baseURL: publicBaseURL,
+		db:      db,
+		client:  &http.Client{Timeout: (pollTimeout + 10) * time.Second},
+	}
+}
+
+// Run long-polls Telegram for updates until ctx is cancelled.
+func (b *Bot) Run(ctx context.Context) {
+	offset := 0
+
+	for ctx.Err() == nil {
+		updates, err := b.getUpdates(ctx, offset)
+
+		if err != nil {
+			log.Printf("telegram: cannot get updates: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryBackoff):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateId + 1
+
+			if u.Message == nil {
+				continue
+			}
+
+			if err := b.sendMessage(ctx, u.Message.Chat.Id, b.reply(ctx, u.Message.Text)); err != nil {
+				log.Printf("telegram: cannot send message: %v", err)
+			}
+		}
+	}
+}
+
+func (b *Bot) reply(ctx context.Context, text string) string {
+	command, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+	command, _, _ = strings.Cut(command, "@")
+	args = strings.TrimSpace(args)
+
+	switch command {
+	case "/search":
+		if args == "" {
+			return "Usage: /search <recipe name>"
+		}
+
+		recipes, err := b.db.FindRecipesByName(ctx, args, searchLimit)
+
+		if err != nil {
+			log.Printf("telegram: search failed: %v", err)
+			return "Something went wrong, please try again later."
+		}
+
+		metrics.Searches.WithLabelValues("telegram").Inc()
+
+		if len(recipes) == 0 {
+			return fmt.Sprintf("No recipes found for %q.", args)
+		}
+
+		var sb strings.Builder
+		for _, recipe := range recipes {
+			sb.WriteString(b.formatRecipe(recipe))
+			sb.WriteString("\n\n")
+		}
+		return strings.TrimSpace(sb.String())
+
+	case "/random":
+		recipe, err := b.db.GetRandomRecipe(ctx, args)
+
+		if err != nil {
+			log.Printf("telegram: random recipe failed: %v", err)
+			return "Something went wrong, please try again later."
+		}
+
+		if recipe == nil {
+			return "No recipes found."
+		}
+
+		return "How about this for dinner?\n\n" + b.formatRecipe(*recipe)
+
+	default:
+		return "Commands:\n/search <name> - find recipes by name\n/random [category] - get a random dinner idea"
+	}
+}
+
+func (b *Bot) formatRecipe(recipe models.Recipe) string {
+	return fmt.Sprintf("%s\n%s\n%s/recipe/%s", recipe.Name, recipe.Description, b.baseURL, recipe.Uuid)
+}
+
+func (b *Bot) getUpdates(ctx context.Context, offset int) ([]update, error) {
+	endpoint := fmt.Sprintf("%s%s/getUpdates?timeout=%d&offset=%d", apiURL, b.token, pollTimeout, offset)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Ok          bool     `json:"ok"`
+		Description string   `json:"description"`
+		Result      []update `json:"result"`
+	}
+
+	if err := b.do(req, &result); err != nil {
+		return nil, err
+	}
+
+	if !result.Ok {
+		return nil, fmt.Errorf("telegram error: %s", result.Description)
+	}
+
+	return result.Result, nil
+}
+
+func (b *Bot) sendMessage(ctx context.Context, chatId int64, text string) error {
+	body, err := json.Marshal(map[string]any{"chat_id": chatId, "text": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL+b.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var result struct {
+		Ok          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+
+	if err := b.do(req, &result); err != nil {
+		return err
+	}
+
+	if !result.Ok {
+		return fmt.Errorf("telegram error: %s", result.Description)
+	}
+
+	return nil
+}
+
+func (b *Bot) do(req *http.Request, v any) error {
+	resp, err := b.client.Do(req)
+	if err != nil {
+		// The request URL contains the bot token, keep it out of the logs.
+		if urlErr, ok := err.(*url.Error); ok {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,97 @@
+package wordfilter
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Filter matches user-generated text against a list of banned words.
+// Matching is case-insensitive and only considers whole words.
+type Filter struct {
+	mu    sync.RWMutex
+	words map[string]struct{}
+}
+
+func New(words []string) *Filter {
+	f := &Filter{}
+	f.Set(words)
+	return f
+}
+
+// Set replaces the list of banned words.
+func (f *Filter) Set(words []string) {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		word = normalize(word)
+		if word != "" {
+			set[word] = struct{}{}
+		}
+	}
+
+	f.mu.Lock()
+	f.words = set
+	f.mu.Unlock()
+}
+
+// Find returns the banned words present in text, in order of appearance.
+func (f *Filter) Find(text string) []string {
+	var found []string
+	f.scan(text, func(start, end int) {
+		found = append(found, text[start:end])
+	})
+	return found
+}
+
+// Mask replaces every letter of the banned words present in text with '*'.
+func (f *Filter) Mask(text string) string {
+	var b strings.Builder
+	last := 0
+	f.scan(text, func(start, end int) {
+		b.WriteString(text[last:start])
+		b.WriteString(strings.Repeat("*", len([]rune(text[start:end]))))
+		last = end
+	})
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func (f *Filter) scan(text string, match func(start, end int)) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if len(f.words) == 0 {
+		return
+	}
+
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			f.check(text, start, i, match)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		f.check(text, start, len(text), match)
+	}
+}
+
+func (f *Filter) check(text string, start, end int, match func(start, end int)) {
+	if _, ok := f.words[normalize(text[start:end])]; ok {
+		match(start, end)
+	}
+}
+
+func normalize(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/analytics"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScrubSearchTerm(t *testing.T) {
+	cases := []struct {
+		term     string
+		expected string
+		ok       bool
+	}{
+		{"  Bolo de   Cenoura ", "bolo de cenoura", true},
+		{"pizza maria@example.com", "pizza", true},
+		{"receita da tia 11987654321", "receita da tia", true},
+		{"bolo 2 ovos", "bolo 2 ovos", true},
+		{"joao@example.com", "", false},
+	}
+
+	for _, c := range cases {
+		scrubbed, ok := analytics.ScrubSearchTerm(c.term)
+		if scrubbed != c.expected || ok != c.ok {
+			t.Errorf("ScrubSearchTerm(%q) = %q, %v; expected %q, %v", c.term, scrubbed, ok, c.expected, c.ok)
+		}
+	}
+
+	if path := analytics.ScrubPath("/recipe/3?ref=mail&email=a@b.c#top"); path != "/recipe/3" {
+		t.Errorf("expected the query and fragment to be dropped; got %q", path)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Sec-GPC", "1")
+	if !analytics.OptedOut(r) {
+		t.Error("expected Sec-GPC: 1 to opt out")
+	}
+}
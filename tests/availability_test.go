@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"testing"
+)
+
+// TestSetIngredientsAvailability checks setting, flipping and the all or
+// nothing handling of unknown ids.
+func TestSetIngredientsAvailability(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`INSERT INTO ingredient (id, name, isavailable) VALUES (1, 'Sal', true), (2, 'Ovo', NULL)`); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	flipped, err := db.SetIngredientsAvailability(ctx, []int{2, 1, 2}, nil)
+	if err != nil {
+		t.Fatalf("error flipping availability. Err: %v", err)
+	}
+	if len(flipped) != 2 || flipped[0].IsAvailable || !flipped[1].IsAvailable {
+		t.Errorf("expected Sal unavailable and Ovo available; got %+v", flipped)
+	}
+
+	available := true
+	if _, err := db.SetIngredientsAvailability(ctx, []int{1, 99}, &available); !errors.Is(err, database.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown id; got %v", err)
+	}
+
+	var sal bool
+	if err := conn.QueryRow(`SELECT isavailable FROM ingredient WHERE id = 1`).Scan(&sal); err != nil {
+		t.Fatalf("error reading availability. Err: %v", err)
+	}
+	if sal {
+		t.Error("expected a failed bulk update to change nothing")
+	}
+}
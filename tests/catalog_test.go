@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/fixtures"
+	"gastro-galaxy-back/internal/models"
+	"testing"
+)
+
+func TestCatalogValidation(t *testing.T) {
+	category := "0191d6a8-0000-7000-8000-000000000001"
+
+	catalog := models.CatalogDto{
+		Version:    models.CatalogVersion,
+		Categories: []models.CatalogCategoryDto{{Uuid: category, Name: "Massas"}},
+		Recipes: []models.CatalogRecipeDto{
+			{Uuid: "0191d6a8-0000-7000-8000-000000000002", Name: "Lasanha", Category: &category, Ingredients: []string{}},
+		},
+	}
+	if err := catalog.Validate(); err != nil {
+		t.Errorf("expected a valid catalog; got %v", err)
+	}
+
+	catalog.Version = 2
+	catalog.Recipes = append(catalog.Recipes, models.CatalogRecipeDto{Uuid: catalog.Recipes[0].Uuid, Ingredients: []string{"tomate"}})
+
+	err, ok := catalog.Validate().(models.ValidationErrors)
+	if !ok {
+		t.Fatalf("expected validation errors; got %v", err)
+	}
+	for _, field := range []string{"version", "recipes[1].uuid", "recipes[1].name", "recipes[1].ingredients"} {
+		if _, ok := err[field]; !ok {
+			t.Errorf("expected an error for %s; got %v", field, err)
+		}
+	}
+}
+
+// TestCatalogRoundTrip exports a catalog from one schema and imports it
+// into another, in both modes.
+func TestCatalogRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	source, err := database.Open(testhelpers.Schema(t))
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer source.Close()
+
+	target, err := database.Open(testhelpers.Schema(t))
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer target.Close()
+
+	demo, err := fixtures.Demo()
+	if err != nil {
+		t.Fatalf("error parsing the demo fixture. Err: %v", err)
+	}
+	if _, err := fixtures.Load(ctx, source, demo); err != nil {
+		t.Fatalf("error loading the demo fixture. Err: %v", err)
+	}
+
+	catalog := models.CatalogDto{Version: models.CatalogVersion}
+	err = source.ExportCatalog(ctx, models.CatalogVisitor{
+		Category: func(category models.Category) error {
+			catalog.Categories = append(catalog.Categories, models.NewCatalogCategoryDto(category))
+			return nil
+		},
+		Ingredient: func(ingredient models.Ingedient) error {
+			catalog.Ingredients = append(catalog.Ingredients, models.NewCatalogIngredientDto(ingredient))
+			return nil
+		},
+		Recipe: func(recipe models.CatalogRecipe) error {
+			catalog.Recipes = append(catalog.Recipes, models.NewCatalogRecipeDto(recipe))
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("error exporting. Err: %v", err)
+	}
+	if err := catalog.Validate(); err != nil {
+		t.Fatalf("expected the export to be a valid import; got %v", err)
+	}
+
+	summary, err := target.ImportCatalog(ctx, catalog.Catalog(), false)
+	if err != nil {
+		t.Fatalf("error importing. Err: %v", err)
+	}
+	if summary.Recipes.Created != len(demo.Recipes) || summary.Ingredients.Created != len(demo.Ingredients) {
+		t.Errorf("expected every row created; got %+v", summary)
+	}
+
+	summary, err = target.ImportCatalog(ctx, catalog.Catalog(), false)
+	if err != nil {
+		t.Fatalf("error importing again. Err: %v", err)
+	}
+	if summary.Recipes.Skipped != len(demo.Recipes) || summary.Recipes.Created != 0 {
+		t.Errorf("expected every recipe skipped; got %+v", summary)
+	}
+
+	summary, err = target.ImportCatalog(ctx, catalog.Catalog(), true)
+	if err != nil {
+		t.Fatalf("error importing with overwrite. Err: %v", err)
+	}
+	if summary.Recipes.Updated != len(demo.Recipes) {
+		t.Errorf("expected every recipe updated; got %+v", summary)
+	}
+
+	recipes, err := target.GetRecipes(ctx, models.RecipeFilter{})
+	if err != nil {
+		t.Fatalf("error listing recipes. Err: %v", err)
+	}
+	if len(recipes) != len(demo.Recipes) {
+		t.Errorf("expected %d recipes after the imports; got %d", len(demo.Recipes), len(recipes))
+	}
+}
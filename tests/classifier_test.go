@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/classifier"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name   string
+		recipe models.ClassifiableRecipe
+		want   []string
+	}{
+		{
+			"spicy meat",
+			models.ClassifiableRecipe{Name: "Frango apimentado", Ingredients: []string{"Peito de frango", "Pimenta dedo-de-moça"}},
+			[]string{models.TagSpicy},
+		},
+		{
+			"vegetarian dessert by category",
+			models.ClassifiableRecipe{Name: "Pudim", Category: "Sobremesas", Ingredients: []string{"Leite condensado", "Ovos"}},
+			[]string{models.TagVegetarian, models.TagDessert},
+		},
+		{
+			"dessert by sweet ingredients",
+			models.ClassifiableRecipe{Name: "Trufa", Ingredients: []string{"Chocolate meio amargo", "Açúcar", "Creme de leite"}},
+			[]string{models.TagVegetarian, models.TagDessert},
+		},
+		{
+			"black pepper is not spicy",
+			models.ClassifiableRecipe{Name: "Salada", Ingredients: []string{"Alface", "Pimenta-do-reino", "Mel"}},
+			[]string{models.TagVegetarian},
+		},
+		{
+			"no ingredients",
+			models.ClassifiableRecipe{Name: "Caldo de carne"},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		var got []string
+		for _, proposal := range classifier.Classify(c.recipe) {
+			if proposal.Reason == "" {
+				t.Errorf("%s: expected a reason for %s", c.name, proposal.Tag)
+			}
+			got = append(got, proposal.Tag)
+		}
+
+		if !slices.Equal(got, c.want) {
+			t.Errorf("%s: expected %v; got %v", c.name, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/httperr"
+	"gastro-galaxy-back/internal/models"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The JSON shapes below are the API contract with the frontend. Changing
+// them is a breaking change, update these tests only on purpose.
+
+func TestRecipeContract(t *testing.T) {
+	categoryId, url, long, minutes := 3, "https://example.com/p.jpg", "Asse por 20 minutos", 20
+
+	assertJSON(t, models.NewRecipeWithIngredientsDto(models.RecipeWithIngredients{
+		Recipe: models.Recipe{Id: 1, Uuid: "01890a5d-ac96-774b-bcce-b302099a8057", CategoryId: &categoryId, Name: "Pizza", Url: &url, Description: "Margherita", LongDescription: &long},
+		Ingredients: []models.Ingedient{
+			{Id: 2, Name: "Tomate", Amount: &long, Url: &url, IsAvailable: true},
+		},
+		Steps: []models.RecipeStep{
+			{Id: 4, RecipeId: 1, Position: 1, Text: "Asse", DurationMinutes: &minutes},
+		},
+		Tags: []string{"italiana", "vegetariana"},
+	}), `{
+		"recipe": {"id": 1, "uuid": "01890a5d-ac96-774b-bcce-b302099a8057", "category_id": 3, "name": "Pizza", "image_url": "https://example.com/p.jpg", "description": "Margherita", "long_description": "Asse por 20 minutos"},
+		"ingredients": [{"id": 2, "name": "Tomate", "amount": "Asse por 20 minutos", "image_url": "https://example.com/p.jpg", "is_available": true}],
+		"steps": [{"id": 4, "position": 1, "text": "Asse", "duration_minutes": 20}],
+		"tags": ["italiana", "vegetariana"]
+	}`)
+}
+
+func TestPublicStatsContract(t *testing.T) {
+	assertJSON(t, models.NewPublicStatsDto(models.PublicStats{
+		TotalRecipes:     10,
+		TotalCategories:  5,
+		TotalIngredients: 40,
+		NewestRecipes:    []models.Recipe{{Id: 10, Name: "Bolo", Description: "De cenoura"}},
+	}), `{"total_recipes": 10, "total_categories": 5, "total_ingredients": 40, "newest_recipes": [{"id": 10, "name": "Bolo", "description": "De cenoura"}]}`)
+}
+
+func TestPageContract(t *testing.T) {
+	assertJSON(t, models.NewPageDto([]models.RecipeDto{{Id: 21, Name: "Lasanha", Description: "Bolonhesa"}}, 2, 20, 41),
+		`{"items": [{"id": 21, "name": "Lasanha", "description": "Bolonhesa"}], "page": 2, "page_size": 20, "total": 41, "total_pages": 3}`)
+
+	assertJSON(t, models.NewPageDto[models.RecipeDto](nil, 1, 20, 0),
+		`{"items": [], "page": 1, "page_size": 20, "total": 0, "total_pages": 0}`)
+}
+
+func TestRecipeSearchResultContract(t *testing.T) {
+	assertJSON(t, models.NewRecipeSearchResultDtos([]models.RecipeSearchResult{{
+		Recipe:             models.Recipe{Id: 7, Name: "Bolo <b>de</b> fubá", Description: "Fofinho"},
+		Rank:               0.5,
+		NameHighlight:      "Bolo <b>de</b> " + models.HighlightStart + "fubá" + models.HighlightStop,
+		DescriptionSnippet: "Fofinho",
+	}}), `[{
+		"id": 7, "name": "Bolo <b>de</b> fubá", "description": "Fofinho", "rank": 0.5,
+		"highlight": {"name": "Bolo &lt;b&gt;de&lt;/b&gt; <mark>fubá</mark>", "description": "Fofinho"}
+	}]`)
+}
+
+func TestHomeContract(t *testing.T) {
+	assertJSON(t, models.NewHomeDto(models.Home{
+		Slots: map[string][]models.Recipe{
+			models.HomeSlotSeasonal: {{Id: 3, Name: "Canjica", Description: "Junina"}},
+		},
+	}), `{"hero": null, "seasonal": [{"id": 3, "name": "Canjica", "description": "Junina"}], "editors_choice": [], "recipe_of_the_day": null}`)
+}
+
+func TestPresignUploadContract(t *testing.T) {
+	assertJSON(t, models.PresignUploadDto{
+		Url:       "https://bucket.example.com/k",
+		Key:       "recipes/1/k.jpg",
+		Method:    "PUT",
+		Headers:   map[string]string{"Content-Type": "image/jpeg", "Content-Length": "2048"},
+		ExpiresAt: time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC),
+	}, `{"url": "https://bucket.example.com/k", "key": "recipes/1/k.jpg", "method": "PUT", "headers": {"Content-Type": "image/jpeg", "Content-Length": "2048"}, "expires_at": "2024-07-01T12:00:00Z"}`)
+}
+
+func TestCookableRecipeContract(t *testing.T) {
+	assertJSON(t, models.NewCookableRecipeDtos([]models.CookableRecipe{
+		{Recipe: models.Recipe{Id: 4, Name: "Omelete", Description: "Simples"}},
+		{Recipe: models.Recipe{Id: 5, Name: "Pudim", Description: "De leite"}, MissingIngredientIds: []int{8, 9}},
+	}), `[
+		{"id": 4, "name": "Omelete", "description": "Simples", "missing_count": 0, "missing_ingredient_ids": []},
+		{"id": 5, "name": "Pudim", "description": "De leite", "missing_count": 2, "missing_ingredient_ids": [8, 9]}
+	]`)
+}
+
+func TestErrorContract(t *testing.T) {
+	notFound := httperr.From(fmt.Errorf("get recipe: %w", database.ErrNotFound))
+	if notFound.Status != http.StatusNotFound {
+		t.Errorf("expected status 404 for database.ErrNotFound; got %d", notFound.Status)
+	}
+	assertJSON(t, notFound, `{"code": "not_found", "message": "Not found"}`)
+
+	assertJSON(t, httperr.New(http.StatusUnprocessableEntity, "name is required").WithDetails(map[string]string{"name": "required"}),
+		`{"code": "unprocessable_entity", "message": "name is required", "details": {"name": "required"}}`)
+
+	internal := httperr.From(errors.New("connection refused"))
+	if internal.Status != http.StatusInternalServerError {
+		t.Errorf("expected status 500 for an unknown error; got %d", internal.Status)
+	}
+	assertJSON(t, internal, `{"code": "internal_server_error", "message": "Internal Server Error"}`)
+}
+
+func TestRecipeInputDecoding(t *testing.T) {
+	expected := models.RecipeInputDto{
+		CategoryId:      2,
+		Name:            "Feijoada",
+		Url:             "https://example.com/f.jpg",
+		Description:     "Completa",
+		LongDescription: "Cozinhe o feijão",
+		IngedientIds:    []int{1, 2},
+	}
+
+	inputs := map[string]string{
+		"snake_case": `{"category_id": 2, "name": "Feijoada", "image_url": "https://example.com/f.jpg", "description": "Completa", "long_description": "Cozinhe o feijão", "ingredient_ids": [1, 2]}`,
+		"legacy":     `{"categoryId": 2, "name": "Feijoada", "url": "https://example.com/f.jpg", "description": "Completa", "longDescription": "Cozinhe o feijão", "ingedientIds": [1, 2]}`,
+		"string_ids": `{"category_id": "2", "name": "Feijoada", "image_url": "https://example.com/f.jpg", "description": "Completa", "long_description": "Cozinhe o feijão", "ingredient_ids": ["1", 2]}`,
+		"mixed":      `{"categoryId": 9, "category_id": 2, "name": "Feijoada", "url": "https://example.com/f.jpg", "description": "Completa", "long_description": "Cozinhe o feijão", "ingedientIds": [1, 2]}`,
+	}
+
+	for name, input := range inputs {
+		var dto models.RecipeInputDto
+		if err := json.Unmarshal([]byte(input), &dto); err != nil {
+			t.Fatalf("%s: error decoding recipe input. Err: %v", name, err)
+		}
+		if !reflect.DeepEqual(dto, expected) {
+			t.Errorf("%s: expected %+v; got %+v", name, expected, dto)
+		}
+	}
+}
+
+func TestIngredientInputDecoding(t *testing.T) {
+	inputs := map[string]string{
+		"snake_case": `{"name": "Sal", "amount": "1 pitada", "image_url": "https://example.com/s.jpg", "is_available": true}`,
+		"legacy":     `{"Name": "Sal", "Amount": "1 pitada", "Url": "https://example.com/s.jpg", "IsAvailable": true}`,
+	}
+
+	for name, input := range inputs {
+		var ingredient models.IngredientInputDto
+		if err := json.Unmarshal([]byte(input), &ingredient); err != nil {
+			t.Fatalf("%s: error decoding ingredient. Err: %v", name, err)
+		}
+		expected := models.IngredientInputDto{Name: "Sal", Amount: "1 pitada", Url: "https://example.com/s.jpg", IsAvailable: true}
+		if ingredient != expected {
+			t.Errorf("%s: expected %+v; got %+v", name, expected, ingredient)
+		}
+	}
+}
+
+func assertJSON(t *testing.T, v any, expected string) {
+	t.Helper()
+
+	actual, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("error marshalling %T. Err: %v", v, err)
+	}
+
+	var got, want any
+	json.Unmarshal(actual, &got)
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("invalid expected JSON. Err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %T to marshal as\n%s\ngot\n%s", v, expected, actual)
+	}
+}
+
+func TestInputValidation(t *testing.T) {
+	valid := models.RecipeInputDto{Name: "Pizza", CategoryId: 1, Url: "https://example.com/p.jpg", IngedientIds: models.IDs{1, 2}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected a valid recipe; got %v", err)
+	}
+
+	err := models.RecipeInputDto{Name: " ", CategoryId: -1, Url: "not a url"}.Validate()
+	assertJSON(t, httperr.From(err), `{
+		"code": "invalid_input",
+		"message": "category_id must not be negative; image_url must be an absolute http or https URL; ingredient_ids must not be empty; name is required",
+		"details": {
+			"name": "is required",
+			"category_id": "must not be negative",
+			"image_url": "must be an absolute http or https URL",
+			"ingredient_ids": "must not be empty"
+		}
+	}`)
+
+	if err := (models.IngredientInputDto{Name: "Tomate", Url: "ftp://example.com/t.jpg"}).Validate(); err == nil {
+		t.Error("expected an ftp image url to be rejected")
+	}
+}
+
+func TestPrepLogValidation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+
+	err := models.PrepLogInputDto{
+		PreparedAt:   &later,
+		Temperatures: []models.TemperatureReading{{Stage: "cooking", Celsius: 750}},
+	}.Validate(now)
+
+	var validation models.ValidationErrors
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected validation errors; got %v", err)
+	}
+	for _, field := range []string{"prepared_by", "prepared_at", "batch_size", "temperatures"} {
+		if validation[field] == "" {
+			t.Errorf("expected %s to be invalid", field)
+		}
+	}
+
+	valid := models.PrepLogInputDto{PreparedBy: "Ana", BatchSize: 4, BatchUnit: "kg", Temperatures: []models.TemperatureReading{{Stage: "cooling", Celsius: 4}}}
+	if err := valid.Validate(now); err != nil {
+		t.Errorf("expected a valid prep log; got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"testing"
+	"time"
+)
+
+// TestDailyRecipeRotation checks the choice of the recipe of the day.
+func TestDailyRecipeRotation(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`INSERT INTO recipe (id, name, description) VALUES (1, 'Pudim', 'De leite'), (2, 'Brigadeiro', 'De panela')`); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	today := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := db.GetDailyRecipe(ctx, today)
+	if err != nil || first == nil {
+		t.Fatalf("expected a recipe of the day; got %v, %v", first, err)
+	}
+
+	again, err := db.GetDailyRecipe(ctx, today)
+	if err != nil || again.Id != first.Id {
+		t.Errorf("expected the same recipe for the same day; got %v, %v", again, err)
+	}
+
+	tomorrow, err := db.GetDailyRecipe(ctx, today.AddDate(0, 0, 1))
+	if err != nil || tomorrow.Id == first.Id {
+		t.Errorf("expected a different recipe the next day; got %v, %v", tomorrow, err)
+	}
+
+	if err := db.PinDailyRecipe(ctx, today, tomorrow.Id); err != nil {
+		t.Fatalf("error pinning recipe. Err: %v", err)
+	}
+
+	pinned, err := db.GetDailyRecipe(ctx, today)
+	if err != nil || pinned.Id != tomorrow.Id {
+		t.Errorf("expected the pinned recipe; got %v, %v", pinned, err)
+	}
+}
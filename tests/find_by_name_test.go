@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/memory"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"testing"
+)
+
+// TestLookupsMatchTextLiterally checks that the text the Telegram bot
+// passes on is matched as typed, not as a LIKE pattern.
+func TestLookupsMatchTextLiterally(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		checkLiteralLookups(t, memory.New())
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		db, err := database.Open(testhelpers.Schema(t))
+		if err != nil {
+			t.Fatalf("error opening database service. Err: %v", err)
+		}
+		defer db.Close()
+
+		checkLiteralLookups(t, db)
+	})
+}
+
+func checkLiteralLookups(t *testing.T, db database.Service) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	for _, name := range []string{"Pizza Margherita", "Bolo 100% Integral", "Pão_de_Queijo"} {
+		if _, err := db.InsertRecipe(ctx, name, "", "", "", 1, nil); err != nil {
+			t.Fatalf("error inserting recipe. Err: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"%", []string{"Bolo 100% Integral"}},
+		{"_", []string{"Pão_de_Queijo"}},
+		{`\`, nil},
+		{"margherita", []string{"Pizza Margherita"}},
+	}
+
+	for _, tt := range tests {
+		recipes, err := db.FindRecipesByName(ctx, tt.name, 10)
+		if err != nil {
+			t.Fatalf("error finding recipes by %q. Err: %v", tt.name, err)
+		}
+
+		var got []string
+		for _, recipe := range recipes {
+			got = append(got, recipe.Name)
+		}
+
+		if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+			t.Errorf("expected %q to find %v; got %v", tt.name, tt.want, got)
+		}
+	}
+
+	for _, category := range []string{"%", "_izzas"} {
+		recipe, err := db.GetRandomRecipe(ctx, category)
+		if err != nil {
+			t.Fatalf("error picking a random recipe. Err: %v", err)
+		}
+
+		if recipe != nil {
+			t.Errorf("expected no recipe in category %q; got %+v", category, recipe)
+		}
+	}
+
+	if recipe, err := db.GetRandomRecipe(ctx, "pizzas"); err != nil || recipe == nil {
+		t.Errorf("expected a recipe in category pizzas; got %+v, %v", recipe, err)
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/fixtures"
+	"gastro-galaxy-back/internal/models"
+	"reflect"
+	"testing"
+)
+
+// recordingStore gives out sequential ids and remembers the recipes.
+type recordingStore struct {
+	database.CategoryRepository
+	database.IngredientRepository
+	database.RecipeRepository
+	next    int
+	recipes map[string][]int
+}
+
+func (s *recordingStore) InsertCategory(ctx context.Context, name string) (int, error) {
+	s.next++
+	return s.next, nil
+}
+
+func (s *recordingStore) InsertIngredient(ctx context.Context, name string, amount string, url string, isAvailable bool, quantity *float64, unit string, nutrition *models.Nutrition) (int, error) {
+	s.next++
+	return s.next, nil
+}
+
+func (s *recordingStore) InsertRecipe(ctx context.Context, name string, description string, longDescription string, url string, categoryId int, ingredientIds []int) (int, error) {
+	s.next++
+	s.recipes[name] = append([]int{categoryId}, ingredientIds...)
+	return s.next, nil
+}
+
+func TestFixtures(t *testing.T) {
+	yamlFixture := `
+categories:
+  - name: Massas
+ingredients:
+  - name: Tomate
+    amount: 2 un
+  - name: Espaguete
+recipes:
+  - name: Espaguete ao sugo
+    category: Massas
+    ingredients: [Espaguete, Tomate]
+`
+	jsonFixture := `{
+		"categories": [{"name": "Massas"}],
+		"ingredients": [{"name": "Tomate", "amount": "2 un"}, {"name": "Espaguete"}],
+		"recipes": [{"name": "Espaguete ao sugo", "category": "Massas", "ingredients": ["Espaguete", "Tomate"]}]
+	}`
+
+	fromYAML, err := fixtures.Parse([]byte(yamlFixture), "yaml")
+	if err != nil {
+		t.Fatalf("error parsing YAML fixture. Err: %v", err)
+	}
+	fromJSON, err := fixtures.Parse([]byte(jsonFixture), "json")
+	if err != nil {
+		t.Fatalf("error parsing JSON fixture. Err: %v", err)
+	}
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("expected both formats to give the same fixture; got %+v and %+v", fromYAML, fromJSON)
+	}
+
+	store := &recordingStore{recipes: map[string][]int{}}
+	if _, err := fixtures.Load(context.Background(), store, fromYAML); err != nil {
+		t.Fatalf("error loading fixture. Err: %v", err)
+	}
+	// Massas is 1, Tomate 2 and Espaguete 3.
+	if got := store.recipes["Espaguete ao sugo"]; !reflect.DeepEqual(got, []int{1, 3, 2}) {
+		t.Errorf("expected the recipe in category 1 with ingredients 3 and 2; got %v", got)
+	}
+
+	dangling := fromYAML
+	dangling.Recipes = []fixtures.Recipe{{Name: "Pizza", Category: "Massas", Ingredients: []string{"Queijo"}}}
+	store = &recordingStore{recipes: map[string][]int{}}
+	if _, err := fixtures.Load(context.Background(), store, dangling); err == nil {
+		t.Error("expected an unknown ingredient to be refused")
+	}
+	if store.next != 0 {
+		t.Errorf("expected nothing inserted from a fixture with a dangling reference; got %d rows", store.next)
+	}
+
+	if _, err := fixtures.Demo(); err != nil {
+		t.Errorf("error parsing the demo fixture. Err: %v", err)
+	}
+}
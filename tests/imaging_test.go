@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"bytes"
+	"gastro-galaxy-back/internal/imaging"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnailKeepsAspectRatioAndFormat(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+	src.Set(10, 10, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatalf("error encoding source image. Err: %v", err)
+	}
+
+	img, format, err := imaging.Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("error decoding image. Err: %v", err)
+	}
+
+	data, contentType, err := imaging.Encode(imaging.Thumbnail(img, 320), format)
+	if err != nil {
+		t.Fatalf("error encoding thumbnail. Err: %v", err)
+	}
+	if contentType != "image/png" {
+		t.Errorf("expected a png thumbnail for a png source; got %s", contentType)
+	}
+
+	thumb, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error decoding thumbnail. Err: %v", err)
+	}
+	if thumb.Width != 320 || thumb.Height != 160 {
+		t.Errorf("expected a 320x160 thumbnail; got %dx%d", thumb.Width, thumb.Height)
+	}
+}
+
+func TestPlaceholderIsStablePerName(t *testing.T) {
+	first, err := imaging.Placeholder("Tomato", 64)
+	if err != nil {
+		t.Fatalf("error rendering placeholder. Err: %v", err)
+	}
+
+	second, _ := imaging.Placeholder("Tomato", 64)
+	if !bytes.Equal(first, second) {
+		t.Error("expected the same placeholder for the same name")
+	}
+
+	other, _ := imaging.Placeholder("Basil", 64)
+	if bytes.Equal(first, other) {
+		t.Error("expected different placeholders for different names")
+	}
+
+	cfg, err := png.DecodeConfig(bytes.NewReader(first))
+	if err != nil {
+		t.Fatalf("error decoding placeholder. Err: %v", err)
+	}
+	if cfg.Width != 64 || cfg.Height != 64 {
+		t.Errorf("expected a 64x64 placeholder; got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+// TestEncodeDropsMetadata checks that re-encoding an upload, as storeImage
+// does, leaves its EXIF segment behind.
+func TestEncodeDropsMetadata(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatalf("error encoding source image. Err: %v", err)
+	}
+
+	// Put an APP1 Exif segment right after the SOI marker.
+	exif := append([]byte{0xFF, 0xE1, 0x00, 0x10}, []byte("Exif\x00\x00GPS-DATA")...)
+	src := append(append([]byte{0xFF, 0xD8}, exif...), buf.Bytes()[2:]...)
+
+	img, format, err := imaging.Decode(src)
+	if err != nil {
+		t.Fatalf("error decoding image. Err: %v", err)
+	}
+
+	data, _, err := imaging.Encode(img, format)
+	if err != nil {
+		t.Fatalf("error encoding image. Err: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("Exif")) || bytes.Contains(data, []byte("GPS-DATA")) {
+		t.Errorf("expected the re-encoded image to have no EXIF metadata")
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/importer"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+const recipePage = `<!DOCTYPE html>
+<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "WebSite", "name": "Receitas"}</script>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "BreadcrumbList"},
+    {
+      "@type": ["Recipe"],
+      "name": "Bolo de cenoura",
+      "description": "Bolo fofinho com cobertura de <b>chocolate</b> &amp; granulado.",
+      "image": [{"@type": "ImageObject", "url": "/img/bolo.jpg"}],
+      "recipeCategory": "Sobremesas, Bolos",
+      "recipeIngredient": ["3 cenouras médias", "2 xícaras de farinha de trigo", "1/2 colher de chá de sal", "Açúcar a gosto"],
+      "recipeInstructions": [
+        {"@type": "HowToSection", "itemListElement": [
+          {"@type": "HowToStep", "text": "Bata as cenouras no liquidificador."},
+          {"@type": "HowToStep", "text": "Misture a farinha."}
+        ]},
+        "Asse por 40 minutos."
+      ]
+    }
+  ]
+}
+</script>
+</head><body></body></html>`
+
+func TestImportJSONLD(t *testing.T) {
+	pageURL, _ := url.Parse("https://receitas.example/bolo")
+
+	recipe, err := importer.JSONLD{}.Parse([]byte(recipePage), pageURL)
+	if err != nil {
+		t.Fatalf("error parsing page. Err: %v", err)
+	}
+
+	expected := &importer.Recipe{
+		Name:            "Bolo de cenoura",
+		Description:     "Bolo fofinho com cobertura de chocolate & granulado.",
+		LongDescription: "Bata as cenouras no liquidificador.\nMisture a farinha.\nAsse por 40 minutos.",
+		ImageUrl:        "https://receitas.example/img/bolo.jpg",
+		Categories:      []string{"Sobremesas", "Bolos"},
+		Ingredients: []importer.Ingredient{
+			{Name: "cenouras médias", Amount: "3"},
+			{Name: "farinha de trigo", Amount: "2 xícaras"},
+			{Name: "sal", Amount: "1/2 colher de chá"},
+			{Name: "Açúcar a gosto"},
+		},
+	}
+	if !reflect.DeepEqual(recipe, expected) {
+		t.Errorf("expected %+v; got %+v", expected, recipe)
+	}
+
+	if _, err := (importer.JSONLD{}).Parse([]byte(`<html><body>Sem receita</body></html>`), pageURL); !errors.Is(err, importer.ErrNoRecipe) {
+		t.Errorf("expected ErrNoRecipe for a page without a recipe; got %v", err)
+	}
+}
+
+func TestImportRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(recipePage))
+	}))
+	defer server.Close()
+
+	if _, err := importer.New().Import(context.Background(), server.URL); !errors.Is(err, importer.ErrForbiddenAddress) {
+		t.Errorf("expected a loopback address to be refused; got %v", err)
+	}
+
+	for _, rawURL := range []string{"http://100.64.0.1/", "http://[::ffff:127.0.0.1]/", "http://[64:ff9b::a00:1]/"} {
+		if _, err := importer.New().Import(context.Background(), rawURL); !errors.Is(err, importer.ErrForbiddenAddress) {
+			t.Errorf("expected %s to be refused; got %v", rawURL, err)
+		}
+	}
+}
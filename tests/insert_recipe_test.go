@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/models"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestInsertRecipeIsAtomic checks that every ingredient is linked exactly
+// once and that a failed link leaves no recipe behind.
+func TestInsertRecipeIsAtomic(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`INSERT INTO ingredient (id, name) VALUES (1, 'Sal'), (2, 'Ovo')`); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	id, err := db.InsertRecipe(ctx, "Omelete", "Simples", "", "", 1, []int{1, 2})
+	if err != nil {
+		t.Fatalf("error inserting recipe. Err: %v", err)
+	}
+
+	var links int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM ingredient_recipe WHERE recipe_id = $1`, id).Scan(&links); err != nil {
+		t.Fatalf("error counting links. Err: %v", err)
+	}
+	if links != 2 {
+		t.Errorf("expected 2 ingredient links; got %d", links)
+	}
+
+	if _, err := db.InsertRecipe(ctx, "Fantasma", "Não existe", "", "", 1, []int{1, 999}); err == nil {
+		t.Fatalf("expected an error linking a missing ingredient")
+	}
+
+	var ghosts int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM recipe WHERE name = 'Fantasma'`).Scan(&ghosts); err != nil {
+		t.Fatalf("error counting recipes. Err: %v", err)
+	}
+	if ghosts != 0 {
+		t.Errorf("expected the failed insert to be rolled back; found %d recipes", ghosts)
+	}
+}
+
+// TestReplaceRecipeIngredients checks that the ingredient list of a recipe
+// is replaced as a whole.
+func TestReplaceRecipeIngredients(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	fixtures := `
+		INSERT INTO ingredient (id, name) VALUES (1, 'Sal'), (2, 'Ovo'), (3, 'Leite');
+		INSERT INTO recipe (id, name, description) VALUES (1, 'Omelete', 'Simples');
+		INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES (1, 1), (2, 1), (2, 1);
+	`
+	if _, err := conn.Exec(fixtures); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if err := db.ReplaceRecipeIngredients(ctx, 1, []int{2, 3, 3}); err != nil {
+		t.Fatalf("error replacing ingredients. Err: %v", err)
+	}
+
+	recipe, err := db.GetRecipeWithIngredients(ctx, 1)
+	if err != nil {
+		t.Fatalf("error reading recipe. Err: %v", err)
+	}
+
+	var ids []int
+	for _, ingredient := range recipe.Ingredients {
+		ids = append(ids, ingredient.Id)
+	}
+	sort.Ints(ids)
+	if !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Errorf("expected ingredients [2 3]; got %v", ids)
+	}
+
+	if err := db.ReplaceRecipeIngredients(ctx, 1, []int{999}); !errors.Is(err, database.ErrUnknownReference) {
+		t.Errorf("expected ErrUnknownReference; got %v", err)
+	}
+
+	if err := db.ReplaceRecipeIngredients(ctx, 42, nil); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected ErrNotFound; got %v", err)
+	}
+}
+
+// TestUpdateRecipeTwice checks that updating a recipe replaces its
+// ingredients, so repeating an update leaves one row per ingredient.
+func TestUpdateRecipeTwice(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	fixtures := `
+		INSERT INTO ingredient (id, name) VALUES (1, 'Sal'), (2, 'Ovo');
+		INSERT INTO recipe (id, name, description) VALUES (1, 'Omelete', 'Simples');
+		INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES (1, 1);
+	`
+	if _, err := conn.Exec(fixtures); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	for range 2 {
+		if err := db.UpdateRecipe(ctx, 1, "Omelete", "Com sal", "", []int{1, 2}); err != nil {
+			t.Fatalf("error updating recipe. Err: %v", err)
+		}
+	}
+
+	var rows, distinct int
+	if err := conn.QueryRow(`SELECT count(*), count(DISTINCT ingredient_id) FROM ingredient_recipe WHERE recipe_id = 1`).Scan(&rows, &distinct); err != nil {
+		t.Fatalf("error counting ingredients. Err: %v", err)
+	}
+
+	if rows != 2 || distinct != 2 {
+		t.Errorf("expected one row for each of the 2 ingredients; got %d rows for %d ingredients", rows, distinct)
+	}
+}
+
+// TestDeleteRecipeKeepsPrepLogs checks that the food safety log of a recipe
+// survives it, with the recipe's name.
+func TestDeleteRecipeKeepsPrepLogs(t *testing.T) {
+	db, err := database.Open(testhelpers.Schema(t))
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	recipeId, err := db.InsertRecipe(ctx, "Caldo de Feijão", "Com bacon", "", "", 5, nil)
+	if err != nil {
+		t.Fatalf("error inserting recipe. Err: %v", err)
+	}
+
+	preparedAt := time.Now().Add(-time.Hour)
+	if _, err := db.InsertPrepLog(ctx, models.PrepLog{RecipeId: &recipeId, PreparedBy: "Ana", PreparedAt: preparedAt, BatchSize: 5, BatchUnit: "l"}); err != nil {
+		t.Fatalf("error inserting prep log. Err: %v", err)
+	}
+
+	if err := db.DeleteRecipe(ctx, recipeId); err != nil {
+		t.Fatalf("error deleting recipe. Err: %v", err)
+	}
+
+	logs, err := db.GetPrepLogs(ctx, nil, preparedAt.Add(-time.Minute), time.Now())
+	if err != nil {
+		t.Fatalf("error reading prep logs. Err: %v", err)
+	}
+
+	if len(logs) != 1 || logs[0].RecipeId != nil || logs[0].RecipeName != "Caldo de Feijão" {
+		t.Errorf("expected the prep log to be kept with the recipe's name; got %+v", logs)
+	}
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gastro-galaxy-back/internal/jsonschema"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/service"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := jsonschema.Compile([]byte(`{
+		"type": "object",
+		"properties": {
+			"station": {"type": "string", "enum": ["grill", "pastry"]},
+			"sku": {"type": "string", "pattern": "^[A-Z]{2}-\\d{3}$"},
+			"portions": {"type": "integer", "minimum": 1},
+			"allergens": {"type": "array", "items": {"type": "string"}, "maxItems": 3}
+		},
+		"required": ["station"],
+		"additionalProperties": false
+	}`))
+	if err != nil {
+		t.Fatalf("error compiling schema. Err: %v", err)
+	}
+
+	cases := []struct {
+		value string
+		ok    bool
+	}{
+		{`{"station": "grill", "sku": "PZ-001", "portions": 4, "allergens": ["gluten"]}`, true},
+		{`{"sku": "PZ-001"}`, false},
+		{`{"station": "bar"}`, false},
+		{`{"station": "grill", "sku": "pz1"}`, false},
+		{`{"station": "grill", "portions": 1.5}`, false},
+		{`{"station": "grill", "allergens": ["a", "b", "c", "d"]}`, false},
+		{`{"station": "grill", "plating": "round"}`, false},
+		{`"grill"`, false},
+	}
+
+	for _, c := range cases {
+		if err := schema.Validate([]byte(c.value)); (err == nil) != c.ok {
+			t.Errorf("Validate(%s): expected ok %v; got %v", c.value, c.ok, err)
+		}
+	}
+
+	for _, bad := range []string{`{"type": "text"}`, `{"$ref": "#/defs/x"}`, `{"pattern": "("}`, `[]`} {
+		if _, err := jsonschema.Compile([]byte(bad)); err == nil {
+			t.Errorf("expected %s to be refused", bad)
+		}
+	}
+}
+
+type customFieldStore struct {
+	service.CustomFieldStore
+	fields []models.CustomField
+	values map[string]json.RawMessage
+}
+
+func (s *customFieldStore) GetCustomFields(ctx context.Context) ([]models.CustomField, error) {
+	return s.fields, nil
+}
+
+func (s *customFieldStore) SetRecipeCustomFields(ctx context.Context, recipeId int, values map[string]json.RawMessage) error {
+	s.values = values
+	return nil
+}
+
+func TestCustomFieldValues(t *testing.T) {
+	store := &customFieldStore{fields: []models.CustomField{
+		{Name: "station", Schema: json.RawMessage(`{"type": "string", "enum": ["grill", "pastry"]}`)},
+		{Name: "plating", Schema: json.RawMessage(`{"type": "string", "maxLength": 200}`)},
+	}}
+	fields := service.NewCustomFieldService(store)
+	ctx := context.Background()
+
+	var errs models.ValidationErrors
+	_, err := fields.SetRecipeValues(ctx, 1, map[string]json.RawMessage{"station": json.RawMessage(`"bar"`), "sku": json.RawMessage(`"X"`)})
+	if !errors.As(err, &errs) || errs["station"] == "" || errs["sku"] == "" {
+		t.Fatalf("expected errors on station and sku; got %v", err)
+	}
+	if store.values != nil {
+		t.Fatal("expected nothing to be stored")
+	}
+
+	values, err := fields.SetRecipeValues(ctx, 1, map[string]json.RawMessage{"station": json.RawMessage(`"grill"`), "plating": json.RawMessage(`null`)})
+	if err != nil {
+		t.Fatalf("error setting values. Err: %v", err)
+	}
+	if len(values) != 1 || string(store.values["station"]) != `"grill"` {
+		t.Errorf("expected only the station to be stored; got %v", store.values)
+	}
+}
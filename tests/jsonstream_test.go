@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"encoding/json"
+	"gastro-galaxy-back/internal/jsonstream"
+	"gastro-galaxy-back/internal/models"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestArrayWriter(t *testing.T) {
+	var sb strings.Builder
+	stream := jsonstream.NewArrayWriter(&sb)
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("error closing stream. Err: %v", err)
+	}
+	if strings.TrimSpace(sb.String()) != "[]" {
+		t.Errorf("expected empty array; got %q", sb.String())
+	}
+
+	sb.Reset()
+	stream = jsonstream.NewArrayWriter(&sb)
+	for i := 1; i <= 3; i++ {
+		stream.Write(models.Recipe{Id: i})
+	}
+	stream.Close()
+
+	var recipes []models.Recipe
+	if err := json.Unmarshal([]byte(sb.String()), &recipes); err != nil {
+		t.Fatalf("error decoding streamed array. Err: %v", err)
+	}
+	if len(recipes) != 3 || recipes[2].Id != 3 {
+		t.Errorf("expected 3 recipes in order; got %v", recipes)
+	}
+}
+
+// TestArrayWriterMemoryStaysFlat streams 100k rows and checks the live heap
+// doesn't grow with the number of rows written.
+func TestArrayWriterMemoryStaysFlat(t *testing.T) {
+	const rows = 100_000
+	const maxGrowth = 1 << 20
+
+	var before, during runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	longDescription := strings.Repeat("l", 500)
+
+	stream := jsonstream.NewArrayWriter(io.Discard)
+	for i := 0; i < rows; i++ {
+		recipe := models.Recipe{
+			Id:              i,
+			Name:            "Recipe",
+			Description:     strings.Repeat("d", 100),
+			LongDescription: &longDescription,
+		}
+		if err := stream.Write(recipe); err != nil {
+			t.Fatalf("error writing row %d. Err: %v", i, err)
+		}
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&during)
+	stream.Close()
+
+	if growth := int64(during.HeapAlloc) - int64(before.HeapAlloc); growth > maxGrowth {
+		t.Errorf("expected heap to stay flat; grew %d bytes while streaming %d rows", growth, rows)
+	}
+}
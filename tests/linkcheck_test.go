@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"context"
+	"gastro-galaxy-back/internal/linkcheck"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLinkCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok.jpg", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/get-only.jpg", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checker := linkcheck.New(nil, time.Hour)
+	ctx := context.Background()
+
+	if err := checker.Check(ctx, server.URL+"/ok.jpg"); err != nil {
+		t.Errorf("expected a working link; got %v", err)
+	}
+	if err := checker.Check(ctx, server.URL+"/get-only.jpg"); err != nil {
+		t.Errorf("expected a GET fallback when HEAD is not allowed; got %v", err)
+	}
+	if err := checker.Check(ctx, server.URL+"/missing.jpg"); err == nil || err.Error() != "404 Not Found" {
+		t.Errorf("expected 404 Not Found for a missing image; got %v", err)
+	}
+}
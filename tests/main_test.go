@@ -0,0 +1,11 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testhelpers.Run(m))
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/models"
+	"testing"
+	"time"
+)
+
+func TestParseISOWeek(t *testing.T) {
+	cases := map[string]string{
+		"2024-W30": "2024-07-22",
+		"2024-W01": "2024-01-01",
+		"2021-W01": "2021-01-04",
+		"2020-W53": "2020-12-28",
+		"2025-W01": "2024-12-30",
+	}
+
+	for input, monday := range cases {
+		week, err := models.ParseISOWeek(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", input, err)
+			continue
+		}
+		if got := week.Monday().Format(time.DateOnly); got != monday {
+			t.Errorf("%s: expected the week to start on %s; got %s", input, monday, got)
+		}
+		if week.String() != input {
+			t.Errorf("%s: expected the week to print as itself; got %s", input, week)
+		}
+	}
+
+	for _, input := range []string{"", "2024-30", "2024-W00", "2021-W53", "2024-W3", "2024-W300", "week"} {
+		if _, err := models.ParseISOWeek(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestMealPlanWeekDto(t *testing.T) {
+	week, _ := models.ParseISOWeek("2024-W30")
+
+	dto := models.NewMealPlanWeekDto(week, []models.Meal{
+		{Date: time.Date(2024, 7, 22, 0, 0, 0, 0, time.UTC), Slot: models.MealSlotDinner, Recipe: models.Recipe{Id: 1}},
+		{Date: time.Date(2024, 7, 28, 0, 0, 0, 0, time.UTC), Slot: models.MealSlotBreakfast, Recipe: models.Recipe{Id: 2}},
+	})
+
+	if len(dto.Days) != 7 || dto.Days[0].Date != "2024-07-22" || dto.Days[6].Date != "2024-07-28" {
+		t.Fatalf("expected the days from Monday to Sunday; got %+v", dto.Days)
+	}
+
+	if dto.Days[0].Dinner == nil || dto.Days[0].Dinner.Id != 1 || dto.Days[0].Lunch != nil {
+		t.Errorf("expected recipe 1 for Monday's dinner only; got %+v", dto.Days[0])
+	}
+
+	if dto.Days[6].Breakfast == nil || dto.Days[6].Breakfast.Id != 2 {
+		t.Errorf("expected recipe 2 for Sunday's breakfast; got %+v", dto.Days[6])
+	}
+}
@@ -0,0 +1,175 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/memory"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/server"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMemoryStore checks that the in-memory database answers like the
+// Postgres one where handlers depend on it: the errors of deletes and
+// unknown references, and the contents of shopping lists and searches.
+func TestMemoryStore(t *testing.T) {
+	db := memory.New()
+	ctx := context.Background()
+
+	tomato, _ := db.InsertIngredient(ctx, "Tomate", "2 un", "", false, nil, "", nil)
+	salt, _ := db.InsertIngredient(ctx, "Sal", "", "", true, nil, "", nil)
+
+	recipe, err := db.InsertRecipe(ctx, "Molho de Tomate", "Molho simples para massas", "", "", 3, []int{tomato, salt})
+	if err != nil {
+		t.Fatalf("error inserting recipe. Err: %v", err)
+	}
+
+	if _, err := db.InsertRecipe(ctx, "Sopa", "", "", "", 3, []int{999}); !errors.Is(err, database.ErrUnknownReference) {
+		t.Errorf("expected ErrUnknownReference for an unknown ingredient; got %v", err)
+	}
+
+	if err := db.DeleteIngredient(ctx, tomato, false); !errors.Is(err, database.ErrInUse) {
+		t.Errorf("expected ErrInUse deleting a used ingredient; got %v", err)
+	}
+
+	if err := db.DeleteCategory(ctx, 3); !errors.Is(err, database.ErrInUse) {
+		t.Errorf("expected ErrInUse deleting a used category; got %v", err)
+	}
+
+	id, err := db.CreateShoppingList(ctx, []int{recipe})
+	if err != nil {
+		t.Fatalf("error creating shopping list. Err: %v", err)
+	}
+
+	list, _ := db.GetShoppingList(ctx, id)
+	if len(list.Items) != 1 || list.Items[0].Name != "Tomate" {
+		t.Errorf("expected only the unavailable ingredient on the list; got %+v", list.Items)
+	}
+
+	results, _ := db.SearchRecipes(ctx, "MASSA -pizza", 10, 0)
+	if len(results) != 1 || results[0].DescriptionSnippet != "Molho simples para "+models.HighlightStart+"massas"+models.HighlightStop {
+		t.Errorf("expected the recipe to match its description; got %+v", results)
+	}
+
+	if _, err := db.InsertPrepLog(ctx, models.PrepLog{RecipeId: &recipe, PreparedBy: "Ana", PreparedAt: time.Now(), BatchSize: 2}); err != nil {
+		t.Fatalf("error inserting prep log. Err: %v", err)
+	}
+
+	if err := db.DeleteRecipe(ctx, recipe); err != nil {
+		t.Fatalf("error deleting recipe. Err: %v", err)
+	}
+
+	logs, _ := db.GetPrepLogs(ctx, nil, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if len(logs) != 1 || logs[0].RecipeId != nil || logs[0].RecipeName != "Molho de Tomate" {
+		t.Errorf("expected the prep log to outlive its recipe; got %+v", logs)
+	}
+
+	if deleted, err := db.GetRecipeWithIngredients(ctx, recipe); deleted != nil || err != nil {
+		t.Errorf("expected no recipe after deleting it; got %+v, %v", deleted, err)
+	}
+
+	if err := db.DeleteIngredient(ctx, tomato, false); err != nil {
+		t.Errorf("expected the ingredient to be free after its recipe was deleted; got %v", err)
+	}
+}
+
+// TestMemoryDriver runs the handlers against DB_DRIVER=memory.
+func TestMemoryDriver(t *testing.T) {
+	t.Setenv("DB_DRIVER", "memory")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, httpServer := server.NewServer(ctx)
+	ts := httptest.NewServer(httpServer.Handler)
+	defer ts.Close()
+
+	ingredient := postCreated(t, ts.URL+"/v1/ingredient", `{"name": "Polvilho"}`)
+	created := postCreated(t, ts.URL+"/v1/recipe", fmt.Sprintf(`{"name": "Pão de Queijo", "category_id": 5, "ingredient_ids": [%d]}`, ingredient))
+
+	resp, err := http.Get(fmt.Sprintf("%s/v1/recipe/%d", ts.URL, created))
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var recipe models.RecipeWithIngredientsDto
+	if err := json.NewDecoder(resp.Body).Decode(&recipe); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || recipe.Recipe.Name != "Pão de Queijo" {
+		t.Errorf("expected the created recipe; got %v %+v", resp.Status, recipe)
+	}
+
+	for range 2 {
+		update := fmt.Sprintf(`{"name": "Pão de Queijo", "category_id": 5, "ingredient_ids": [%d]}`, ingredient)
+
+		req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/v1/recipe/%d", ts.URL, created), strings.NewReader(update))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error making request to server. Err: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("expected status No Content updating the recipe; got %v", resp.Status)
+		}
+	}
+
+	resp, err = http.Get(fmt.Sprintf("%s/v1/recipe/%d", ts.URL, created))
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	recipe = models.RecipeWithIngredientsDto{}
+	if err := json.NewDecoder(resp.Body).Decode(&recipe); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+
+	if len(recipe.Ingredients) != 1 {
+		t.Errorf("expected one ingredient after updating the recipe twice; got %+v", recipe.Ingredients)
+	}
+
+	resp, err = http.Get(ts.URL + "/v1/recipe/999")
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status Not Found for an unknown recipe; got %v", resp.Status)
+	}
+}
+
+// postCreated posts body to url and returns the id of the created row.
+func postCreated(t *testing.T, url string, body string) int {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status Created; got %v", resp.Status)
+	}
+
+	var created models.CreatedDto
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+
+	return created.Id
+}
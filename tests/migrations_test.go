@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/migrations"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestMigrationsAreNumberedInOrder(t *testing.T) {
+	all, err := migrations.All()
+	if err != nil {
+		t.Fatalf("error loading migrations. Err: %v", err)
+	}
+
+	for i, m := range all {
+		if m.Version != i+1 {
+			t.Errorf("expected migration %d to have version %d; got %d (%s)", i, i+1, m.Version, m.Name)
+		}
+	}
+}
+
+func TestSchemaCompatibility(t *testing.T) {
+	cases := []struct {
+		name                            string
+		latest, version, compatibleFrom int
+		ok                              bool
+	}{
+		{"same version", 10, 10, 0, true},
+		{"database behind", 10, 9, 0, false},
+		{"newer compatible migration", 10, 11, 10, true},
+		{"newer breaking migration", 10, 12, 11, false},
+		{"compatible with older builds", 10, 11, 8, true},
+	}
+
+	for _, c := range cases {
+		err := migrations.Compatible(c.latest, c.version, c.compatibleFrom)
+		if c.ok && err != nil {
+			t.Errorf("%s: expected compatible; got %v", c.name, err)
+		}
+		if !c.ok && !errors.Is(err, migrations.ErrIncompatibleSchema) {
+			t.Errorf("%s: expected ErrIncompatibleSchema; got %v", c.name, err)
+		}
+	}
+}
+
+func TestMigrationsCompatibleWithThemselves(t *testing.T) {
+	all, err := migrations.All()
+	if err != nil {
+		t.Fatalf("error loading migrations. Err: %v", err)
+	}
+
+	for _, m := range all {
+		if m.CompatibleFrom < 1 || m.CompatibleFrom > m.Version {
+			t.Errorf("expected migration %04d_%s to be compatible from a version between 1 and %d; got %d", m.Version, m.Name, m.Version, m.CompatibleFrom)
+		}
+	}
+}
+
+// TestMigrationsRoundTrip reverts every migration and applies them again.
+func TestMigrationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := pgxpool.New(ctx, testhelpers.Schema(t))
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer db.Close()
+
+	latest, err := migrations.Latest()
+	if err != nil {
+		t.Fatalf("error loading migrations. Err: %v", err)
+	}
+
+	if err := migrations.Down(ctx, db, latest); err != nil {
+		t.Fatalf("error reverting migrations. Err: %v", err)
+	}
+
+	if version, err := migrations.Version(ctx, db); err != nil || version != 0 {
+		t.Fatalf("expected version 0 after reverting everything; got %d, %v", version, err)
+	}
+
+	if err := migrations.Up(ctx, db); err != nil {
+		t.Fatalf("error applying migrations again. Err: %v", err)
+	}
+
+	if version, err := migrations.Version(ctx, db); err != nil || version != latest {
+		t.Errorf("expected version %d; got %d, %v", latest, version, err)
+	}
+
+	if err := migrations.Check(ctx, db); err != nil {
+		t.Errorf("expected the migrated schema to be compatible; got %v", err)
+	}
+}
+
+// legacySchema is init.sql as it was before migrations, which databases
+// created back then still have.
+const legacySchema = `
+	CREATE TABLE category (id SERIAL PRIMARY KEY, name TEXT);
+	INSERT INTO category (id, name) VALUES (1, 'Pizzas');
+	CREATE TABLE recipe (
+		id SERIAL PRIMARY KEY, name TEXT, description TEXT, imageUrl TEXT,
+		category_id INTEGER, CONSTRAINT fk_category FOREIGN KEY(category_id) REFERENCES category(id)
+	);
+	CREATE TABLE ingredient(id SERIAL PRIMARY KEY, name TEXT, amount TEXT, imageUrl TEXT, isAvailable BOOLEAN);
+	CREATE TABLE ingredient_recipe (
+		id SERIAL PRIMARY KEY, ingredient_id INTEGER, recipe_id INTEGER,
+		CONSTRAINT fk_ingredient FOREIGN KEY (ingredient_id) REFERENCES ingredient(id),
+		CONSTRAINT fk_recipe FOREIGN KEY (recipe_id) REFERENCES recipe(id)
+	);
+	INSERT INTO recipe (name, description, category_id) VALUES ('Margherita', 'Clássica', 1);
+`
+
+// TestMigrateLegacySchema migrates a database created from the old init.sql
+// and checks the recipes kept there can be read with the current queries.
+func TestMigrateLegacySchema(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := pgxpool.New(ctx, testhelpers.BareSchema(t))
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(ctx, legacySchema); err != nil {
+		t.Fatalf("error loading legacy schema. Err: %v", err)
+	}
+
+	if err := migrations.Up(ctx, db); err != nil {
+		t.Fatalf("error migrating legacy schema. Err: %v", err)
+	}
+
+	var name string
+	var longDescription *string
+	if err := db.QueryRow(ctx, `SELECT name, long_description FROM recipe WHERE uuid IS NOT NULL`).Scan(&name, &longDescription); err != nil {
+		t.Fatalf("error reading migrated recipe. Err: %v", err)
+	}
+
+	if name != "Margherita" || longDescription != nil {
+		t.Errorf("expected the legacy recipe without a long description; got %q, %v", name, longDescription)
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/models"
+	"testing"
+)
+
+func TestNullableFieldsAreOmitted(t *testing.T) {
+	body, err := json.Marshal(models.NewRecipeDto(models.Recipe{Id: 1, Name: "Pão de queijo", Description: "Mineiro"}))
+	if err != nil {
+		t.Fatalf("error marshalling recipe. Err: %v", err)
+	}
+
+	expected := `{"id":1,"name":"Pão de queijo","description":"Mineiro"}`
+	if string(body) != expected {
+		t.Errorf("expected %s; got %s", expected, body)
+	}
+
+	url := "https://example.com/ovo.jpg"
+	body, err = json.Marshal(models.NewIngredientDto(models.Ingedient{Id: 2, Name: "Ovo", Url: &url}))
+	if err != nil {
+		t.Fatalf("error marshalling ingredient. Err: %v", err)
+	}
+
+	expected = `{"id":2,"name":"Ovo","image_url":"https://example.com/ovo.jpg","is_available":false}`
+	if string(body) != expected {
+		t.Errorf("expected %s; got %s", expected, body)
+	}
+}
+
+const nullFixturesSQL = `
+	INSERT INTO recipe (id, name, description, long_description, imageurl, category_id)
+		VALUES (1, 'Arroz', 'Branco', NULL, NULL, NULL);
+	INSERT INTO ingredient (id, name, amount, imageurl, isavailable)
+		VALUES (1, 'Sal', NULL, NULL, NULL);
+	INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES (1, 1);
+`
+
+// TestNullableColumns reads rows whose optional columns are NULL through the
+// database service.
+func TestNullableColumns(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	fixtures, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer fixtures.Close()
+
+	if _, err := fixtures.Exec(nullFixturesSQL); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	recipe, err := db.GetRecipeWithIngredients(ctx, 1)
+	if err != nil {
+		t.Fatalf("error reading recipe with NULL columns. Err: %v", err)
+	}
+	if recipe.Recipe.LongDescription != nil || recipe.Recipe.Url != nil || recipe.Recipe.CategoryId != nil {
+		t.Errorf("expected NULL columns to be nil; got %+v", recipe.Recipe)
+	}
+	if len(recipe.Ingredients) != 1 || recipe.Ingredients[0].Amount != nil || recipe.Ingredients[0].IsAvailable {
+		t.Errorf("expected one ingredient with NULL columns; got %+v", recipe.Ingredients)
+	}
+
+	if _, err := db.GetRecipes(ctx, models.RecipeFilter{}); err != nil {
+		t.Errorf("error listing recipes with NULL columns. Err: %v", err)
+	}
+
+	if _, err := db.GetIngredients(ctx, nil); err != nil {
+		t.Errorf("error listing ingredients with NULL columns. Err: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"testing"
+)
+
+func TestNutritionOf(t *testing.T) {
+	flour, eggs, unit := 200.0, 2.0, "g"
+
+	nutrition := models.NutritionOf([]models.Ingedient{
+		{Id: 1, Name: "Farinha", Quantity: &flour, Unit: &unit, Nutrition: &models.Nutrition{Calories: 3.64, Protein: 0.1, Fat: 0.01, Carbs: 0.76}},
+		{Id: 2, Name: "Ovo", Quantity: &eggs, Nutrition: &models.Nutrition{Calories: 72, Protein: 6.3, Fat: 4.8, Carbs: 0.4}},
+		{Id: 3, Name: "Sal", Nutrition: &models.Nutrition{}},
+		{Id: 4, Name: "Fermento", Quantity: &eggs},
+	})
+
+	want := models.Nutrition{Calories: 872, Protein: 32.6, Fat: 11.6, Carbs: 152.8}
+	got := nutrition.Total
+
+	for name, pair := range map[string][2]float64{
+		"calories": {want.Calories, got.Calories},
+		"protein":  {want.Protein, got.Protein},
+		"fat":      {want.Fat, got.Fat},
+		"carbs":    {want.Carbs, got.Carbs},
+	} {
+		if diff := pair[0] - pair[1]; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("expected %v %s; got %v", pair[0], name, pair[1])
+		}
+	}
+
+	if len(nutrition.Missing) != 2 || nutrition.Missing[0].Id != 3 || nutrition.Missing[1].Id != 4 {
+		t.Errorf("expected the ingredients without a quantity or nutrition to be missing; got %+v", nutrition.Missing)
+	}
+
+	if dto := models.NewRecipeNutritionDto(nutrition); dto.Complete {
+		t.Error("expected the nutrition to be incomplete")
+	}
+}
+
+func TestIngredientNutritionValidation(t *testing.T) {
+	negative := -1.0
+
+	err := models.IngredientInputDto{Name: "Farinha", Quantity: &negative, Nutrition: &models.NutritionDto{Fat: -2}}.Validate()
+
+	var errs models.ValidationErrors
+	if !errors.As(err, &errs) || errs["quantity"] == "" || errs["nutrition"] == "" {
+		t.Errorf("expected quantity and nutrition to be rejected; got %v", err)
+	}
+}
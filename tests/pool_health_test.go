@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/database"
+	"testing"
+)
+
+func TestEvaluatePool(t *testing.T) {
+	// A pool that has been up for long, with counters far above its size.
+	old := database.PoolSample{TotalConns: 10, MaxConns: 20, EmptyAcquires: 50_000, IdleClosed: 900, LifetimeClosed: 400}
+
+	cases := []struct {
+		name     string
+		previous database.PoolSample
+		current  database.PoolSample
+		status   string
+	}{
+		{"quiet since the last check", old, old, "up"},
+		{"a few closed since the last check", old, database.PoolSample{TotalConns: 10, MaxConns: 20, EmptyAcquires: 50_100, IdleClosed: 902, LifetimeClosed: 401}, "up"},
+		{"many idle closed since the last check", old, database.PoolSample{TotalConns: 10, MaxConns: 20, EmptyAcquires: 50_000, IdleClosed: 906, LifetimeClosed: 400}, "degraded"},
+		{"many closed at max lifetime since the last check", old, database.PoolSample{TotalConns: 10, MaxConns: 20, EmptyAcquires: 50_000, IdleClosed: 900, LifetimeClosed: 406}, "degraded"},
+		{"many waits since the last check", old, database.PoolSample{TotalConns: 10, MaxConns: 20, EmptyAcquires: 51_001, IdleClosed: 900, LifetimeClosed: 400}, "degraded"},
+		{"nearly full", old, database.PoolSample{TotalConns: 17, MaxConns: 20, EmptyAcquires: 50_000, IdleClosed: 900, LifetimeClosed: 400}, "degraded"},
+	}
+
+	for _, c := range cases {
+		if status, message := database.EvaluatePool(c.previous, c.current); status != c.status {
+			t.Errorf("%s: expected %s; got %s (%s)", c.name, c.status, status, message)
+		}
+	}
+}
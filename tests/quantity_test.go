@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"errors"
+	"gastro-galaxy-back/internal/migrations"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/quantity"
+	"strings"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		text string
+		want quantity.Amount
+		ok   bool
+	}{
+		{"2 xícaras", quantity.Amount{Quantity: 2, Unit: quantity.Cup}, true},
+		{"200g", quantity.Amount{Quantity: 200, Unit: quantity.Gram}, true},
+		{"1 ½ colher de sopa", quantity.Amount{Quantity: 1.5, Unit: quantity.Tablespoon}, true},
+		{"1/2 kg", quantity.Amount{Quantity: 0.5, Unit: quantity.Kilogram}, true},
+		{"0,5 L", quantity.Amount{Quantity: 0.5, Unit: quantity.Liter}, true},
+		{"1 dl", quantity.Amount{Quantity: 100, Unit: quantity.Milliliter}, true},
+		{"3", quantity.Amount{Quantity: 3, Unit: quantity.Piece}, true},
+		{"a gosto", quantity.Amount{}, false},
+		{"200-250 g", quantity.Amount{}, false},
+		{"", quantity.Amount{}, false},
+	}
+
+	for _, c := range cases {
+		got, ok := quantity.Parse(c.text)
+		if ok != c.ok || (ok && got != c.want) {
+			t.Errorf("Parse(%q): expected %+v, %v; got %+v, %v", c.text, c.want, c.ok, got, ok)
+		}
+	}
+}
+
+func TestIngredientInputParsesAmount(t *testing.T) {
+	input := models.IngredientInputDto{Name: "Farinha", Amount: "2 xícaras"}
+	input.ParseAmount()
+
+	if input.Quantity == nil || *input.Quantity != 2 || input.Unit != "cup" {
+		t.Errorf("expected 2 cup; got %v %q", input.Quantity, input.Unit)
+	}
+
+	half := 0.5
+	input = models.IngredientInputDto{Name: "Farinha", Amount: "2 xícaras", Quantity: &half, Unit: "KG"}
+	input.ParseAmount()
+
+	if *input.Quantity != 0.5 || input.Unit != "kg" {
+		t.Errorf("expected the sent amount to be kept; got %v %q", *input.Quantity, input.Unit)
+	}
+}
+
+func TestIngredientInputRejectsUnknownUnit(t *testing.T) {
+	one := 1.0
+
+	for _, input := range []models.IngredientInputDto{
+		{Name: "Farinha", Quantity: &one, Unit: "handful"},
+		{Name: "Farinha", Quantity: &one},
+		{Name: "Farinha", Unit: "g"},
+	} {
+		var errs models.ValidationErrors
+		if err := input.Validate(); !errors.As(err, &errs) || errs["unit"] == "" {
+			t.Errorf("expected a unit error for %v %q; got %v", input.Quantity, input.Unit, err)
+		}
+	}
+}
+
+// TestUnitsMatchSchema keeps the units the API accepts in step with the
+// ones the database allows.
+func TestUnitsMatchSchema(t *testing.T) {
+	all, err := migrations.All()
+	if err != nil {
+		t.Fatalf("error loading migrations. Err: %v", err)
+	}
+
+	var up string
+	for _, m := range all {
+		if m.Name == "ingredient_units" {
+			up = m.Up
+		}
+	}
+
+	if up == "" {
+		t.Fatal("expected the ingredient_units migration")
+	}
+
+	for _, unit := range quantity.Units {
+		if !strings.Contains(up, "'"+string(unit)+"'") {
+			t.Errorf("expected the schema to allow unit %q", unit)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"database/sql"
+	"encoding/json"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Tables big enough in production that a sequential scan on them is a regression.
+var largeTables = map[string]bool{
+	"recipe":            true,
+	"ingredient":        true,
+	"ingredient_recipe": true,
+}
+
+const seedSQL = `
+	INSERT INTO category (id, name) SELECT g, 'Category ' || g FROM generate_series(6, 500) g;
+	INSERT INTO recipe (name, description, long_description, imageurl, category_id)
+		SELECT 'Recipe ' || g, 'Description ' || g, 'Long description ' || g, 'https://example.com/' || g || '.jpg', g % 500 + 1
+		FROM generate_series(1, 20000) g;
+	INSERT INTO ingredient (name, amount, imageurl, isavailable)
+		SELECT 'Ingredient ' || g, g || ' g', 'https://example.com/i' || g || '.jpg', g % 2 = 0
+		FROM generate_series(1, 2000) g;
+	INSERT INTO ingredient_recipe (ingredient_id, recipe_id)
+		SELECT (g * 7) % 2000 + 1, g % 20000 + 1 FROM generate_series(1, 100000) g;
+	ANALYZE;
+`
+
+// TestQueryPlans EXPLAINs the key queries against a seeded database and fails
+// when any of them falls back to a sequential scan on a large table.
+func TestQueryPlans(t *testing.T) {
+	db, err := sql.Open("pgx", testhelpers.Schema(t))
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(seedSQL); err != nil {
+		t.Fatalf("error seeding test schema. Err: %v", err)
+	}
+
+	for _, q := range database.KeyQueries() {
+		t.Run(q.Name, func(t *testing.T) {
+			var raw []byte
+			if err := db.QueryRow("EXPLAIN (FORMAT JSON) "+q.SQL, q.Args...).Scan(&raw); err != nil {
+				t.Fatalf("error explaining query. Err: %v", err)
+			}
+
+			var plans []struct {
+				Plan planNode
+			}
+			if err := json.Unmarshal(raw, &plans); err != nil {
+				t.Fatalf("error decoding plan. Err: %v", err)
+			}
+
+			for _, plan := range plans {
+				if table, ok := findSeqScan(plan.Plan); ok {
+					t.Errorf("sequential scan on %s in plan:\n%s", table, raw)
+				}
+			}
+		})
+	}
+}
+
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	Plans        []planNode `json:"Plans"`
+}
+
+func findSeqScan(node planNode) (string, bool) {
+	if node.NodeType == "Seq Scan" && largeTables[node.RelationName] {
+		return node.RelationName, true
+	}
+	for _, child := range node.Plans {
+		if table, ok := findSeqScan(child); ok {
+			return table, true
+		}
+	}
+	return "", false
+}
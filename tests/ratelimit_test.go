@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/ratelimit"
+	"testing"
+	"time"
+)
+
+func TestLimiterAllowsNPerWindow(t *testing.T) {
+	limiter := ratelimit.New(2, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("203.0.113.7"); !ok {
+			t.Fatalf("expected event %d to be allowed", i+1)
+		}
+	}
+
+	ok, retryAfter := limiter.Allow("203.0.113.7")
+	if ok {
+		t.Error("expected the third event in the window to be refused")
+	}
+	if retryAfter <= 0 || retryAfter > time.Hour {
+		t.Errorf("expected a retry within the hour; got %s", retryAfter)
+	}
+
+	if ok, _ := limiter.Allow("198.51.100.2"); !ok {
+		t.Error("expected other clients to have their own limit")
+	}
+}
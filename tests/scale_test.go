@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"errors"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/service"
+	"testing"
+)
+
+func TestScaleRecipe(t *testing.T) {
+	flour, eggs, gram, piece, salt := 200.0, 3.0, "g", "piece", "a gosto"
+
+	recipe := models.RecipeWithIngredients{
+		Recipe: models.Recipe{Id: 1, Name: "Pão"},
+		Ingredients: []models.Ingedient{
+			{Id: 1, Name: "Farinha", Quantity: &flour, Unit: &gram, Nutrition: &models.Nutrition{Calories: 3.64}},
+			{Id: 2, Name: "Ovo", Quantity: &eggs, Unit: &piece},
+			{Id: 3, Name: "Sal", Amount: &salt},
+		},
+	}
+
+	scaled, err := service.ScaleRecipe(recipe, 1.5)
+	if err != nil {
+		t.Fatalf("error scaling recipe. Err: %v", err)
+	}
+
+	if q := *scaled.Ingredients[0].Quantity; q != 300 {
+		t.Errorf("expected 300 g of flour; got %v", q)
+	}
+	if a := *scaled.Ingredients[1].Amount; a != "4.5" {
+		t.Errorf("expected the eggs amount to read 4.5; got %q", a)
+	}
+	if a := *scaled.Ingredients[0].Amount; a != "300 g" {
+		t.Errorf("expected the flour amount to read 300 g; got %q", a)
+	}
+	if a := *scaled.Ingredients[2].Amount; a != salt || scaled.Ingredients[2].Quantity != nil {
+		t.Errorf("expected the salt to be left alone; got %q", a)
+	}
+	if c := scaled.Nutrition.Total.Calories; c < 1091.99 || c > 1092.01 {
+		t.Errorf("expected 1092 calories; got %v", c)
+	}
+	if flour != 200 {
+		t.Error("expected the original recipe to be left unchanged")
+	}
+
+	for _, factor := range []float64{0, -1, 101} {
+		if _, err := service.ScaleRecipe(recipe, factor); !errors.Is(err, service.ErrInvalidFactor) {
+			t.Errorf("expected ErrInvalidFactor for %v; got %v", factor, err)
+		}
+	}
+}
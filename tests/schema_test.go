@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TestSchemaConstraints checks the constraints the database service relies
+// on to report unknown references and to clean up after deletes.
+func TestSchemaConstraints(t *testing.T) {
+	tx := testhelpers.Tx(t)
+	ctx := context.Background()
+
+	var recipeId, ingredientId, listId int
+
+	if err := tx.QueryRow(ctx, `INSERT INTO recipe (name, description, category_id) VALUES ('Feijoada', 'Completa', 5) RETURNING id`).Scan(&recipeId); err != nil {
+		t.Fatalf("error inserting recipe. Err: %v", err)
+	}
+
+	if err := tx.QueryRow(ctx, `INSERT INTO ingredient (name, isavailable) VALUES ('Feijão preto', false) RETURNING id`).Scan(&ingredientId); err != nil {
+		t.Fatalf("error inserting ingredient. Err: %v", err)
+	}
+
+	// The failing insert runs in a savepoint, so the transaction stays usable.
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		t.Fatalf("error creating savepoint. Err: %v", err)
+	}
+
+	_, err = savepoint.Exec(ctx, `INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES ($1, $2)`, ingredientId+1000, recipeId)
+	if pgErr := (*pgconn.PgError)(nil); !errors.As(err, &pgErr) || pgErr.Code != "23503" {
+		t.Errorf("expected a foreign key violation linking an unknown ingredient; got %v", err)
+	}
+	savepoint.Rollback(ctx)
+
+	for _, stmt := range []string{
+		`INSERT INTO tag_suggestion (recipe_id, tag, reason) VALUES ($1, 'spicy', 'pimenta')`,
+		`INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES ($2, $1)`,
+	} {
+		if _, err := tx.Exec(ctx, stmt, recipeId, ingredientId); err != nil {
+			t.Fatalf("error loading fixtures. Err: %v", err)
+		}
+	}
+
+	if err := tx.QueryRow(ctx, `INSERT INTO shopping_list (recipe_ids) VALUES (ARRAY[$1::int]) RETURNING id`, recipeId).Scan(&listId); err != nil {
+		t.Fatalf("error inserting shopping list. Err: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `INSERT INTO shopping_list_item (shopping_list_id, ingredient_id, name, recipe_ids) VALUES ($1, $2, 'Feijão preto', ARRAY[$3::int])`, listId, ingredientId, recipeId); err != nil {
+		t.Fatalf("error inserting shopping list item. Err: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM ingredient_recipe WHERE recipe_id = $1`, recipeId); err != nil {
+		t.Fatalf("error unlinking recipe. Err: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recipe WHERE id = $1`, recipeId); err != nil {
+		t.Fatalf("error deleting recipe. Err: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM ingredient WHERE id = $1`, ingredientId); err != nil {
+		t.Fatalf("error deleting ingredient. Err: %v", err)
+	}
+
+	var suggestions int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM tag_suggestion WHERE recipe_id = $1`, recipeId).Scan(&suggestions); err != nil || suggestions != 0 {
+		t.Errorf("expected the tag suggestions to go with their recipe; got %d, %v", suggestions, err)
+	}
+
+	var itemIngredient *int
+	if err := tx.QueryRow(ctx, `SELECT ingredient_id FROM shopping_list_item WHERE shopping_list_id = $1`, listId).Scan(&itemIngredient); err != nil || itemIngredient != nil {
+		t.Errorf("expected the shopping list item to keep its copy without the ingredient; got %v, %v", itemIngredient, err)
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/service"
+	"testing"
+)
+
+// recipeStore keeps recipes in memory for the service tests.
+type recipeStore struct {
+	service.RecipeStore
+	recipes map[int]*models.RecipeWithIngredients
+	updated []int
+}
+
+func (s *recipeStore) GetRecipeWithIngredients(ctx context.Context, id int) (*models.RecipeWithIngredients, error) {
+	return s.recipes[id], nil
+}
+
+func (s *recipeStore) UpdateRecipe(ctx context.Context, id int, name string, description string, url string, ingredientIds []int) error {
+	s.updated = append(s.updated, id)
+	s.recipes[id].Recipe.Name = name
+	return nil
+}
+
+func TestRecipeServiceUpdate(t *testing.T) {
+	store := &recipeStore{recipes: map[int]*models.RecipeWithIngredients{
+		1: {Recipe: models.Recipe{Id: 1, Name: "Bolo"}},
+	}}
+
+	masked := func(fields ...*string) error {
+		for _, field := range fields {
+			if *field == "palavrão" {
+				*field = "********"
+			}
+		}
+		return nil
+	}
+
+	recipes := service.NewRecipeService(store, masked)
+	ctx := context.Background()
+
+	input := models.RecipeInputDto{Name: "palavrão", IngedientIds: models.IDs{1}}
+
+	if err := recipes.Update(ctx, 2, input); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing recipe; got %v", err)
+	}
+
+	var invalid models.ValidationErrors
+	if err := recipes.Update(ctx, 1, models.RecipeInputDto{IngedientIds: models.IDs{1}}); !errors.As(err, &invalid) {
+		t.Errorf("expected validation errors; got %v", err)
+	}
+
+	if len(store.updated) != 0 {
+		t.Fatalf("expected nothing to be written; got updates of %v", store.updated)
+	}
+
+	if err := recipes.Update(ctx, 1, input); err != nil {
+		t.Fatalf("error updating recipe. Err: %v", err)
+	}
+
+	if name := store.recipes[1].Recipe.Name; name != "********" {
+		t.Errorf("expected the checked name to be stored; got %q", name)
+	}
+}
+
+type categoryStore struct {
+	database.CategoryRepository
+	names []string
+}
+
+func (s *categoryStore) InsertCategory(ctx context.Context, name string) (int, error) {
+	s.names = append(s.names, name)
+	return len(s.names), nil
+}
+
+func TestCategoryServiceCreate(t *testing.T) {
+	store := &categoryStore{}
+	categories := service.NewCategoryService(store, nil)
+
+	if _, err := categories.Create(context.Background(), models.CategoryInputDto{Name: "   "}); err == nil {
+		t.Error("expected a blank name to be refused")
+	}
+
+	id, err := categories.Create(context.Background(), models.CategoryInputDto{Name: "  Massas "})
+	if err != nil || id != 1 || store.names[0] != "Massas" {
+		t.Errorf("expected Massas to be stored trimmed as 1; got %d %v %v", id, store.names, err)
+	}
+}
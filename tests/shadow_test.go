@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/shadow"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShadowMirrorsReads(t *testing.T) {
+	mirrored := make(chan *http.Request, 10)
+	canary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrored <- r
+	}))
+	defer canary.Close()
+
+	mirror, err := shadow.New(canary.URL+"/", 100)
+	if err != nil {
+		t.Fatalf("error creating mirror. Err: %v", err)
+	}
+
+	handler := mirror.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	post := httptest.NewRequest(http.MethodPost, "/v1/recipe", strings.NewReader("{}"))
+	handler.ServeHTTP(httptest.NewRecorder(), post)
+
+	get := httptest.NewRequest(http.MethodGet, "/v1/recipes?page=2", nil)
+	get.Header.Set("Authorization", "Bearer secret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, get)
+
+	if recorder.Code != http.StatusTeapot {
+		t.Errorf("expected the primary to answer; got %d", recorder.Code)
+	}
+
+	select {
+	case r := <-mirrored:
+		if r.Method != http.MethodGet || r.URL.RequestURI() != "/v1/recipes?page=2" {
+			t.Errorf("expected the GET mirrored; got %s %s", r.Method, r.URL.RequestURI())
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected credentials to stay on the primary")
+		}
+		if r.Header.Get(shadow.Header) == "" {
+			t.Errorf("expected the %s header on mirrored requests", shadow.Header)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the GET to be mirrored")
+	}
+
+	select {
+	case r := <-mirrored:
+		t.Errorf("expected only reads mirrored; got %s %s", r.Method, r.URL.RequestURI())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/models"
+	"gastro-galaxy-back/internal/server"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestShoppingList checks that a list merges the ingredients shared by its
+// recipes and leaves out the available ones.
+func TestShoppingList(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	fixtures := `
+		INSERT INTO ingredient (id, name, amount, isavailable) VALUES (101, 'Tomate', '2 un', false), (102, 'Sal', NULL, true), (103, 'Alface', '1 pé', NULL);
+		INSERT INTO recipe (id, name, description, category_id) VALUES (101, 'Molho', '', 1), (102, 'Salada', '', 1);
+		INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES (101, 101), (102, 101), (101, 102), (102, 102), (103, 102);
+	`
+	if _, err := conn.Exec(fixtures); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	if _, err := db.CreateShoppingList(ctx, []int{101, 999}); !errors.Is(err, database.ErrUnknownReference) {
+		t.Fatalf("expected ErrUnknownReference for an unknown recipe; got %v", err)
+	}
+
+	id, err := db.CreateShoppingList(ctx, []int{102, 101, 101})
+	if err != nil {
+		t.Fatalf("error creating shopping list. Err: %v", err)
+	}
+
+	list, err := db.GetShoppingList(ctx, id)
+	if err != nil {
+		t.Fatalf("error reading shopping list. Err: %v", err)
+	}
+
+	var names []string
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"Alface", "Tomate"}) {
+		t.Fatalf("expected Alface and Tomate, without the available Sal; got %v", names)
+	}
+	if !reflect.DeepEqual(list.Items[1].RecipeIds, []int{101, 102}) {
+		t.Errorf("expected Tomate listed once for both recipes; got %v", list.Items[1].RecipeIds)
+	}
+
+	item, err := db.CheckShoppingListItem(ctx, id, list.Items[0].Id, nil)
+	if err != nil || !item.Checked {
+		t.Errorf("expected the item flipped to checked; got %+v, %v", item, err)
+	}
+
+	if err := db.DeleteShoppingList(ctx, id); err != nil {
+		t.Fatalf("error deleting shopping list. Err: %v", err)
+	}
+	if _, err := db.GetShoppingList(ctx, id); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after deleting; got %v", err)
+	}
+}
+
+// TestShoppingListHandlers checks that lists are only reachable by the
+// uuid given when they are made, not by a guessable serial id.
+func TestShoppingListHandlers(t *testing.T) {
+	t.Setenv("DB_DRIVER", "memory")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, httpServer := server.NewServer(ctx)
+	ts := httptest.NewServer(httpServer.Handler)
+	defer ts.Close()
+
+	ingredient := postCreated(t, ts.URL+"/v1/ingredient", `{"name": "Tomate"}`)
+	recipe := postCreated(t, ts.URL+"/v1/recipe", fmt.Sprintf(`{"name": "Molho", "category_id": 3, "ingredient_ids": [%d]}`, ingredient))
+
+	resp, err := http.Post(ts.URL+"/v1/shopping-list", "application/json", strings.NewReader(fmt.Sprintf(`{"recipe_ids": [%d]}`, recipe)))
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var list models.ShoppingListDto
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("error decoding response body. Err: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated || list.Uuid == "" || len(list.Items) != 1 {
+		t.Fatalf("expected the created list with its uuid; got %v %+v", resp.Status, list)
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		status int
+	}{
+		{"get by serial id", http.MethodGet, "/v1/shopping-list/1", http.StatusBadRequest},
+		{"check item by serial id", http.MethodPatch, fmt.Sprintf("/v1/shopping-list/1/item/%d", list.Items[0].Id), http.StatusBadRequest},
+		{"delete by serial id", http.MethodDelete, "/v1/shopping-list/1", http.StatusBadRequest},
+		{"get unknown uuid", http.MethodGet, "/v1/shopping-list/01890a5d-ac96-774b-bcce-b302099a8057", http.StatusNotFound},
+		{"get by uuid", http.MethodGet, "/v1/shopping-list/" + list.Uuid, http.StatusOK},
+		{"check item by uuid", http.MethodPatch, fmt.Sprintf("/v1/shopping-list/%s/item/%d", list.Uuid, list.Items[0].Id), http.StatusOK},
+		{"delete by uuid", http.MethodDelete, "/v1/shopping-list/" + list.Uuid, http.StatusNoContent},
+		{"get deleted", http.MethodGet, "/v1/shopping-list/" + list.Uuid, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, ts.URL+tt.path, nil)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error making request to server. Err: %v", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: expected status %d; got %v", tt.name, tt.status, resp.Status)
+		}
+	}
+}
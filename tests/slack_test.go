@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gastro-galaxy-back/internal/server"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const slackTestSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// slackSign returns the X-Slack-Signature of body sent at timestamp.
+func slackSign(secret string, timestamp int64, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:%s", timestamp, body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// TestSlackCommand checks that /slack/commands only answers requests signed
+// with SLACK_SIGNING_SECRET, and that the command text picks recipes by tag.
+func TestSlackCommand(t *testing.T) {
+	t.Setenv("DB_DRIVER", "memory")
+	t.Setenv("SLACK_SIGNING_SECRET", slackTestSecret)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, httpServer := server.NewServer(ctx)
+	ts := httptest.NewServer(httpServer.Handler)
+	defer ts.Close()
+
+	ingredient := postCreated(t, ts.URL+"/v1/ingredient", `{"name": "Banana"}`)
+	vegan := postCreated(t, ts.URL+"/v1/recipe", fmt.Sprintf(`{"name": "Moqueca de Banana", "category_id": 5, "ingredient_ids": [%d]}`, ingredient))
+	postCreated(t, ts.URL+"/v1/recipe", fmt.Sprintf(`{"name": "Feijoada", "category_id": 5, "ingredient_ids": [%d]}`, ingredient))
+
+	resp, err := http.Post(fmt.Sprintf("%s/v1/recipe/%d/tags", ts.URL, vegan), "application/json", strings.NewReader(`{"tags": ["vegan"]}`))
+	if err != nil {
+		t.Fatalf("error making request to server. Err: %v", err)
+	}
+	resp.Body.Close()
+
+	now := time.Now().Unix()
+	body := "command=%2Flunch&text=vegan"
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		status    int
+	}{
+		{"valid", strconv.FormatInt(now, 10), slackSign(slackTestSecret, now, body), http.StatusOK},
+		{"tampered", strconv.FormatInt(now, 10), slackSign(slackTestSecret, now, "command=%2Flunch&text=meat"), http.StatusUnauthorized},
+		{"wrong secret", strconv.FormatInt(now, 10), slackSign("not the secret", now, body), http.StatusUnauthorized},
+		{"stale", strconv.FormatInt(now-600, 10), slackSign(slackTestSecret, now-600, body), http.StatusUnauthorized},
+		{"missing signature", strconv.FormatInt(now, 10), "", http.StatusUnauthorized},
+		{"missing timestamp", "", slackSign(slackTestSecret, now, body), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodPost, ts.URL+"/v1/slack/commands", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.timestamp != "" {
+				req.Header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+			}
+			if tt.signature != "" {
+				req.Header.Set("X-Slack-Signature", tt.signature)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("error making request to server. Err: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("expected status %d; got %v", tt.status, resp.Status)
+			}
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var message struct {
+				Text string `json:"text"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+				t.Fatalf("error decoding response body. Err: %v", err)
+			}
+
+			if message.Text != "Lunch idea: Moqueca de Banana" {
+				t.Errorf("expected the only vegan recipe; got %q", message.Text)
+			}
+		})
+	}
+}
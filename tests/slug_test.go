@@ -0,0 +1,22 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/models"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	cases := map[string]string{
+		"Pão de Queijo":        "pao-de-queijo",
+		"  Bolo de Fubá (2x) ": "bolo-de-fuba-2x",
+		"Moqueca — Baiana!":    "moqueca-baiana",
+		"Crème brûlée":         "creme-brulee",
+		"":                     "",
+	}
+
+	for name, want := range cases {
+		if got := models.Slug(name); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
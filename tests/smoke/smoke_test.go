@@ -0,0 +1,181 @@
+//go:build smoke
+
+// Package smoke checks a deployed API after a release. Run it with
+//
+//	SMOKE_BASE_URL=https://api.example.com go test -tags=smoke ./tests/smoke
+//
+// The checks are read-only unless SMOKE_WRITE=true, which also creates a
+// category, an ingredient and a recipe, reads them back and deletes them.
+package smoke
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+func baseURL(t *testing.T) string {
+	t.Helper()
+
+	base := strings.TrimSuffix(os.Getenv("SMOKE_BASE_URL"), "/")
+	if base == "" {
+		t.Fatal("SMOKE_BASE_URL must point to the deployment to check")
+	}
+	return base
+}
+
+// call sends a request and decodes the JSON answer into out, if not nil.
+// It fails the test unless the status is expected.
+func call(t *testing.T, method, url string, body any, expected int, out any) {
+	t.Helper()
+
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("error encoding request body. Err: %v", err)
+		}
+		payload = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, payload)
+	if err != nil {
+		t.Fatalf("error building request. Err: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed. Err: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error reading response body. Err: %v", err)
+	}
+
+	if resp.StatusCode != expected {
+		t.Fatalf("%s %s: expected status %d; got %s: %s", method, url, expected, resp.Status, data)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: error decoding response. Err: %v: %s", method, url, err, data)
+		}
+	}
+}
+
+type page struct {
+	Items []struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"items"`
+	Total int `json:"total"`
+}
+
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func TestReadPath(t *testing.T) {
+	base := baseURL(t)
+
+	call(t, http.MethodGet, base+"/v1/health/live", nil, http.StatusOK, nil)
+	call(t, http.MethodGet, base+"/v1/health/ready", nil, http.StatusOK, nil)
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+	}
+	call(t, http.MethodGet, base+"/openapi.json", nil, http.StatusOK, &spec)
+	if spec.OpenAPI == "" {
+		t.Error("expected /openapi.json to serve the spec")
+	}
+
+	var categories []struct {
+		Id   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	call(t, http.MethodGet, base+"/v1/categories", nil, http.StatusOK, &categories)
+
+	var ingredients page
+	call(t, http.MethodGet, base+"/v1/ingredients?page=1&page_size=5", nil, http.StatusOK, &ingredients)
+
+	var recipes page
+	call(t, http.MethodGet, base+"/v1/recipes?page=1&page_size=5", nil, http.StatusOK, &recipes)
+
+	call(t, http.MethodGet, base+"/v1/recipes/search?q=a", nil, http.StatusOK, nil)
+	call(t, http.MethodGet, base+"/v1/home", nil, http.StatusOK, nil)
+
+	if len(recipes.Items) > 0 {
+		call(t, http.MethodGet, fmt.Sprintf("%s/v1/recipe/%d", base, recipes.Items[0].Id), nil, http.StatusOK, nil)
+	}
+
+	var notFound apiError
+	call(t, http.MethodGet, base+"/v1/recipe/2147483647", nil, http.StatusNotFound, &notFound)
+	if notFound.Code == "" {
+		t.Error("expected errors in the JSON error envelope")
+	}
+}
+
+func TestWritePath(t *testing.T) {
+	if os.Getenv("SMOKE_WRITE") != "true" {
+		t.Skip("SMOKE_WRITE not set to true")
+	}
+
+	base := baseURL(t)
+	name := fmt.Sprintf("smoke test %d", time.Now().UnixNano())
+
+	var created struct {
+		Id int `json:"id"`
+	}
+
+	call(t, http.MethodPost, base+"/v1/category", map[string]string{"name": name}, http.StatusCreated, &created)
+	categoryId := created.Id
+	t.Cleanup(func() {
+		call(t, http.MethodDelete, fmt.Sprintf("%s/v1/category/%d", base, categoryId), nil, http.StatusNoContent, nil)
+	})
+
+	call(t, http.MethodPost, base+"/v1/ingredient", map[string]any{"name": name, "amount": "1 un"}, http.StatusCreated, &created)
+	ingredientId := created.Id
+	t.Cleanup(func() {
+		call(t, http.MethodDelete, fmt.Sprintf("%s/v1/ingredient/%d", base, ingredientId), nil, http.StatusNoContent, nil)
+	})
+
+	recipe := map[string]any{
+		"name":           name,
+		"description":    "Created by the smoke tests, deleted right after.",
+		"category_id":    categoryId,
+		"ingredient_ids": []int{ingredientId},
+	}
+	call(t, http.MethodPost, base+"/v1/recipe", recipe, http.StatusCreated, &created)
+	recipeId := created.Id
+	t.Cleanup(func() {
+		call(t, http.MethodDelete, fmt.Sprintf("%s/v1/recipe/%d", base, recipeId), nil, http.StatusNoContent, nil)
+	})
+
+	var read struct {
+		Recipe struct {
+			Name string `json:"name"`
+		} `json:"recipe"`
+		Ingredients []struct {
+			Id int `json:"id"`
+		} `json:"ingredients"`
+	}
+	call(t, http.MethodGet, fmt.Sprintf("%s/v1/recipe/%d", base, recipeId), nil, http.StatusOK, &read)
+
+	if read.Recipe.Name != name || len(read.Ingredients) != 1 || read.Ingredients[0].Id != ingredientId {
+		t.Errorf("expected the recipe to read back as written; got %+v", read)
+	}
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/memory"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/models"
+	"testing"
+)
+
+func TestRecipeSteps(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testRecipeSteps(t, memory.New())
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		db, err := database.Open(testhelpers.Schema(t))
+		if err != nil {
+			t.Fatalf("error opening database service. Err: %v", err)
+		}
+		defer db.Close()
+
+		testRecipeSteps(t, db)
+	})
+}
+
+// testRecipeSteps checks that steps are numbered in the order they are
+// added, renumbered when one is deleted or the recipe is reordered, and
+// returned with the recipe.
+func testRecipeSteps(t *testing.T, db database.Service) {
+	ctx := context.Background()
+
+	ingredient, _ := db.InsertIngredient(ctx, "Farinha", "", "", false, nil, "", nil)
+
+	recipe, err := db.InsertRecipe(ctx, "Pão", "", "", "", 1, []int{ingredient})
+	if err != nil {
+		t.Fatalf("error inserting recipe. Err: %v", err)
+	}
+
+	var ids []int
+	for _, text := range []string{"Misture", "Sove", "Descanse", "Asse"} {
+		id, err := db.InsertRecipeStep(ctx, models.RecipeStep{RecipeId: recipe, Text: text})
+		if err != nil {
+			t.Fatalf("error inserting step. Err: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if _, err := db.InsertRecipeStep(ctx, models.RecipeStep{RecipeId: 999, Text: "Asse"}); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for an unknown recipe; got %v", err)
+	}
+
+	if err := db.DeleteRecipeStep(ctx, recipe, ids[1]); err != nil {
+		t.Fatalf("error deleting step. Err: %v", err)
+	}
+
+	if err := db.ReorderRecipeSteps(ctx, recipe, []int{ids[3], ids[0]}); !errors.Is(err, database.ErrStepOrder) {
+		t.Errorf("expected ErrStepOrder when a step is left out; got %v", err)
+	}
+
+	if err := db.ReorderRecipeSteps(ctx, recipe, []int{ids[3], ids[0], ids[2]}); err != nil {
+		t.Fatalf("error reordering steps. Err: %v", err)
+	}
+
+	minutes := 40
+	if err := db.UpdateRecipeStep(ctx, models.RecipeStep{Id: ids[3], RecipeId: recipe, Text: "Asse a 180 °C", DurationMinutes: &minutes}); err != nil {
+		t.Fatalf("error updating step. Err: %v", err)
+	}
+
+	loaded, err := db.GetRecipeWithIngredients(ctx, recipe)
+	if err != nil {
+		t.Fatalf("error loading recipe. Err: %v", err)
+	}
+
+	var texts []string
+	for i, step := range loaded.Steps {
+		if step.Position != i+1 {
+			t.Errorf("expected step %q at position %d; got %d", step.Text, i+1, step.Position)
+		}
+		texts = append(texts, step.Text)
+	}
+
+	if len(texts) != 3 || texts[0] != "Asse a 180 °C" || texts[1] != "Misture" || texts[2] != "Descanse" {
+		t.Errorf("expected the steps in their new order; got %q", texts)
+	}
+
+	if *loaded.Steps[0].DurationMinutes != 40 {
+		t.Errorf("expected the updated duration; got %v", *loaded.Steps[0].DurationMinutes)
+	}
+}
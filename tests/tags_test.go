@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/memory"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"gastro-galaxy-back/internal/models"
+	"slices"
+	"testing"
+)
+
+func TestRecipeTags(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testRecipeTags(t, memory.New())
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		db, err := database.Open(testhelpers.Schema(t))
+		if err != nil {
+			t.Fatalf("error opening database service. Err: %v", err)
+		}
+		defer db.Close()
+
+		testRecipeTags(t, db)
+	})
+}
+
+// testRecipeTags checks the tag filters of the recipe lists, and that an
+// accepted tag suggestion tags its recipe.
+func testRecipeTags(t *testing.T, db database.Service) {
+	ctx := context.Background()
+
+	ingredient, _ := db.InsertIngredient(ctx, "Grão-de-bico", "", "", false, nil, "", nil)
+
+	var recipes []int
+	for _, name := range []string{"Homus", "Falafel", "Salada"} {
+		id, err := db.InsertRecipe(ctx, name, "", "", "", 5, []int{ingredient})
+		if err != nil {
+			t.Fatalf("error inserting recipe. Err: %v", err)
+		}
+		recipes = append(recipes, id)
+	}
+
+	db.AttachRecipeTags(ctx, recipes[0], []string{"vegan", "quick"})
+	db.AttachRecipeTags(ctx, recipes[1], []string{"vegan"})
+
+	if err := db.AttachRecipeTags(ctx, 999, []string{"vegan"}); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected ErrNotFound tagging an unknown recipe; got %v", err)
+	}
+
+	names := func(filter models.RecipeFilter) []string {
+		found, err := db.GetRecipes(ctx, filter)
+		if err != nil {
+			t.Fatalf("error listing recipes. Err: %v", err)
+		}
+
+		var names []string
+		for _, recipe := range found {
+			names = append(names, recipe.Name)
+		}
+		return names
+	}
+
+	if all := names(models.RecipeFilter{Tags: []string{"vegan", "quick"}}); !slices.Equal(all, []string{"Homus"}) {
+		t.Errorf("expected only the recipe with both tags; got %v", all)
+	}
+
+	if either := names(models.RecipeFilter{Tags: []string{"vegan", "quick"}, AnyTag: true}); !slices.Equal(either, []string{"Homus", "Falafel"}) {
+		t.Errorf("expected the recipes with either tag; got %v", either)
+	}
+
+	if none := names(models.RecipeFilter{Categories: []string{"Pizzas"}, Tags: []string{"vegan"}}); len(none) != 0 {
+		t.Errorf("expected the category and tag filters combined; got %v", none)
+	}
+
+	db.InsertTagSuggestions(ctx, recipes[2], []models.TagProposal{{Tag: "vegetarian", Reason: "no meat"}})
+
+	suggestions, _ := db.GetTagSuggestions(ctx, models.SuggestionPending)
+	if len(suggestions) != 1 {
+		t.Fatalf("expected one pending tag suggestion; got %+v", suggestions)
+	}
+
+	if err := db.ReviewTagSuggestion(ctx, suggestions[0].Id, models.SuggestionAccepted); err != nil {
+		t.Fatalf("error accepting tag suggestion. Err: %v", err)
+	}
+
+	if err := db.DetachRecipeTag(ctx, recipes[1], "vegan"); err != nil {
+		t.Fatalf("error removing tag. Err: %v", err)
+	}
+
+	if err := db.DetachRecipeTag(ctx, recipes[1], "vegan"); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("expected ErrNotFound removing a tag the recipe doesn't carry; got %v", err)
+	}
+
+	tags, _ := db.GetTags(ctx)
+	expected := []models.Tag{{Name: "quick", Recipes: 1}, {Name: "vegan", Recipes: 1}, {Name: "vegetarian", Recipes: 1}}
+	if !slices.Equal(tags, expected) {
+		t.Errorf("expected the tags in use; got %+v", tags)
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"gastro-galaxy-back/internal/database"
+	"gastro-galaxy-back/internal/database/testhelpers"
+	"testing"
+)
+
+// TestIngredientTaxonomy checks the one level hierarchy and that variants
+// of a base stand in for each other.
+func TestIngredientTaxonomy(t *testing.T) {
+	dsn := testhelpers.Schema(t)
+
+	conn, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("error connecting to test schema. Err: %v", err)
+	}
+	defer conn.Close()
+
+	fixtures := `
+		INSERT INTO ingredient (id, name, isavailable) VALUES
+			(1, 'Tomate', false), (2, 'Tomate cereja', false), (3, 'Tomate pelado', true), (4, 'Sal', false);
+		INSERT INTO recipe (id, name, description) VALUES (1, 'Salada', 'Fresca');
+		INSERT INTO ingredient_recipe (ingredient_id, recipe_id) VALUES (2, 1);
+	`
+	if _, err := conn.Exec(fixtures); err != nil {
+		t.Fatalf("error loading fixtures. Err: %v", err)
+	}
+
+	db, err := database.Open(dsn)
+	if err != nil {
+		t.Fatalf("error opening database service. Err: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	tomato, cherry, canned, salt, missing := 1, 2, 3, 4, 99
+
+	cookable, err := db.GetCookableRecipes(ctx, nil, 0)
+	if err != nil || len(cookable) != 0 {
+		t.Fatalf("expected nothing cookable before the taxonomy; got %+v, %v", cookable, err)
+	}
+
+	for _, variant := range []int{cherry, canned} {
+		if err := db.SetIngredientBase(ctx, variant, &tomato); err != nil {
+			t.Fatalf("error setting the base of %d. Err: %v", variant, err)
+		}
+	}
+
+	if err := db.SetIngredientBase(ctx, salt, &cherry); !errors.Is(err, database.ErrNestedVariant) {
+		t.Errorf("expected ErrNestedVariant for a variant as base; got %v", err)
+	}
+	if err := db.SetIngredientBase(ctx, tomato, &salt); !errors.Is(err, database.ErrNestedVariant) {
+		t.Errorf("expected ErrNestedVariant for a base with variants; got %v", err)
+	}
+	if err := db.SetIngredientBase(ctx, salt, &missing); !errors.Is(err, database.ErrUnknownReference) {
+		t.Errorf("expected ErrUnknownReference for an unknown base; got %v", err)
+	}
+
+	cookable, err = db.GetCookableRecipes(ctx, nil, 0)
+	if err != nil || len(cookable) != 1 {
+		t.Errorf("expected canned tomatoes to cover cherry tomatoes; got %+v, %v", cookable, err)
+	}
+
+	substitutes, err := db.GetIngredientSubstitutes(ctx, cherry)
+	if err != nil || len(substitutes) != 2 || substitutes[0].Id != canned || substitutes[1].Id != tomato {
+		t.Errorf("expected the canned and plain tomatoes, available first; got %+v, %v", substitutes, err)
+	}
+
+	recipes, err := db.GetRecipesUsingIngredient(ctx, tomato)
+	if err != nil || len(recipes) != 1 {
+		t.Errorf("expected the salad to use tomatoes; got %+v, %v", recipes, err)
+	}
+
+	if err := db.SetIngredientBase(ctx, cherry, nil); err != nil {
+		t.Fatalf("error clearing the base. Err: %v", err)
+	}
+
+	if recipes, err := db.GetRecipesUsingIngredient(ctx, tomato); err != nil || len(recipes) != 0 {
+		t.Errorf("expected no recipes once cherry tomatoes are a base again; got %+v, %v", recipes, err)
+	}
+}
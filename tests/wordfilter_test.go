@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"gastro-galaxy-back/internal/wordfilter"
+	"reflect"
+	"testing"
+)
+
+func TestWordFilter(t *testing.T) {
+	f := wordfilter.New([]string{"Darn", "heck"})
+
+	found := f.Find("Darn good pie, what the HECK. Darnish is fine")
+	expected := []string{"Darn", "HECK"}
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("expected matches %v; got %v", expected, found)
+	}
+
+	masked := f.Mask("Pão da heck!")
+	if masked != "Pão da ****!" {
+		t.Errorf("expected masked text to be %q; got %q", "Pão da ****!", masked)
+	}
+
+	f.Set(nil)
+	if found := f.Find("darn"); len(found) != 0 {
+		t.Errorf("expected no matches after clearing the list; got %v", found)
+	}
+}